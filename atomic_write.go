@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// File modes used for config files
+const (
+	DefaultFileMode os.FileMode = 0644
+	SecretFileMode  os.FileMode = 0600 // New files whose content looks like it holds secrets
+)
+
+// maxSymlinkDepth bounds symlink resolution to catch loops
+const maxSymlinkDepth = 40
+
+// secretPattern matches keys and values that usually hold credentials, such
+// as "API_TOKEN": or Authorization: Bearer ...
+var secretPattern = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[_-]?key|private[_-]?key|credential|authorization)[a-z0-9_-]*"?\s*[:=]|bearer\s+[a-z0-9._~+/-]+`)
+
+// WriteFileAtomic replaces the file at path with data by writing a temporary
+// file next to it and renaming it into place. If path is a symlink, the file
+// it points to is replaced and the link is kept. An existing file keeps its
+// mode and, where permitted, its ownership. New files get mode, or
+// SecretFileMode if the content looks like it contains secrets.
+func WriteFileAtomic(path string, data []byte, mode os.FileMode) error {
+	target, err := resolveSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	existing, statErr := os.Stat(target)
+	switch {
+	case statErr == nil:
+		mode = existing.Mode().Perm()
+	case ContainsSecrets(data):
+		mode = SecretFileMode
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmpPath)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		cleanup()
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if statErr == nil {
+		// Best effort: only root can give a file to another user
+		preserveOwner(tmp, existing)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+// ContainsSecrets reports whether data looks like it holds credentials
+func ContainsSecrets(data []byte) bool {
+	return secretPattern.Match(data)
+}
+
+// resolveSymlinks follows symlinks at path to the file they point to. The
+// final target doesn't need to exist.
+func resolveSymlinks(path string) (string, error) {
+	for i := 0; i < maxSymlinkDepth; i++ {
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return path, nil
+		}
+
+		link, err := os.Readlink(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read symlink: %w", err)
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(path), link)
+		}
+		path = link
+	}
+	return "", fmt.Errorf("too many levels of symlinks: %s", path)
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and symlinks differ on Windows")
+	}
+	tmpDir := t.TempDir()
+
+	// Writing through a symlink updates the real file and keeps the link
+	realDir := filepath.Join(tmpDir, "dotfiles")
+	if err := os.MkdirAll(realDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	realPath := filepath.Join(realDir, "mcp.json")
+	if err := os.WriteFile(realPath, []byte(`{}`), 0640); err != nil {
+		t.Fatal(err)
+	}
+	linkPath := filepath.Join(tmpDir, "mcp.json")
+	if err := os.Symlink(filepath.Join("dotfiles", "mcp.json"), linkPath); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := NewFileDestination("linked", linkPath, ExportFormatJSON)
+	if err := dest.Write([]byte(`{"mcpServers":{}}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("Expected the symlink to be kept")
+	}
+	data, err := os.ReadFile(realPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"mcpServers":{}}` {
+		t.Errorf("Expected the real file to be updated, got %s", data)
+	}
+
+	// The existing mode is kept
+	info, err = os.Stat(realPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("Expected mode 0640 to be kept, got %o", info.Mode().Perm())
+	}
+
+	// New files default to the given mode unless they look like they hold secrets
+	plainPath := filepath.Join(tmpDir, "plain.json")
+	if err := WriteFileAtomic(plainPath, []byte(`{"command":"server"}`), DefaultFileMode); err != nil {
+		t.Fatal(err)
+	}
+	secretPath := filepath.Join(tmpDir, "secret.json")
+	if err := WriteFileAtomic(secretPath, []byte(`{"env":{"GITHUB_TOKEN":"abc"}}`), DefaultFileMode); err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]os.FileMode{plainPath: DefaultFileMode, secretPath: SecretFileMode} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("Expected %s to have mode %o, got %o", filepath.Base(path), want, info.Mode().Perm())
+		}
+	}
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("Expected 4 entries, got %d", len(entries))
+	}
+}
@@ -46,14 +46,33 @@ type autoSyncManager struct {
 	watcher       *fileWatcher
 	debounceTimer *time.Timer
 	lastSync      time.Time
+	nextSync      time.Time
+	lastError     string
 	mu            sync.Mutex
 	wg            sync.WaitGroup
+
+	// Diagnostics exposed through GetStatus
+	watchedPaths   []string
+	eventsReceived int64
+	eventsIgnored  int64
+	syncsPerformed int64
+	destStatus     map[string]*DestinationSyncStatus
+
+	// Directory of the global config, which may also be a project directory
+	configDir string
+
+	// Registered projects with auto-sync enabled
+	projectDirs   map[string]string // watched directory -> project path
+	projectTimers map[string]*time.Timer
 }
 
 // newAutoSyncManager creates a new auto-sync manager
 func newAutoSyncManager(engine *engineImpl) *autoSyncManager {
 	return &autoSyncManager{
-		engine: engine,
+		engine:        engine,
+		destStatus:    make(map[string]*DestinationSyncStatus),
+		projectDirs:   make(map[string]string),
+		projectTimers: make(map[string]*time.Timer),
 	}
 }
 
@@ -92,6 +111,18 @@ func (asm *autoSyncManager) Start(config AutoSyncConfig) error {
 	asm.isRunning = true
 	asm.stopChan = make(chan struct{})
 
+	// Reset diagnostics for this run
+	asm.watchedPaths = nil
+	asm.eventsReceived = 0
+	asm.eventsIgnored = 0
+	asm.syncsPerformed = 0
+	asm.lastError = ""
+	asm.nextSync = time.Time{}
+	asm.destStatus = make(map[string]*DestinationSyncStatus)
+	asm.configDir = ""
+	asm.projectDirs = make(map[string]string)
+	asm.projectTimers = make(map[string]*time.Timer)
+
 	// Update and persist auto-sync settings in engine config
 	asm.engine.mu.Lock()
 	asm.engine.config.Settings.AutoSync.Enabled = true
@@ -111,7 +142,7 @@ func (asm *autoSyncManager) Start(config AutoSyncConfig) error {
 	configPath := asm.engine.configPath
 	if configPath == "" {
 		// Try to use storage path
-		if storage, ok := asm.engine.storage.(*FileStorage); ok {
+		if storage, ok := asm.engine.getStorage().(*FileStorage); ok {
 			configPath = filepath.Join(storage.GetBasePath(), "config.json")
 		}
 	}
@@ -124,16 +155,23 @@ func (asm *autoSyncManager) Start(config AutoSyncConfig) error {
 				asm.isRunning = false
 				return fmt.Errorf("failed to watch config file: %w", err)
 			}
+			asm.watchedPaths = append(asm.watchedPaths, configPath)
 
 			// Also watch the directory for new files
 			dir := filepath.Dir(configPath)
+			asm.configDir = dir
 			if err := asm.watcher.Add(dir); err != nil {
 				// Non-fatal: log warning but continue
 				asm.engine.eventBus.emit(EventWarning, fmt.Sprintf("failed to watch directory %s: %v", dir, err))
+			} else {
+				asm.watchedPaths = append(asm.watchedPaths, dir)
 			}
 		}
 	}
 
+	// Watch registered projects that opted into auto-sync
+	asm.watchProjectsLocked()
+
 	// Start the watcher goroutine
 	asm.wg.Add(1)
 	go func() {
@@ -179,6 +217,14 @@ func (asm *autoSyncManager) Stop() error {
 		asm.debounceTimer.Stop()
 		asm.debounceTimer = nil
 	}
+	for path, timer := range asm.projectTimers {
+		timer.Stop()
+		delete(asm.projectTimers, path)
+	}
+	asm.nextSync = time.Time{}
+	for _, status := range asm.destStatus {
+		status.PendingDebounce = false
+	}
 	asm.mu.Unlock()
 
 	// Update and persist auto-sync disabled state in engine config
@@ -220,10 +266,24 @@ func (asm *autoSyncManager) GetStatus() (*AutoSyncStatus, error) {
 	}
 
 	status := &AutoSyncStatus{
-		Running:       asm.isRunning,
-		LastSync:      asm.lastSync,
-		Enabled:       enabled,
-		WatchInterval: watchInterval,
+		Running:        asm.isRunning,
+		LastSync:       asm.lastSync,
+		NextSync:       asm.nextSync,
+		Enabled:        enabled,
+		WatchInterval:  watchInterval,
+		WatchedPaths:   append([]string(nil), asm.watchedPaths...),
+		EventsReceived: asm.eventsReceived,
+		EventsIgnored:  asm.eventsIgnored,
+		SyncsPerformed: asm.syncsPerformed,
+		LastError:      asm.lastError,
+	}
+
+	// Return copies so callers can't mutate internal state
+	if len(asm.destStatus) > 0 {
+		status.Destinations = make(map[string]DestinationSyncStatus, len(asm.destStatus))
+		for name, destStatus := range asm.destStatus {
+			status.Destinations[name] = *destStatus
+		}
 	}
 
 	return status, nil
@@ -242,7 +302,35 @@ func (asm *autoSyncManager) watchLoop() {
 			}
 
 			// Skip ignored events
-			if asm.shouldIgnoreEvent(event) {
+			asm.mu.Lock()
+			asm.eventsReceived++
+			ignored := asm.shouldIgnoreEvent(event)
+			if ignored {
+				asm.eventsIgnored++
+			}
+			asm.mu.Unlock()
+			if ignored {
+				continue
+			}
+
+			// Changes to a project's MCP config only sync that project
+			asm.mu.Lock()
+			projectPath, isProject := asm.projectForEvent(event.Name)
+			if isProject && projectPath == "" && filepath.Dir(event.Name) == asm.configDir {
+				isProject = false
+			}
+			asm.mu.Unlock()
+			if isProject {
+				if projectPath != "" {
+					asm.engine.eventBus.emit(EventFileChanged, ConfigChange{
+						Type:      asm.getChangeType(event),
+						Timestamp: time.Now(),
+						Source:    "file-watcher",
+						Name:      event.Name,
+						Details:   map[string]interface{}{"project": projectPath},
+					})
+					asm.debouncedProjectSync(projectPath)
+				}
 				continue
 			}
 
@@ -263,6 +351,7 @@ func (asm *autoSyncManager) watchLoop() {
 			}
 			
 			// Emit error
+			asm.recordError(err)
 			asm.engine.eventBus.emit(EventError, err)
 		}
 	}
@@ -312,6 +401,11 @@ func (asm *autoSyncManager) debouncedSync() {
 	asm.mu.Lock()
 	defer asm.mu.Unlock()
 
+	// Config changes call this whether or not auto-sync is running
+	if !asm.isRunning {
+		return
+	}
+
 	// Cancel existing timer
 	if asm.debounceTimer != nil {
 		asm.debounceTimer.Stop()
@@ -321,18 +415,32 @@ func (asm *autoSyncManager) debouncedSync() {
 	asm.debounceTimer = time.AfterFunc(asm.config.DebounceDelay, func() {
 		asm.performSync()
 	})
+	asm.nextSync = time.Now().Add(asm.config.DebounceDelay)
+
+	// Mark destinations as waiting on the debounce
+	for _, destName := range asm.getDestinationsToSync() {
+		asm.destinationStatus(destName).PendingDebounce = true
+	}
 }
 
 // performSync performs the actual sync operation
 func (asm *autoSyncManager) performSync() {
+	asm.mu.Lock()
+	asm.nextSync = time.Time{}
+	asm.mu.Unlock()
+
 	// Reload config first
 	if err := asm.engine.LoadConfig(asm.engine.configPath); err != nil {
-		asm.engine.eventBus.emit(EventError, fmt.Errorf("failed to reload config: %w", err))
+		err = fmt.Errorf("failed to reload config: %w", err)
+		asm.recordError(err)
+		asm.engine.eventBus.emit(EventError, err)
 		return
 	}
 
 	// Get destinations to sync
+	asm.mu.Lock()
 	destinations := asm.getDestinationsToSync()
+	asm.mu.Unlock()
 	if len(destinations) == 0 {
 		return
 	}
@@ -350,11 +458,14 @@ func (asm *autoSyncManager) performSync() {
 		// Get destination
 		dest, err := asm.engine.GetDestination(destName)
 		if err != nil {
-			asm.engine.eventBus.emit(EventError, fmt.Errorf("failed to get destination %s: %w", destName, err))
+			err = fmt.Errorf("failed to get destination %s: %w", destName, err)
+			asm.recordDestinationResult(destName, nil, err)
+			asm.engine.eventBus.emit(EventError, err)
 			continue
 		}
 
 		result, err := asm.engine.SyncTo(ctx, dest, options)
+		asm.recordDestinationResult(destName, result, err)
 		if err != nil {
 			asm.engine.eventBus.emit(EventSyncFailed, *result)
 		} else {
@@ -365,6 +476,48 @@ func (asm *autoSyncManager) performSync() {
 	// Update last sync time
 	asm.mu.Lock()
 	asm.lastSync = time.Now()
+	asm.syncsPerformed++
+	asm.mu.Unlock()
+}
+
+// destinationStatus returns the status entry for a destination (caller must hold asm.mu)
+func (asm *autoSyncManager) destinationStatus(name string) *DestinationSyncStatus {
+	status, ok := asm.destStatus[name]
+	if !ok {
+		status = &DestinationSyncStatus{Name: name}
+		asm.destStatus[name] = status
+	}
+	return status
+}
+
+// recordDestinationResult updates per-destination status after a sync attempt
+func (asm *autoSyncManager) recordDestinationResult(name string, result *SyncResult, err error) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	status := asm.destinationStatus(name)
+	status.LastAttempt = time.Now()
+	status.PendingDebounce = false
+	status.SyncCount++
+
+	if err != nil {
+		status.FailureCount++
+		status.LastError = err.Error()
+		asm.lastError = fmt.Sprintf("%s: %v", name, err)
+		return
+	}
+
+	status.LastSuccess = status.LastAttempt
+	status.LastError = ""
+	if result != nil {
+		status.ServersWritten = result.ServersWritten
+	}
+}
+
+// recordError remembers the most recent auto-sync error
+func (asm *autoSyncManager) recordError(err error) {
+	asm.mu.Lock()
+	asm.lastError = err.Error()
 	asm.mu.Unlock()
 }
 
@@ -376,9 +529,11 @@ func (asm *autoSyncManager) getDestinationsToSync() []string {
 	if len(asm.config.TargetWhitelist) > 0 {
 		destinations = asm.config.TargetWhitelist
 	} else {
-		// Otherwise sync to all registered destinations
-		for name := range asm.engine.destinations {
-			destinations = append(destinations, name)
+		// Otherwise sync to all registered destinations that take the global config
+		for name, dest := range asm.engine.ListDestinations() {
+			if !isProjectOnly(dest) {
+				destinations = append(destinations, name)
+			}
 		}
 	}
 
@@ -399,4 +554,159 @@ func (asm *autoSyncManager) getDestinationsToSync() []string {
 	}
 
 	return destinations
-}
\ No newline at end of file
+}
+
+// watchProjectsLocked watches the directories of registered projects with
+// AutoSync enabled and stops watching projects that no longer qualify
+// (caller must hold asm.mu)
+func (asm *autoSyncManager) watchProjectsLocked() {
+	asm.engine.mu.RLock()
+	wanted := make(map[string]string)
+	for path, project := range asm.engine.config.Settings.Projects {
+		if !project.AutoSync {
+			continue
+		}
+		wanted[path] = path
+		// Nested config directory such as .mcp/config.json
+		for _, configFile := range projectMCPConfigFiles {
+			if dir := filepath.Dir(configFile); dir != "." {
+				wanted[filepath.Join(path, dir)] = path
+			}
+		}
+	}
+	asm.engine.mu.RUnlock()
+
+	// Drop watches for projects that were removed or disabled
+	for dir := range asm.projectDirs {
+		if _, ok := wanted[dir]; !ok {
+			asm.watcher.Remove(dir)
+			delete(asm.projectDirs, dir)
+			asm.removeWatchedPath(dir)
+		}
+	}
+
+	for dir, projectPath := range wanted {
+		if _, ok := asm.projectDirs[dir]; ok {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := asm.watcher.Add(dir); err != nil {
+			asm.engine.eventBus.emit(EventWarning, fmt.Sprintf("failed to watch project directory %s: %v", dir, err))
+			continue
+		}
+		asm.projectDirs[dir] = projectPath
+		asm.watchedPaths = append(asm.watchedPaths, dir)
+	}
+}
+
+// refreshProjectWatches re-reads registered projects and updates project watches
+func (asm *autoSyncManager) refreshProjectWatches() {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	if !asm.isRunning {
+		return
+	}
+	asm.watchProjectsLocked()
+}
+
+// removeWatchedPath drops a path from the reported watch list (caller must hold asm.mu)
+func (asm *autoSyncManager) removeWatchedPath(path string) {
+	for i, watched := range asm.watchedPaths {
+		if watched == path {
+			asm.watchedPaths = append(asm.watchedPaths[:i], asm.watchedPaths[i+1:]...)
+			return
+		}
+	}
+}
+
+// projectForEvent maps a file event to a watched project. The second return
+// value reports whether the event happened inside a project directory; the
+// project path is empty when the file is not one of the project's MCP configs.
+// Caller must hold asm.mu.
+func (asm *autoSyncManager) projectForEvent(name string) (string, bool) {
+	projectPath, ok := asm.projectDirs[filepath.Dir(name)]
+	if !ok {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(projectPath, name)
+	if err != nil {
+		return "", true
+	}
+	for _, configFile := range projectMCPConfigFiles {
+		if rel == filepath.FromSlash(configFile) {
+			return projectPath, true
+		}
+	}
+
+	return "", true
+}
+
+// debouncedProjectSync performs a debounced sync of a single project
+func (asm *autoSyncManager) debouncedProjectSync(projectPath string) {
+	asm.mu.Lock()
+	defer asm.mu.Unlock()
+
+	if timer, ok := asm.projectTimers[projectPath]; ok {
+		timer.Stop()
+	}
+
+	asm.projectTimers[projectPath] = time.AfterFunc(asm.config.DebounceDelay, func() {
+		asm.performProjectSync(projectPath)
+	})
+}
+
+// performProjectSync re-parses a project's MCP config and syncs its
+// destinations, unless auto-sync was stopped while the timer was pending
+func (asm *autoSyncManager) performProjectSync(projectPath string) {
+	asm.mu.Lock()
+	delete(asm.projectTimers, projectPath)
+	running := asm.isRunning
+	asm.mu.Unlock()
+	if !running {
+		return
+	}
+
+	if _, err := asm.engine.reloadProject(projectPath); err != nil {
+		err = fmt.Errorf("failed to reload project %s: %w", projectPath, err)
+		asm.recordError(err)
+		asm.engine.eventBus.emit(EventError, err)
+		return
+	}
+
+	result, err := asm.engine.SyncProject(context.Background(), projectPath, SyncOptions{})
+	if err != nil {
+		err = fmt.Errorf("failed to sync project %s: %w", projectPath, err)
+		asm.recordError(err)
+		asm.engine.eventBus.emit(EventError, err)
+		return
+	}
+
+	for i := range result.Results {
+		syncResult := result.Results[i]
+		var syncErr error
+		if !syncResult.Success && len(syncResult.Errors) > 0 {
+			syncErr = fmt.Errorf("%s", syncResult.Errors[len(syncResult.Errors)-1].Error)
+		}
+		asm.recordDestinationResult(projectStatusKey(projectPath, syncResult.Destination), &syncResult, syncErr)
+
+		if syncErr != nil {
+			asm.engine.eventBus.emit(EventSyncFailed, syncResult)
+		} else {
+			asm.engine.eventBus.emit(EventSyncCompleted, syncResult)
+		}
+	}
+
+	asm.mu.Lock()
+	asm.lastSync = time.Now()
+	asm.syncsPerformed++
+	asm.mu.Unlock()
+}
+
+// projectStatusKey is the AutoSyncStatus.Destinations key for a project destination
+func projectStatusKey(projectPath, destination string) string {
+	return fmt.Sprintf("project:%s:%s", projectPath, destination)
+}
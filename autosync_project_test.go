@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAutoSyncProjectDirectories tests that auto-sync picks up changes to a
+// registered project's .mcp.json and syncs the project's destinations
+func TestAutoSyncProjectDirectories(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "engine-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	projectDir := filepath.Join(tmpDir, "my-project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewEngine(WithFileStorage(filepath.Join(tmpDir, "storage")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.AddServer("global-server", ServerConfig{
+		Transport: "stdio",
+		Command:   "global-command",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDest := &testDestination{
+		id:     "project-dest",
+		path:   filepath.Join(projectDir, "synced.json"),
+		synced: make(chan bool, 1),
+	}
+	if err := engine.RegisterDestination("project-dest", projectDest); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.RegisterProject(projectDir, ProjectConfig{
+		Name:         "my-project",
+		Destinations: []string{"project-dest"},
+		AutoSync:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	updates := make(chan ConfigChange, 10)
+	unsubscribe := engine.OnConfigChange(func(change ConfigChange) {
+		if change.Type == "project-updated" {
+			updates <- change
+		}
+	})
+	defer unsubscribe()
+
+	if err := engine.StartAutoSync(AutoSyncConfig{
+		Enabled:         true,
+		WatchInterval:   100 * time.Millisecond,
+		DebounceDelay:   50 * time.Millisecond,
+		TargetWhitelist: []string{"none"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.StopAutoSync()
+
+	status, err := engine.GetAutoSyncStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	watched := false
+	for _, path := range status.WatchedPaths {
+		if path == projectDir {
+			watched = true
+		}
+	}
+	if !watched {
+		t.Fatalf("Expected project directory to be watched, got %v", status.WatchedPaths)
+	}
+
+	// Write the project's MCP config
+	mcpJSON := []byte(`{"mcpServers": {"project-server": {"command": "project-command", "transport": "stdio"}}}`)
+	if err := os.WriteFile(filepath.Join(projectDir, ".mcp.json"), mcpJSON, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case change := <-updates:
+		if change.Name != projectDir {
+			t.Errorf("Expected project update for %s, got %s", projectDir, change.Name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for project update event")
+	}
+
+	select {
+	case <-projectDest.synced:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for project sync")
+	}
+
+	// Stored project config should reflect the parsed file
+	project, err := engine.GetProjectConfig(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, ok := project.Servers["project-server"]
+	if !ok {
+		t.Fatal("Expected project-server in stored project config")
+	}
+	if !server.Internal.ProjectSpecific || server.Internal.ProjectPath != projectDir {
+		t.Error("Expected project server to be marked project-specific")
+	}
+
+	// The project destination gets both global and project servers
+	data, err := projectDest.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written struct {
+		MCPServers map[string]interface{} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := written.MCPServers["project-server"]; !ok {
+		t.Error("Expected project-server to be synced")
+	}
+	if _, ok := written.MCPServers["global-server"]; !ok {
+		t.Error("Expected global-server to be synced alongside project servers")
+	}
+}
+
+// TestAutoSyncProjectWatchesFollowConfig tests that projects in a replaced
+// config are watched, and that a project sync due after stopping is dropped
+func TestAutoSyncProjectWatchesFollowConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "my-project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewEngine(WithFileStorage(filepath.Join(tmpDir, "storage")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.StartAutoSync(AutoSyncConfig{
+		Enabled:         true,
+		WatchInterval:   100 * time.Millisecond,
+		DebounceDelay:   50 * time.Millisecond,
+		TargetWhitelist: []string{"none"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	setProject := func(autoSync bool) {
+		t.Helper()
+		config, err := engine.GetConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.Settings.Projects = map[string]ProjectConfig{
+			projectDir: {Name: "my-project", Path: projectDir, AutoSync: autoSync},
+		}
+		if err := engine.SetConfig(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitForWatch := func(want bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			status, err := engine.GetAutoSyncStatus()
+			if err != nil {
+				t.Fatal(err)
+			}
+			watched := false
+			for _, path := range status.WatchedPaths {
+				if path == projectDir {
+					watched = true
+				}
+			}
+			if watched == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected project watched to be %v, got %v", want, status.WatchedPaths)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	setProject(true)
+	waitForWatch(true)
+	setProject(false)
+	waitForWatch(false)
+
+	// A project sync whose timer fires after stopping does nothing
+	setProject(true)
+	waitForWatch(true)
+	if err := engine.StopAutoSync(); err != nil {
+		t.Fatal(err)
+	}
+	mcpJSON := []byte(`{"mcpServers": {"project-server": {"command": "project-command", "transport": "stdio"}}}`)
+	if err := os.WriteFile(filepath.Join(projectDir, ".mcp.json"), mcpJSON, 0644); err != nil {
+		t.Fatal(err)
+	}
+	engine.(*engineImpl).autoSync.performProjectSync(projectDir)
+	project, err := engine.GetProjectConfig(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := project.Servers["project-server"]; ok {
+		t.Error("Expected no project sync after auto-sync stopped")
+	}
+}
@@ -569,4 +569,115 @@ func (sfd *syncFlagDestination) Write(data []byte) error {
 	*sfd.synced = true
 	sfd.flagMu.Unlock()
 	return sfd.testDestination.Write(data)
-}
\ No newline at end of file
+}
+func TestAutoSyncStatusDiagnostics(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "engine-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	engine, err := NewEngine(WithFileStorage(tmpDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		Version: "1.0.0",
+		Servers: map[string]ServerWithMetadata{
+			"test-server": {
+				ServerConfig: ServerConfig{
+					Transport: "stdio",
+					Command:   "test-command",
+				},
+				Internal: InternalMetadata{Enabled: true},
+			},
+		},
+	}
+	if err := engine.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	testDest := &testDestination{
+		id:     "test-dest",
+		path:   filepath.Join(tmpDir, "test-dest.json"),
+		synced: make(chan bool, 1),
+	}
+	if err := engine.RegisterDestination("test-dest", testDest); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.StartAutoSync(AutoSyncConfig{
+		Enabled:         true,
+		WatchInterval:   100 * time.Millisecond,
+		DebounceDelay:   200 * time.Millisecond,
+		TargetWhitelist: []string{"test-dest"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.StopAutoSync()
+
+	status, err := engine.GetAutoSyncStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.WatchedPaths) == 0 {
+		t.Error("Expected watched paths to be reported")
+	}
+
+	// Trigger a debounced sync and check the pending state
+	if err := engine.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		status, _ = engine.GetAutoSyncStatus()
+		if dest, ok := status.Destinations["test-dest"]; ok && dest.PendingDebounce {
+			if status.NextSync.IsZero() {
+				t.Error("Expected NextSync to be set while a sync is pending")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for pending debounce")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-testDest.synced:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for sync")
+	}
+
+	// Status is updated right after the write returns
+	deadline = time.Now().Add(time.Second)
+	for {
+		status, _ = engine.GetAutoSyncStatus()
+		if status.SyncsPerformed > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for sync to be recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	dest := status.Destinations["test-dest"]
+	if dest.PendingDebounce {
+		t.Error("Expected pending debounce to be cleared after sync")
+	}
+	if dest.LastSuccess.IsZero() || dest.LastAttempt.IsZero() {
+		t.Error("Expected last attempt and last success to be set")
+	}
+	if dest.LastError != "" {
+		t.Errorf("Unexpected destination error: %s", dest.LastError)
+	}
+	if dest.ServersWritten != 1 {
+		t.Errorf("Expected 1 server written, got %d", dest.ServersWritten)
+	}
+	if !status.NextSync.IsZero() {
+		t.Error("Expected NextSync to be cleared after sync")
+	}
+}
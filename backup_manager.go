@@ -40,18 +40,8 @@ func (e *engineImpl) CreateBackup(description string) (*BackupInfo, error) {
 	}
 
 	// Store backup using storage layer
-	backupKey := fmt.Sprintf("backups/%s", backupID)
-	if err := e.storage.Write(backupKey, data); err != nil {
-		return nil, fmt.Errorf("failed to write backup: %w", err)
-	}
-
-	// Store backup metadata
-	metadataKey := fmt.Sprintf("backup-meta:%s", backupID)
-	metaData, _ := json.Marshal(info)
-	if err := e.storage.Write(metadataKey, metaData); err != nil {
-		// Try to clean up the backup
-		e.storage.Delete(backupKey)
-		return nil, fmt.Errorf("failed to write backup metadata: %w", err)
+	if err := e.writeBackup(info, data); err != nil {
+		return nil, err
 	}
 
 	// Clean up old backups if needed
@@ -68,10 +58,28 @@ func (e *engineImpl) CreateBackup(description string) (*BackupInfo, error) {
 	return info, nil
 }
 
+// writeBackup stores backup data and its metadata
+func (e *engineImpl) writeBackup(info *BackupInfo, data []byte) error {
+	backupKey := fmt.Sprintf("backups/%s", info.ID)
+	if err := e.getStorage().Write(backupKey, data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	// Store backup metadata
+	metadataKey := fmt.Sprintf("backup-meta:%s", info.ID)
+	metaData, _ := json.Marshal(info)
+	if err := e.getStorage().Write(metadataKey, metaData); err != nil {
+		// Try to clean up the backup
+		e.getStorage().Delete(backupKey)
+		return fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+	return nil
+}
+
 // ListBackups returns a list of all backups
 func (e *engineImpl) ListBackups() ([]*BackupInfo, error) {
 	// List all backup metadata keys
-	metaKeys, err := e.storage.List("backup-meta:")
+	metaKeys, err := e.getStorage().List("backup-meta:")
 	if err != nil {
 		return nil, fmt.Errorf("failed to list backup metadata: %w", err)
 	}
@@ -80,7 +88,7 @@ func (e *engineImpl) ListBackups() ([]*BackupInfo, error) {
 	
 	for _, key := range metaKeys {
 		// Read metadata
-		data, err := e.storage.Read(key)
+		data, err := e.getStorage().Read(key)
 		if err != nil {
 			continue // Skip invalid metadata
 		}
@@ -105,16 +113,29 @@ func (e *engineImpl) ListBackups() ([]*BackupInfo, error) {
 func (e *engineImpl) RestoreBackup(backupID string) error {
 	// Read backup data
 	backupKey := fmt.Sprintf("backups/%s", backupID)
-	data, err := e.storage.Read(backupKey)
+	data, err := e.getStorage().Read(backupKey)
 	if err != nil {
 		return fmt.Errorf("failed to read backup: %w", err)
 	}
 
+	// Backups of older configs are migrated like stored configs, and
+	// backups from a newer major version are refused
+	data, _, err = e.upgradeConfig(data)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
 	// Parse backup config
 	var backupConfig Config
 	if err := json.Unmarshal(data, &backupConfig); err != nil {
 		return fmt.Errorf("failed to parse backup: %w", err)
 	}
+	if backupConfig.Servers == nil {
+		backupConfig.Servers = make(map[string]ServerWithMetadata)
+	}
+	if backupConfig.Targets == nil {
+		backupConfig.Targets = make(map[string]TargetConfig)
+	}
 
 	// Create a backup of current config before restoring (safety)
 	if _, err := e.CreateBackup("pre-restore-backup"); err != nil {
@@ -126,13 +147,21 @@ func (e *engineImpl) RestoreBackup(backupID string) error {
 	defer e.mu.Unlock()
 
 	// Restore the config
+	previous := e.config
 	e.config = &backupConfig
 
 	// Save to storage
 	if err := e.saveConfigNoLock(); err != nil {
+		// Keep the config that is still stored
+		e.config = previous
 		return fmt.Errorf("failed to save restored config: %w", err)
 	}
 
+	// Watch the restored config's projects if auto-sync is running
+	if e.autoSync != nil {
+		go e.autoSync.refreshProjectWatches()
+	}
+
 	// Emit event
 	e.eventBus.emit(EventBackupRestored, BackupInfo{
 		ID:        backupID,
@@ -158,11 +187,11 @@ func (e *engineImpl) cleanupOldBackups(maxBackups int) error {
 		for _, backup := range toDelete {
 			// Delete backup data
 			backupKey := fmt.Sprintf("backups/%s", backup.ID)
-			e.storage.Delete(backupKey)
+			e.getStorage().Delete(backupKey)
 			
 			// Delete metadata
 			metaKey := fmt.Sprintf("backup-meta/%s", backup.ID)
-			e.storage.Delete(metaKey)
+			e.getStorage().Delete(metaKey)
 		}
 	}
 	
@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket holds all keys of a BoltStorage
+var boltBucket = []byte("agent-master")
+
+// BoltStorage implements Storage in a single bbolt database file. Every write
+// is its own transaction, keys are kept sorted so List is a prefix scan, and
+// Watch notifies handlers after commits from this process. bbolt locks the
+// file while it's open, so only one process can use a database at a time.
+type BoltStorage struct {
+	db       *bolt.DB
+	mu       sync.RWMutex
+	watchers map[string][]func([]byte)
+}
+
+// NewBoltStorage opens or creates the database at path, waiting up to
+// DefaultLockTimeout for another process that has it open
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	return openBoltStorage(path, DefaultLockTimeout)
+}
+
+func openBoltStorage(path string, lockTimeout time.Duration) (*BoltStorage, error) {
+	path = expandPath(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	// bbolt treats a zero timeout as wait forever
+	if lockTimeout <= 0 {
+		lockTimeout = time.Nanosecond
+	}
+	db, err := bolt.Open(path, SecretFileMode, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, &LockError{Path: path, Timeout: lockTimeout}
+		}
+		return nil, fmt.Errorf("failed to open bolt storage: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	return &BoltStorage{
+		db:       db,
+		watchers: make(map[string][]func([]byte)),
+	}, nil
+}
+
+// Close closes the database and releases its file lock
+func (bs *BoltStorage) Close() error {
+	return bs.db.Close()
+}
+
+// Path returns the database file path
+func (bs *BoltStorage) Path() string {
+	return bs.db.Path()
+}
+
+// Read reads data from storage
+func (bs *BoltStorage) Read(key string) ([]byte, error) {
+	var data []byte
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltBucket).Get([]byte(key))
+		if value == nil {
+			return fmt.Errorf("key not found: %s", key)
+		}
+		// Values are only valid during the transaction
+		data = append([]byte(nil), value...)
+		return nil
+	})
+	return data, err
+}
+
+// Write writes data to storage
+func (bs *BoltStorage) Write(key string, data []byte) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if err := bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), data)
+	}); err != nil {
+		return err
+	}
+
+	bs.notify(key, data)
+	return nil
+}
+
+// Update replaces the data of a key with the result of fn in one
+// transaction. fn receives nil if the key doesn't exist.
+func (bs *BoltStorage) Update(key string, fn func([]byte) ([]byte, error)) error {
+	var data []byte
+	if err := bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		var current []byte
+		if value := bucket.Get([]byte(key)); value != nil {
+			current = append([]byte(nil), value...)
+		}
+
+		var err error
+		if data, err = fn(current); err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key), data)
+	}); err != nil {
+		return err
+	}
+
+	bs.notify(key, data)
+	return nil
+}
+
+// ReadRevision reads a key with its revision
+func (bs *BoltStorage) ReadRevision(key string) ([]byte, string, error) {
+	data, err := bs.Read(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, ContentRevision(data), nil
+}
+
+// WriteIfRevision writes a key if it is still at revision, checking and
+// writing in one transaction
+func (bs *BoltStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	if err := bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		current := bucket.Get([]byte(key))
+		if err := checkRevision(key, current, current != nil, revision); err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key), data)
+	}); err != nil {
+		return "", err
+	}
+
+	bs.notify(key, data)
+	return ContentRevision(data), nil
+}
+
+// Delete removes data from storage
+func (bs *BoltStorage) Delete(key string) error {
+	if err := bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		if bucket.Get([]byte(key)) == nil {
+			return fmt.Errorf("key not found: %s", key)
+		}
+		return bucket.Delete([]byte(key))
+	}); err != nil {
+		return err
+	}
+
+	bs.notify(key, nil)
+	return nil
+}
+
+// List lists keys with given prefix, sorted
+func (bs *BoltStorage) List(prefix string) ([]string, error) {
+	var keys []string
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(boltBucket).Cursor()
+		p := []byte(prefix)
+		for k, _ := cursor.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = cursor.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// Watch watches for changes to a key
+func (bs *BoltStorage) Watch(key string, handler func([]byte)) (func(), error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.watchers[key] = append(bs.watchers[key], handler)
+	index := len(bs.watchers[key]) - 1
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			bs.mu.Lock()
+			defer bs.mu.Unlock()
+
+			// Keep indices stable for other unsubscribe functions
+			handlers := bs.watchers[key]
+			if index < len(handlers) {
+				handlers[index] = nil
+			}
+
+			// Clean up if no more handlers
+			for _, h := range handlers {
+				if h != nil {
+					return
+				}
+			}
+			delete(bs.watchers, key)
+		})
+	}, nil
+}
+
+// notify calls the handlers watching key
+func (bs *BoltStorage) notify(key string, data []byte) {
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	for _, handler := range bs.watchers[key] {
+		if handler != nil {
+			go handler(data)
+		}
+	}
+}
+
+// WithBoltStorage stores engine data in a bbolt database at path, opened
+// with the engine's lock timeout
+func WithBoltStorage(path string) Option {
+	return func(cfg *engineConfig) error {
+		if strings.TrimSpace(path) == "" {
+			return fmt.Errorf("bolt storage path is required")
+		}
+		cfg.storage = nil
+		cfg.boltPath = path
+		return nil
+	}
+}
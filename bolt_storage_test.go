@@ -0,0 +1,111 @@
+package engine
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBoltStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-master.db")
+	storage, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("NewBoltStorage failed: %v", err)
+	}
+
+	changed := make(chan []byte, 1)
+	unwatch, err := storage.Watch("config", func(data []byte) { changed <- data })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range map[string]string{
+		"config":              `{"version":"1.0.2"}`,
+		"backups:2":           `{}`,
+		"backups:1":           `{}`,
+		"backups-meta:1":      `{}`,
+		"targets:claude:conf": `{}`,
+	} {
+		if err := storage.Write(key, []byte(value)); err != nil {
+			t.Fatalf("Write %s failed: %v", key, err)
+		}
+	}
+
+	select {
+	case data := <-changed:
+		if string(data) != `{"version":"1.0.2"}` {
+			t.Errorf("Unexpected watch data: %s", data)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected a watch notification")
+	}
+	unwatch()
+	storage.mu.RLock()
+	_, watched := storage.watchers["config"]
+	storage.mu.RUnlock()
+	if watched {
+		t.Error("Expected the last unwatch to remove the key's handlers")
+	}
+
+	// List is a sorted prefix scan
+	keys, err := storage.List("backups:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"backups:1", "backups:2"}) {
+		t.Errorf("Unexpected keys: %v", keys)
+	}
+
+	if err := storage.Delete("backups:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Read("backups:1"); !isNotFoundError(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	if err := storage.Delete("backups:1"); !isNotFoundError(err) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+
+	// A failing update leaves the key untouched
+	failed := errors.New("failed")
+	if err := storage.Update("config", func([]byte) ([]byte, error) { return nil, failed }); err != failed {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+
+	// Only one process can open the database
+	if _, err := openBoltStorage(path, 10*time.Millisecond); !errors.As(err, new(*LockError)) {
+		t.Errorf("Expected a LockError, got %v", err)
+	}
+
+	// The engine persists its config in the database
+	engine, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("test-server", ServerConfig{Transport: "stdio", Command: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewEngine(WithBoltStorage(path))
+	if err != nil {
+		t.Fatalf("NewEngine failed: %v", err)
+	}
+	if _, err := reopened.GetServer("test-server"); err != nil {
+		t.Errorf("Expected server to persist: %v", err)
+	}
+
+	// Closing the engine closes the database it opened
+	if err := reopened.Close(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewEngine(WithBoltStorage(path), WithLockTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected the database to be released by Close: %v", err)
+	}
+	again.Close()
+}
@@ -0,0 +1,367 @@
+package engine
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DefaultCacheEntries is how many keys a CachingStorage keeps by default
+const DefaultCacheEntries = 256
+
+// CachingStorage keeps recently read values of the storage it wraps in
+// memory, evicting the least recently used keys beyond its size. Cached
+// keys are watched on the wrapped storage, so changes made through other
+// instances invalidate them as soon as the backend reports them.
+//
+// Writes go straight to the wrapped storage and invalidate the key; the
+// next read fetches it again.
+type CachingStorage struct {
+	backend    Storage
+	maxEntries int
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // Most recently used first
+	writeMu    sync.Mutex // Serializes WriteIfRevision without a revisioned backend
+	generation uint64     // Source of cacheEntry generations
+	hits       uint64
+	misses     uint64
+}
+
+// cacheEntry is a cached key. Entries stay while they are watched, even
+// after a change invalidates their value.
+type cacheEntry struct {
+	key      string
+	data     []byte
+	revision string // Set when read with ReadRevision
+	valid    bool
+	gen      uint64 // Renewed on invalidation, so stale reads aren't cached
+	unwatch  func()
+}
+
+// CacheStats reports how a CachingStorage is used
+type CacheStats struct {
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+// NewCachingStorage wraps backend with a cache of up to maxEntries keys,
+// or DefaultCacheEntries if maxEntries is 0
+func NewCachingStorage(backend Storage, maxEntries int) (*CachingStorage, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if maxEntries < 0 {
+		return nil, fmt.Errorf("cache size must not be negative")
+	}
+	if maxEntries == 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &CachingStorage{
+		backend:    backend,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}, nil
+}
+
+// Backend returns the wrapped storage
+func (cs *CachingStorage) Backend() Storage {
+	return cs.backend
+}
+
+// Stats returns the cache's size and hit counts
+func (cs *CachingStorage) Stats() CacheStats {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return CacheStats{Entries: cs.lru.Len(), Hits: cs.hits, Misses: cs.misses}
+}
+
+// Read reads data from the cache, or from storage on a miss
+func (cs *CachingStorage) Read(key string) ([]byte, error) {
+	if data, _, ok := cs.lookup(key, false); ok {
+		return data, nil
+	}
+
+	gen, err := cs.track(key)
+	if err != nil {
+		return cs.backend.Read(key)
+	}
+	data, err := cs.backend.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	cs.fill(key, gen, data, "")
+	return data, nil
+}
+
+// Write writes data to storage and invalidates the cached value
+func (cs *CachingStorage) Write(key string, data []byte) error {
+	defer cs.invalidate(key)
+	return cs.backend.Write(key, data)
+}
+
+// ReadRevision reads a key with its revision, from the cache if it was
+// cached by an earlier ReadRevision
+func (cs *CachingStorage) ReadRevision(key string) ([]byte, string, error) {
+	if data, revision, ok := cs.lookup(key, true); ok {
+		return data, revision, nil
+	}
+
+	gen, trackErr := cs.track(key)
+	var data []byte
+	var revision string
+	var err error
+	if revisioned, ok := cs.backend.(RevisionedStorage); ok {
+		data, revision, err = revisioned.ReadRevision(key)
+	} else {
+		data, err = cs.backend.Read(key)
+		revision = ContentRevision(data)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if trackErr == nil {
+		cs.fill(key, gen, data, revision)
+	}
+	return data, revision, nil
+}
+
+// WriteIfRevision writes a key if it is still at revision. The check is
+// only atomic across processes if the wrapped storage is a
+// RevisionedStorage.
+func (cs *CachingStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	defer cs.invalidate(key)
+	if revisioned, ok := cs.backend.(RevisionedStorage); ok {
+		return revisioned.WriteIfRevision(key, data, revision)
+	}
+
+	cs.writeMu.Lock()
+	defer cs.writeMu.Unlock()
+	current, err := cs.backend.Read(key)
+	if err := checkRevision(key, current, err == nil, revision); err != nil {
+		return "", err
+	}
+	if err := cs.backend.Write(key, data); err != nil {
+		return "", err
+	}
+	return ContentRevision(data), nil
+}
+
+// ReadMany reads keys, taking cached values from the cache and the rest
+// from storage. Values read for it aren't cached, so exports don't evict
+// the keys in use.
+func (cs *CachingStorage) ReadMany(keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if data, _, ok := cs.lookup(key, false); ok {
+			values[key] = data
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	if batch, ok := cs.backend.(BatchReader); ok {
+		fetched, err := batch.ReadMany(missing)
+		if err != nil {
+			return nil, err
+		}
+		for key, data := range fetched {
+			values[key] = data
+		}
+		return values, nil
+	}
+	for _, key := range missing {
+		data, err := cs.backend.Read(key)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		values[key] = data
+	}
+	return values, nil
+}
+
+// Delete removes data from storage and the cache
+func (cs *CachingStorage) Delete(key string) error {
+	defer cs.invalidate(key)
+	return cs.backend.Delete(key)
+}
+
+// List lists keys with given prefix. Listings aren't cached.
+func (cs *CachingStorage) List(prefix string) ([]string, error) {
+	return cs.backend.List(prefix)
+}
+
+// Watch watches for changes to a key. The cached value is invalidated
+// before handler is called, so handlers reading the key see the change.
+func (cs *CachingStorage) Watch(key string, handler func([]byte)) (func(), error) {
+	return cs.backend.Watch(key, func(data []byte) {
+		cs.invalidate(key)
+		handler(data)
+	})
+}
+
+// Purge empties the cache
+func (cs *CachingStorage) Purge() {
+	cs.mu.Lock()
+	var unwatch []func()
+	for cs.lru.Len() > 0 {
+		unwatch = append(unwatch, cs.removeLocked(cs.lru.Back()))
+	}
+	cs.mu.Unlock()
+
+	for _, fn := range unwatch {
+		fn()
+	}
+}
+
+// Close empties the cache and closes the wrapped storage if it can be
+// closed
+func (cs *CachingStorage) Close() error {
+	cs.Purge()
+	if closer, ok := cs.backend.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// lookup returns a copy of the cached value of key, marking it recently
+// used. With withRevision, only values cached with a revision are hits.
+func (cs *CachingStorage) lookup(key string, withRevision bool) ([]byte, string, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	element, ok := cs.entries[key]
+	if !ok {
+		cs.misses++
+		return nil, "", false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !entry.valid || (withRevision && entry.revision == "") {
+		cs.misses++
+		return nil, "", false
+	}
+	cs.lru.MoveToFront(element)
+	cs.hits++
+	return copyBytes(entry.data), entry.revision, true
+}
+
+// track makes sure key has an entry watched on the backend, returning its
+// generation. The watch is set up before the backend is read, so changes
+// made after the read are never missed.
+func (cs *CachingStorage) track(key string) (uint64, error) {
+	cs.mu.Lock()
+	if element, ok := cs.entries[key]; ok {
+		cs.lru.MoveToFront(element)
+		gen := element.Value.(*cacheEntry).gen
+		cs.mu.Unlock()
+		return gen, nil
+	}
+	cs.mu.Unlock()
+
+	unwatch, err := cs.backend.Watch(key, func([]byte) {
+		cs.invalidate(key)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cs.mu.Lock()
+	if element, ok := cs.entries[key]; ok {
+		// Tracked by a concurrent read meanwhile
+		cs.lru.MoveToFront(element)
+		gen := element.Value.(*cacheEntry).gen
+		cs.mu.Unlock()
+		unwatch()
+		return gen, nil
+	}
+	cs.generation++
+	entry := &cacheEntry{key: key, gen: cs.generation, unwatch: unwatch}
+	cs.entries[key] = cs.lru.PushFront(entry)
+
+	var evicted []func()
+	for cs.lru.Len() > cs.maxEntries {
+		evicted = append(evicted, cs.removeLocked(cs.lru.Back()))
+	}
+	cs.mu.Unlock()
+
+	for _, fn := range evicted {
+		fn()
+	}
+	return entry.gen, nil
+}
+
+// fill caches data for key unless it was invalidated or evicted since gen
+func (cs *CachingStorage) fill(key string, gen uint64, data []byte, revision string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	element, ok := cs.entries[key]
+	if !ok {
+		return
+	}
+	entry := element.Value.(*cacheEntry)
+	if entry.gen != gen {
+		return
+	}
+	entry.data = copyBytes(data)
+	entry.revision = revision
+	entry.valid = true
+}
+
+// invalidate drops the cached value of key, keeping it watched
+func (cs *CachingStorage) invalidate(key string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if element, ok := cs.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.data = nil
+		entry.revision = ""
+		entry.valid = false
+		cs.generation++
+		entry.gen = cs.generation
+	}
+}
+
+// removeLocked drops an entry, returning the function that stops watching
+// it, to be called without cs.mu; the caller holds cs.mu
+func (cs *CachingStorage) removeLocked(element *list.Element) func() {
+	entry := cs.lru.Remove(element).(*cacheEntry)
+	delete(cs.entries, entry.key)
+	return entry.unwatch
+}
+
+func copyBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+	copied := make([]byte, len(data))
+	copy(copied, data)
+	return copied
+}
+
+// WithCache caches up to maxEntries recently read keys in memory, or
+// DefaultCacheEntries if maxEntries is 0. The cache sits below encryption,
+// so it only holds sealed values.
+func WithCache(maxEntries int) Option {
+	return func(cfg *engineConfig) error {
+		if maxEntries < 0 {
+			return fmt.Errorf("cache size must not be negative")
+		}
+		if maxEntries == 0 {
+			maxEntries = DefaultCacheEntries
+		}
+		cfg.cacheEntries = maxEntries
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCachingStorage(t *testing.T) {
+	backend := NewMemoryStorage()
+	cache, err := NewCachingStorage(backend, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := cache.Write(key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Reads are served from the cache once cached
+	for i := 0; i < 3; i++ {
+		if data, err := cache.Read("a"); err != nil || string(data) != "a" {
+			t.Fatalf("Read a: %s, %v", data, err)
+		}
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %+v", stats)
+	}
+
+	// The least recently used key is evicted beyond the size
+	cache.Read("b")
+	cache.Read("a")
+	cache.Read("c")
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("Expected 2 entries, got %d", stats.Entries)
+	}
+	if _, ok := cache.entries["b"]; ok {
+		t.Error("Expected b to be evicted")
+	}
+
+	// Own writes are read back at once
+	if err := cache.Write("a", []byte("a2")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := cache.Read("a"); string(data) != "a2" {
+		t.Errorf("Expected a2 after writing, got %s", data)
+	}
+
+	// Changes made behind the cache's back invalidate it through Watch
+	if err := backend.Write("a", []byte("a3")); err != nil {
+		t.Fatal(err)
+	}
+	waitForValue(t, cache, "a", "a3")
+	if err := backend.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := cache.Read("c"); isNotFoundError(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected a deleted key to be invalidated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Evicted keys stop being watched
+	backend.mu.RLock()
+	_, watched := backend.watchers["b"]
+	backend.mu.RUnlock()
+	if watched {
+		t.Error("Expected the evicted key's watch to be removed")
+	}
+}
+
+func TestEngineWithCache(t *testing.T) {
+	backend := NewMemoryStorage()
+	engine, err := NewEngine(WithStorage(backend), WithCache(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := engine.CreateBackup(fmt.Sprintf("backup %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cache := engine.(*engineImpl).getStorage().(*CachingStorage)
+	if _, err := engine.ListBackups(); err != nil {
+		t.Fatal(err)
+	}
+	before := cache.Stats()
+	backups, err := engine.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := cache.Stats()
+	if len(backups) != 3 || after.Hits-before.Hits != 3 || after.Misses != before.Misses {
+		t.Errorf("Expected listing 3 backups again to hit the cache, got %d backups, %+v -> %+v", len(backups), before, after)
+	}
+
+	// Another engine's config save reaches this one through the cache
+	other, err := NewEngine(WithStorage(backend))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.AddServer("shared", ServerConfig{Transport: "stdio", Command: "shared"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := engine.GetServer("shared"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the cached engine to reload the changed config")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestEngineCachesListings(t *testing.T) {
+	backend := NewFaultyStorage(NewMemoryStorage())
+	engine, err := NewEngine(WithStorage(backend), WithCache(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if err := engine.AddServer(name, ServerConfig{Transport: "stdio", Command: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	project := t.TempDir()
+	if err := engine.RegisterProject(project, ProjectConfig{Name: "project"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first listing is built and stored, and read into the cache by the
+	// next one; later ones don't reach the backend
+	for i := 0; i < 2; i++ {
+		if _, err := engine.ListServers(ServerFilter{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := engine.ListProjects(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := backend.Read(Keys.ServerCache()); err != nil {
+		t.Errorf("Expected the server listing in storage: %v", err)
+	}
+	if _, err := backend.Read(Keys.ProjectCache(project)); err != nil {
+		t.Errorf("Expected the project listing in storage: %v", err)
+	}
+	reads := backend.Calls(FaultRead)
+	servers, err := engine.ListServers(ServerFilter{Source: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	projects, err := engine.ListProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := backend.Calls(FaultRead) - reads; calls != 0 {
+		t.Errorf("Expected cached listings not to read the backend, got %d reads", calls)
+	}
+	if len(servers) != 2 || len(projects) != 1 || projects[0].Name != "project" {
+		t.Errorf("Expected 2 servers and the project, got %d servers, %+v", len(servers), projects)
+	}
+
+	// Changing the config rebuilds the listing
+	if err := engine.AddServer("c", ServerConfig{Transport: "stdio", Command: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if servers, _ := engine.ListServers(ServerFilter{}); len(servers) != 3 {
+		t.Errorf("Expected 3 servers after adding one, got %d", len(servers))
+	}
+
+	// A config that failed to save isn't listed from the stored listing
+	backend.FailNext(FaultWrite, Keys.Config(), fmt.Errorf("disk full"))
+	if err := engine.AddServer("d", ServerConfig{Transport: "stdio", Command: "d"}); err == nil {
+		t.Fatal("Expected the save to fail")
+	}
+	if servers, _ := engine.ListServers(ServerFilter{}); len(servers) != 4 {
+		t.Errorf("Expected the unsaved server to be listed, got %d servers", len(servers))
+	}
+}
+
+// waitForValue waits for a cache to return want for key
+func waitForValue(t *testing.T, cache *CachingStorage, key, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if data, err := cache.Read(key); err == nil && string(data) == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %s to become %s", key, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
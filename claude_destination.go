@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ClaudeCodeDestination syncs servers into Claude Code's ~/.claude.json.
+// Global servers go to the top-level mcpServers, project servers go to
+// projects[path].mcpServers, and servers defined in a project's .mcp.json are
+// approved or rejected through enabledMcpjsonServers/disabledMcpjsonServers.
+// Everything else in the file is preserved.
+type ClaudeCodeDestination struct {
+	ID      string
+	adapter *ClaudeCodeAdapter
+}
+
+// claudeCodeSync is the transformed payload handed from Transform to Write
+type claudeCodeSync struct {
+	MCPServers map[string]ServerConfig          `json:"mcpServers"`
+	Projects   map[string]claudeCodeProjectSync `json:"projects,omitempty"`
+}
+
+// claudeCodeProjectSync is the managed part of a single project's settings.
+// MCPServers is nil for projects with a .mcp.json and empty, not omitted, for
+// projects whose servers were all removed or disabled.
+type claudeCodeProjectSync struct {
+	MCPServers             map[string]ServerConfig `json:"mcpServers"`
+	EnabledMCPJSONServers  []string                `json:"enabledMcpjsonServers,omitempty"`
+	DisabledMCPJSONServers []string                `json:"disabledMcpjsonServers,omitempty"`
+}
+
+// NewClaudeCodeDestination creates a Claude Code destination using the given adapter
+func NewClaudeCodeDestination(adapter *ClaudeCodeAdapter) *ClaudeCodeDestination {
+	return &ClaudeCodeDestination{
+		ID:      "claude-code",
+		adapter: adapter,
+	}
+}
+
+// GetID returns the destination identifier
+func (c *ClaudeCodeDestination) GetID() string {
+	return c.ID
+}
+
+// GetDescription returns a human-readable description
+func (c *ClaudeCodeDestination) GetDescription() string {
+	return fmt.Sprintf("Claude Code configuration at %s", c.adapter.ConfigPath())
+}
+
+// Transform splits the config into global and per-project servers
+func (c *ClaudeCodeDestination) Transform(config *Config) (interface{}, error) {
+	result := claudeCodeSync{
+		MCPServers: make(map[string]ServerConfig),
+		Projects:   make(map[string]claudeCodeProjectSync),
+	}
+
+	for name, server := range config.Servers {
+		if server.Internal.Enabled {
+			result.MCPServers[name] = server.ServerConfig
+		}
+	}
+
+	// Every managed project is included, so servers removed from a project
+	// are cleared from Claude Code too
+	for path, project := range config.Settings.Projects {
+		if project.Path != "" {
+			path = project.Path
+		}
+
+		var settings claudeCodeProjectSync
+		if hasMCPJSON(path) {
+			// Claude Code reads .mcp.json itself and only needs approval
+			for name, server := range project.Servers {
+				if server.Internal.Enabled {
+					settings.EnabledMCPJSONServers = append(settings.EnabledMCPJSONServers, name)
+				} else {
+					settings.DisabledMCPJSONServers = append(settings.DisabledMCPJSONServers, name)
+				}
+			}
+			sort.Strings(settings.EnabledMCPJSONServers)
+			sort.Strings(settings.DisabledMCPJSONServers)
+		} else {
+			settings.MCPServers = make(map[string]ServerConfig)
+			for name, server := range project.Servers {
+				if server.Internal.Enabled {
+					settings.MCPServers[name] = server.ServerConfig
+				}
+			}
+		}
+
+		result.Projects[path] = settings
+	}
+
+	return result, nil
+}
+
+// Read reads the current Claude Code configuration
+func (c *ClaudeCodeDestination) Read() ([]byte, error) {
+	return os.ReadFile(c.adapter.ConfigPath())
+}
+
+// ParseConfig reads global servers from Claude Code's config format
+func (c *ClaudeCodeDestination) ParseConfig(data []byte) (*Config, error) {
+	var config ClaudeCodeConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return (&MCPConfig{MCPServers: config.MCPServers}).ToConfigWithOptions(false)
+}
+
+// Write merges the transformed servers into the existing Claude Code configuration
+func (c *ClaudeCodeDestination) Write(data []byte) error {
+	var update claudeCodeSync
+	if err := json.Unmarshal(data, &update); err != nil {
+		return fmt.Errorf("invalid Claude Code sync data: %w", err)
+	}
+
+	config, err := c.adapter.ReadClaudeCodeConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read Claude Code config: %w", err)
+	}
+
+	config.MCPServers = update.MCPServers
+	if config.MCPServers == nil {
+		config.MCPServers = make(map[string]ServerConfig)
+	}
+
+	for path, project := range update.Projects {
+		settings := config.Projects[path]
+		if project.MCPServers != nil {
+			settings.MCPServers = project.MCPServers
+		}
+
+		// Only touch names we manage; approvals made in Claude Code for other
+		// servers are kept as they are
+		settings.EnabledMCPJSONServers = mergeServerNames(settings.EnabledMCPJSONServers,
+			project.EnabledMCPJSONServers, project.DisabledMCPJSONServers)
+		settings.DisabledMCPJSONServers = mergeServerNames(settings.DisabledMCPJSONServers,
+			project.DisabledMCPJSONServers, project.EnabledMCPJSONServers)
+
+		config.Projects[path] = settings
+	}
+
+	if err := c.adapter.WriteClaudeCodeConfig(config); err != nil {
+		return fmt.Errorf("failed to write Claude Code config: %w", err)
+	}
+	return nil
+}
+
+// Lock takes the config file's advisory lock
+func (c *ClaudeCodeDestination) Lock(timeout time.Duration) (func() error, error) {
+	lock, err := AcquireFileLock(c.adapter.ConfigPath(), timeout)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Unlock, nil
+}
+
+// Exists checks if the Claude Code config file exists
+func (c *ClaudeCodeDestination) Exists() bool {
+	_, err := os.Stat(c.adapter.ConfigPath())
+	return err == nil
+}
+
+// SupportsBackup returns true
+func (c *ClaudeCodeDestination) SupportsBackup() bool {
+	return true
+}
+
+// Backup copies the current config file next to it with a timestamp suffix
+func (c *ClaudeCodeDestination) Backup() (string, error) {
+	path := c.adapter.ConfigPath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil // No backup needed
+		}
+		return "", err
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	backupPath := filepath.Join(filepath.Dir(path),
+		fmt.Sprintf("%s.%s.backup", filepath.Base(path), timestamp))
+
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return "", err
+	}
+
+	return backupPath, nil
+}
+
+// hasMCPJSON reports whether a project has a .mcp.json that Claude Code loads itself
+func hasMCPJSON(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, ".mcp.json"))
+	return err == nil
+}
+
+// mergeServerNames adds names in add to existing and drops names in remove
+func mergeServerNames(existing, add, remove []string) []string {
+	drop := make(map[string]bool, len(add)+len(remove))
+	for _, name := range remove {
+		drop[name] = true
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, name := range append(append([]string{}, existing...), add...) {
+		if drop[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
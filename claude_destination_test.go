@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClaudeCodeDestination(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "claude-code-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Project whose servers live in .mcp.json and one with a plain mcp.json
+	mcpJSONProject := filepath.Join(tmpDir, "shared")
+	localProject := filepath.Join(tmpDir, "local")
+	for _, dir := range []string{mcpJSONProject, localProject} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(mcpJSONProject, ".mcp.json"), []byte(`{"mcpServers":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(tmpDir, ".claude.json")
+	existing := `{
+  "numStartups": 42,
+  "theme": "dark",
+  "mcpServers": {
+    "stale": {"type": "stdio", "command": "old"}
+  },
+  "projects": {
+    "` + mcpJSONProject + `": {
+      "history": [{"display": "hello"}],
+      "allowedTools": ["Bash"],
+      "enabledMcpjsonServers": ["approved-by-user"],
+      "disabledMcpjsonServers": ["shared-off"]
+    },
+    "/untouched": {"history": []}
+  }
+}`
+	if err := os.WriteFile(configPath, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddServer("global", ServerConfig{Transport: "stdio", Command: "npx", Args: []string{"server"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.RegisterProject(mcpJSONProject, ProjectConfig{
+		Name: "shared",
+		Servers: map[string]ServerWithMetadata{
+			"shared-on":  {ServerConfig: ServerConfig{Transport: "stdio", Command: "a"}, Internal: InternalMetadata{Enabled: true}},
+			"shared-off": {ServerConfig: ServerConfig{Transport: "stdio", Command: "b"}, Internal: InternalMetadata{Enabled: true}},
+			"rejected":   {ServerConfig: ServerConfig{Transport: "stdio", Command: "c"}, Internal: InternalMetadata{Enabled: false}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.RegisterProject(localProject, ProjectConfig{
+		Name: "local",
+		Servers: map[string]ServerWithMetadata{
+			"local-server": {ServerConfig: ServerConfig{Transport: "sse", URL: "http://localhost:3000"}, Internal: InternalMetadata{Enabled: true}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := NewClaudeCodeDestination(NewClaudeCodeAdapterWithPath(configPath))
+	result, err := e.SyncTo(context.Background(), dest, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Sync was not successful: %+v", result.Errors)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+
+	if written["numStartups"] != float64(42) || written["theme"] != "dark" {
+		t.Errorf("Unmodeled top-level fields were not preserved: %s", data)
+	}
+
+	servers := written["mcpServers"].(map[string]interface{})
+	if _, ok := servers["stale"]; ok {
+		t.Error("Expected stale global server to be replaced")
+	}
+	global, ok := servers["global"].(map[string]interface{})
+	if !ok || global["type"] != "stdio" || global["command"] != "npx" {
+		t.Errorf("Expected global server in Claude Code format, got %v", servers["global"])
+	}
+
+	projects := written["projects"].(map[string]interface{})
+	if _, ok := projects["/untouched"]; !ok {
+		t.Error("Expected unregistered project to be preserved")
+	}
+
+	shared := projects[mcpJSONProject].(map[string]interface{})
+	if _, ok := shared["history"]; !ok {
+		t.Error("Expected project history to be preserved")
+	}
+	if _, ok := shared["mcpServers"]; ok {
+		t.Error(".mcp.json servers should not be copied into mcpServers")
+	}
+	assertStrings(t, shared["enabledMcpjsonServers"], []string{"approved-by-user", "shared-off", "shared-on"})
+	assertStrings(t, shared["disabledMcpjsonServers"], []string{"rejected"})
+
+	local := projects[localProject].(map[string]interface{})
+	localServers, ok := local["mcpServers"].(map[string]interface{})
+	if !ok || localServers["local-server"] == nil {
+		t.Errorf("Expected local project servers in mcpServers, got %v", local)
+	}
+
+	// Disabling or removing a project's last server clears it from Claude Code
+	for _, projectServers := range []map[string]ServerWithMetadata{
+		{"local-server": {ServerConfig: ServerConfig{Transport: "sse", URL: "http://localhost:3000"}, Internal: InternalMetadata{Enabled: false}}},
+		{},
+	} {
+		if err := e.RegisterProject(localProject, ProjectConfig{Name: "local", Servers: map[string]ServerWithMetadata{
+			"local-server": {ServerConfig: ServerConfig{Transport: "sse", URL: "http://localhost:3000"}, Internal: InternalMetadata{Enabled: true}},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+			t.Fatalf("SyncTo failed: %v", err)
+		}
+		if err := e.RegisterProject(localProject, ProjectConfig{Name: "local", Servers: projectServers}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+			t.Fatalf("SyncTo failed: %v", err)
+		}
+		data, err = os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		written = nil
+		if err := json.Unmarshal(data, &written); err != nil {
+			t.Fatal(err)
+		}
+		local = written["projects"].(map[string]interface{})[localProject].(map[string]interface{})
+		if localServers, _ := local["mcpServers"].(map[string]interface{}); len(localServers) != 0 {
+			t.Errorf("Expected local project servers to be cleared, got %v", local["mcpServers"])
+		}
+	}
+
+	// The adapter round-trips the file without losing anything
+	adapter := NewClaudeCodeAdapterWithPath(configPath)
+	config, err := adapter.ReadClaudeCodeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.MCPServers["global"].Transport != "stdio" {
+		t.Errorf("Expected transport to be read from type, got %q", config.MCPServers["global"].Transport)
+	}
+	if err := adapter.WriteClaudeCodeConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	roundTrip, _ := os.ReadFile(configPath)
+	var reread map[string]interface{}
+	if err := json.Unmarshal(roundTrip, &reread); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(written, reread) {
+		t.Errorf("Round trip changed the config:\nbefore: %s\nafter:  %s", data, roundTrip)
+	}
+}
+
+func assertStrings(t *testing.T, got interface{}, want []string) {
+	t.Helper()
+	list, _ := got.([]interface{})
+	var names []string
+	for _, v := range list {
+		names = append(names, v.(string))
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
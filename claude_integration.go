@@ -35,6 +35,21 @@ func NewClaudeCodeAdapter() (*ClaudeCodeAdapter, error) {
 	}, nil
 }
 
+// NewClaudeCodeAdapterWithPath creates an adapter for a specific config file.
+// The claude executable is optional; only validation and testing need it.
+func NewClaudeCodeAdapterWithPath(configPath string) *ClaudeCodeAdapter {
+	sdkPath, _ := exec.LookPath("claude")
+	return &ClaudeCodeAdapter{
+		configPath: expandPath(configPath),
+		sdkPath:    sdkPath,
+	}
+}
+
+// ConfigPath returns the path of the Claude Code config file
+func (c *ClaudeCodeAdapter) ConfigPath() string {
+	return c.configPath
+}
+
 // ValidateServerConfig validates a server config using Claude Code SDK
 func (c *ClaudeCodeAdapter) ValidateServerConfig(name string, config ServerConfig) error {
 	// Create temporary config file
@@ -169,7 +184,9 @@ type ClaudeCodeConfig struct {
 	MCPServers map[string]ServerConfig    `json:"mcpServers"`
 	Projects   map[string]ProjectSettings `json:"projects"`
 	Theme      string                     `json:"theme,omitempty"`
-	// ... other Claude Code specific fields
+
+	// Extra holds fields not modeled above so they survive a read-write cycle
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // ProjectSettings represents Claude Code project-specific settings
@@ -180,6 +197,178 @@ type ProjectSettings struct {
 	EnabledMCPJSONServers      []string                `json:"enabledMcpjsonServers,omitempty"`
 	EnableAllProjectMCPServers bool                    `json:"enableAllProjectMcpServers,omitempty"`
 	DontCrawlDirectory         bool                    `json:"dontCrawlDirectory,omitempty"`
+
+	// Extra holds fields not modeled above so they survive a read-write cycle
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// claudeCodeServer is a server entry as Claude Code stores it, with the
+// transport under "type"
+type claudeCodeServer struct {
+	Type      string            `json:"type,omitempty"`
+	Transport string            `json:"transport,omitempty"` // Written by older adapter versions
+	Command   string            `json:"command,omitempty"`
+	Args      []string          `json:"args,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	URL       string            `json:"url,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// toClaudeCodeServers converts engine servers to Claude Code's format
+func toClaudeCodeServers(servers map[string]ServerConfig) map[string]claudeCodeServer {
+	if servers == nil {
+		return nil
+	}
+
+	result := make(map[string]claudeCodeServer, len(servers))
+	for name, server := range servers {
+		serverType := server.Transport
+		if serverType == "" && server.Command != "" {
+			serverType = "stdio"
+		}
+		result[name] = claudeCodeServer{
+			Type:    serverType,
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.Headers,
+		}
+	}
+	return result
+}
+
+// fromClaudeCodeServers converts Claude Code server entries to engine servers
+func fromClaudeCodeServers(servers map[string]claudeCodeServer) map[string]ServerConfig {
+	if servers == nil {
+		return nil
+	}
+
+	result := make(map[string]ServerConfig, len(servers))
+	for name, server := range servers {
+		transport := server.Type
+		if transport == "" {
+			transport = server.Transport
+		}
+		if transport == "" && server.Command != "" {
+			transport = "stdio"
+		}
+		result[name] = ServerConfig{
+			Transport: transport,
+			Command:   server.Command,
+			Args:      server.Args,
+			Env:       server.Env,
+			URL:       server.URL,
+			Headers:   server.Headers,
+		}
+	}
+	return result
+}
+
+// withExtraFields merges unmodeled fields back into marshaled JSON
+func withExtraFields(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, modeled := fields[key]; !modeled {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// extraFields returns the fields of a JSON object that are not in known
+func extraFields(data []byte, known ...string) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, key := range known {
+		delete(fields, key)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// MarshalJSON writes servers in Claude Code's format and keeps unmodeled fields
+func (c ClaudeCodeConfig) MarshalJSON() ([]byte, error) {
+	type plain ClaudeCodeConfig
+	data, err := json.Marshal(struct {
+		plain
+		MCPServers map[string]claudeCodeServer `json:"mcpServers"`
+	}{
+		plain:      plain(c),
+		MCPServers: toClaudeCodeServers(c.MCPServers),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withExtraFields(data, c.Extra)
+}
+
+// UnmarshalJSON reads servers in Claude Code's format and keeps unmodeled fields
+func (c *ClaudeCodeConfig) UnmarshalJSON(data []byte) error {
+	type plain ClaudeCodeConfig
+	aux := struct {
+		*plain
+		MCPServers map[string]claudeCodeServer `json:"mcpServers"`
+	}{plain: (*plain)(c)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	c.MCPServers = fromClaudeCodeServers(aux.MCPServers)
+
+	extra, err := extraFields(data, "mcpServers", "projects", "theme")
+	if err != nil {
+		return err
+	}
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON writes servers in Claude Code's format and keeps unmodeled fields
+func (p ProjectSettings) MarshalJSON() ([]byte, error) {
+	type plain ProjectSettings
+	data, err := json.Marshal(struct {
+		plain
+		MCPServers map[string]claudeCodeServer `json:"mcpServers,omitempty"`
+	}{
+		plain:      plain(p),
+		MCPServers: toClaudeCodeServers(p.MCPServers),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withExtraFields(data, p.Extra)
+}
+
+// UnmarshalJSON reads servers in Claude Code's format and keeps unmodeled fields
+func (p *ProjectSettings) UnmarshalJSON(data []byte) error {
+	type plain ProjectSettings
+	aux := struct {
+		*plain
+		MCPServers map[string]claudeCodeServer `json:"mcpServers"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.MCPServers = fromClaudeCodeServers(aux.MCPServers)
+
+	extra, err := extraFields(data, "mcpServers", "allowedTools", "disabledMcpjsonServers",
+		"enabledMcpjsonServers", "enableAllProjectMcpServers", "dontCrawlDirectory")
+	if err != nil {
+		return err
+	}
+	p.Extra = extra
+	return nil
 }
 
 // ReadClaudeCodeConfig reads the Claude Code configuration
@@ -226,10 +415,5 @@ func (c *ClaudeCodeAdapter) WriteClaudeCodeConfig(config *ClaudeCodeConfig) erro
 		return err
 	}
 
-	tmpFile := c.configPath + ".tmp"
-	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
-		return err
-	}
-
-	return os.Rename(tmpFile, c.configPath)
+	return WriteFileAtomic(c.configPath, data, DefaultFileMode)
 }
@@ -30,6 +30,7 @@ func main() {
 		idleTimeout   = flag.Duration("idle-timeout", 0, "idle timeout (0 = disabled)")
 		version       = flag.Bool("version", false, "show version information")
 		systemd       = flag.Bool("systemd", false, "enable systemd integration")
+		rotateKey     = flag.Bool("rotate-key", false, "re-encrypt storage with a new key, replacing the encryption key file, and exit")
 	)
 
 	flag.Parse()
@@ -81,6 +82,14 @@ func main() {
 		log.Fatalf("Failed to create daemon: %v", err)
 	}
 
+	if *rotateKey {
+		if err := d.RotateEncryptionKey(); err != nil {
+			log.Fatalf("Failed to rotate encryption key: %v", err)
+		}
+		log.Println("Encryption key rotated")
+		return
+	}
+
 	log.Printf("Starting agent-master-daemon %s", Version)
 	if err := d.Run(ctx); err != nil {
 		log.Fatalf("Daemon failed: %v", err)
@@ -13,13 +13,36 @@ import (
 func (e *engineImpl) LoadConfig(path string) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	defer e.scheduler.notify()
 
+	previousPath := e.configPath
 	e.configPath = path
 
 	// If a file path is provided and exists, load from file
 	if path != "" {
 		expandedPath := expandPath(path)
 		if data, err := os.ReadFile(expandedPath); err == nil {
+			// Config files are sealed with the storage key when encrypted
+			if _, sealed := parseSealed(data); sealed {
+				encrypted, ok := e.getStorage().(*EncryptedStorage)
+				if !ok {
+					e.configPath = previousPath
+					return fmt.Errorf("config file %s is encrypted and the engine has no encryption key", path)
+				}
+				opened, err := encrypted.decrypt(Keys.Config(), data)
+				if err != nil {
+					e.configPath = previousPath
+					return fmt.Errorf("failed to load config from file: %w", err)
+				}
+				data = opened
+			}
+
+			data, migrated, err := e.upgradeConfig(data)
+			if err != nil {
+				e.configPath = previousPath
+				return fmt.Errorf("failed to load config from file: %w", err)
+			}
+
 			// Parse the config from file
 			var fileConfig Config
 			if err := json.Unmarshal(data, &fileConfig); err != nil {
@@ -36,6 +59,11 @@ func (e *engineImpl) LoadConfig(path string) error {
 
 			// DO NOT save to storage - file is the source of truth
 			// This was causing the CLI overwriting bug
+			if migrated {
+				if err := e.saveConfigNoLock(); err != nil {
+					return fmt.Errorf("failed to save migrated config: %w", err)
+				}
+			}
 
 			// Emit event
 			e.eventBus.emit(EventConfigLoaded, ConfigChange{
@@ -48,12 +76,27 @@ func (e *engineImpl) LoadConfig(path string) error {
 		}
 	}
 
-	// Fall back to loading from storage
-	if err := LoadJSON(e.storage, Keys.Config(), &e.config); err != nil {
+	// Fall back to loading from storage, replacing any unsaved changes
+	var storedConfig Config
+	migrated, err := e.loadStoredConfig(&storedConfig)
+	if err != nil {
 		// If not found, that's OK - we'll use defaults
 		if !isNotFoundError(err) {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
+	} else {
+		e.config = &storedConfig
+		if e.config.Servers == nil {
+			e.config.Servers = make(map[string]ServerWithMetadata)
+		}
+		if e.config.Targets == nil {
+			e.config.Targets = make(map[string]TargetConfig)
+		}
+		if migrated {
+			if err := e.saveConfigNoLock(); err != nil {
+				return fmt.Errorf("failed to save migrated config: %w", err)
+			}
+		}
 	}
 
 	// Emit event
@@ -75,7 +118,13 @@ func (e *engineImpl) SaveConfig() error {
 }
 
 // saveConfigNoLock saves config without acquiring lock (caller must hold lock)
-func (e *engineImpl) saveConfigNoLock() error {
+func (e *engineImpl) saveConfigNoLock() (err error) {
+	// Listings cached for the stored config don't match one that failed to save
+	defer func() { e.unsavedConfig.Store(err != nil) }()
+
+	// Configs written from the current types are in the current schema
+	stampConfigVersion(e.config)
+
 	// If we have a config file path, save to file instead of storage
 	if e.configPath != "" {
 		expandedPath := expandPath(e.configPath)
@@ -84,12 +133,34 @@ func (e *engineImpl) saveConfigNoLock() error {
 			return fmt.Errorf("failed to marshal config: %w", err)
 		}
 
-		if err := os.WriteFile(expandedPath, data, 0644); err != nil {
+		// Keep secrets out of the file when storage is encrypted. The
+		// config is sealed as Keys.Config(), like file storage seals it.
+		if encrypted, ok := e.getStorage().(*EncryptedStorage); ok {
+			if data, err = encrypted.encrypt(Keys.Config(), data); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+
+		// Write atomically, keeping symlinks and permissions; new files
+		// holding tokens get SecretFileMode
+		if err := WriteFileAtomic(expandedPath, data, DefaultFileMode); err != nil {
 			return fmt.Errorf("failed to write config file: %w", err)
 		}
+	} else if revisioned, ok := e.getStorage().(RevisionedStorage); ok {
+		// Only overwrite the config this engine loaded, so concurrent
+		// writers get a ConflictError instead of losing edits
+		data, err := json.MarshalIndent(e.config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		revision, err := revisioned.WriteIfRevision(Keys.Config(), data, e.configRevision)
+		if err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		e.configRevision = revision
 	} else {
 		// Fall back to storage backend
-		if err := SaveJSON(e.storage, Keys.Config(), e.config); err != nil {
+		if err := SaveJSON(e.getStorage(), Keys.Config(), e.config); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 	}
@@ -103,6 +174,112 @@ func (e *engineImpl) saveConfigNoLock() error {
 	return nil
 }
 
+// loadStoredConfig unmarshals the stored config into v, migrating it to
+// the current schema first, and remembers its revision when the storage
+// supports conditional writes. It reports whether the config was migrated
+// and so needs saving.
+func (e *engineImpl) loadStoredConfig(v interface{}) (bool, error) {
+	var data []byte
+	var err error
+	if revisioned, ok := e.getStorage().(RevisionedStorage); ok {
+		var revision string
+		data, revision, err = revisioned.ReadRevision(Keys.Config())
+		if err != nil {
+			if isNotFoundError(err) {
+				e.configRevision = ""
+			}
+			return false, err
+		}
+		e.configRevision = revision
+	} else if data, err = e.getStorage().Read(Keys.Config()); err != nil {
+		return false, err
+	}
+
+	data, migrated, err := e.upgradeConfig(data)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+	e.unsavedConfig.Store(false)
+	return migrated, nil
+}
+
+// watchStoredConfig reloads the config whenever another engine sharing the
+// storage saves it. Revisions are needed to tell those saves from our own.
+func (e *engineImpl) watchStoredConfig() error {
+	storage := e.getStorage()
+	if _, ok := storage.(RevisionedStorage); !ok {
+		return nil
+	}
+	unwatch, err := storage.Watch(Keys.Config(), func([]byte) {
+		e.reloadStoredConfig()
+	})
+	e.unwatchConfig = unwatch
+	return err
+}
+
+// reloadStoredConfig replaces the config with the stored one if someone else
+// saved it since this engine last loaded or saved it
+func (e *engineImpl) reloadStoredConfig() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// A config file, when set, is the source of truth
+	if e.configPath != "" {
+		return
+	}
+
+	// Handlers may run late, so check what is stored now
+	revisioned, ok := e.getStorage().(RevisionedStorage)
+	if !ok {
+		return
+	}
+	data, revision, err := revisioned.ReadRevision(Keys.Config())
+	if err != nil || revision == e.configRevision {
+		return
+	}
+
+	// Migrated in memory only; the engine that saved it may be older
+	data, _, err = e.upgradeConfig(data)
+	if err != nil {
+		e.eventBus.emit(EventWarning, fmt.Sprintf("failed to reload changed config: %v", err))
+		return
+	}
+	var stored Config
+	if err := json.Unmarshal(data, &stored); err != nil {
+		e.eventBus.emit(EventWarning, fmt.Sprintf("failed to reload changed config: %v", err))
+		return
+	}
+	if stored.Servers == nil {
+		stored.Servers = make(map[string]ServerWithMetadata)
+	}
+	if stored.Targets == nil {
+		stored.Targets = make(map[string]TargetConfig)
+	}
+	e.config = &stored
+	e.configRevision = revision
+	e.unsavedConfig.Store(false)
+
+	e.eventBus.emit(EventConfigLoaded, ConfigChange{
+		Type:      "config-reloaded",
+		Timestamp: time.Now(),
+		Source:    "storage",
+	})
+
+	// Sync, watch the config's projects and reschedule. Auto-sync checks
+	// that it is running under its own lock, which can't be taken while
+	// holding e.mu.
+	if e.autoSync != nil {
+		go e.autoSync.refreshProjectWatches()
+		go e.autoSync.debouncedSync()
+	}
+	if e.scheduler != nil {
+		e.scheduler.notify()
+	}
+}
+
 // GetConfig returns a copy of the current configuration
 func (e *engineImpl) GetConfig() (*Config, error) {
 	e.mu.RLock()
@@ -133,10 +310,16 @@ func (e *engineImpl) SetConfig(config *Config) error {
 	e.config = config
 	err := e.saveConfigNoLock()
 
-	// Trigger auto-sync if enabled
-	if err == nil && e.autoSync != nil && e.autoSync.isRunning {
+	// Trigger auto-sync and watch the config's projects if it is running
+	if err == nil && e.autoSync != nil {
+		go e.autoSync.refreshProjectWatches()
 		go e.autoSync.debouncedSync()
 	}
 
+	// Let the scheduler pick up job changes
+	if err == nil && e.scheduler != nil {
+		e.scheduler.notify()
+	}
+
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -163,3 +164,46 @@ func TestLoadConfigStorageBackend(t *testing.T) {
 		t.Error("Config should not be nil")
 	}
 }
+
+func TestSaveConfigFileAtomically(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes and symlinks differ on Windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config.json")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if err := e.LoadConfig(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.AddServer("api", ServerConfig{
+		Transport: "stdio",
+		Command:   "api",
+		Env:       map[string]string{"API_TOKEN": "secret"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The symlink is kept and the new file holding a token isn't world-readable
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Expected the config symlink to be kept, got %v, %v", info, err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != SecretFileMode {
+		t.Errorf("Expected mode %o, got %o", SecretFileMode, info.Mode().Perm())
+	}
+}
@@ -0,0 +1,288 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// configMigration upgrades config documents from one schema version to the
+// next. Migrate works on the decoded JSON document, so it can read fields
+// the current Config type no longer has.
+type configMigration struct {
+	From        string
+	To          string
+	Description string
+	Migrate     func(doc map[string]interface{}) error
+}
+
+// AppliedConfigMigration records a migration applied to a config
+type AppliedConfigMigration struct {
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Description string    `json:"description,omitempty"`
+	AppliedAt   time.Time `json:"appliedAt"`
+	BackupID    string    `json:"backupId,omitempty"` // Backup of the config before migrating
+}
+
+// ConfigVersionError is returned when a config was written by a newer
+// major version of the engine, which this one can't read safely
+type ConfigVersionError struct {
+	Version   string
+	Supported string
+}
+
+func (e *ConfigVersionError) Error() string {
+	return fmt.Sprintf("config version %s is newer than supported version %s", e.Version, e.Supported)
+}
+
+var (
+	configMigrationsMu sync.RWMutex
+	configMigrations   = make(map[string]configMigration) // By From version
+)
+
+func init() {
+	for _, migration := range []configMigration{
+		{
+			From:        "0.0.0",
+			To:          "1.0.2",
+			Description: "Set the version of unversioned configs",
+			Migrate:     func(map[string]interface{}) error { return nil },
+		},
+		{
+			From:        "1.0.2",
+			To:          "1.1.0",
+			Description: "Move settings.backup.location to backupPath and drop empty legacy targets",
+			Migrate:     foldLegacySettings,
+		},
+	} {
+		if err := registerConfigMigration(migration); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// registerConfigMigration adds a migration to the registry. Configs are
+// upgraded one step at a time, from their version to DefaultConfigVersion,
+// so bump it along with each new migration. A version without a migration
+// of its own is upgraded by the migration with the next higher From version.
+func registerConfigMigration(migration configMigration) error {
+	if migration.Migrate == nil {
+		return fmt.Errorf("config migration %s -> %s has no Migrate function", migration.From, migration.To)
+	}
+	from, err := parseConfigVersion(migration.From)
+	if err != nil {
+		return err
+	}
+	to, err := parseConfigVersion(migration.To)
+	if err != nil {
+		return err
+	}
+	if compareConfigVersions(to, from) <= 0 {
+		return fmt.Errorf("config migration %s -> %s must upgrade the version", migration.From, migration.To)
+	}
+
+	configMigrationsMu.Lock()
+	defer configMigrationsMu.Unlock()
+
+	if _, exists := configMigrations[migration.From]; exists {
+		return fmt.Errorf("config migration from %s already registered", migration.From)
+	}
+	configMigrations[migration.From] = migration
+	return nil
+}
+
+// migrateConfigDocument upgrades a config document to DefaultConfigVersion,
+// recording backupID as the backup of the original. It returns the document
+// unchanged, with no applied migrations, if it is current or newer within
+// the same major version.
+func migrateConfigDocument(data []byte, backupID string) ([]byte, []AppliedConfigMigration, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep durations and counts exact
+	var doc map[string]interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	version, _ := doc["version"].(string)
+	if version == "" {
+		version = "0.0.0"
+	}
+	current, err := parseConfigVersion(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	supported, _ := parseConfigVersion(DefaultConfigVersion)
+	if current[0] > supported[0] {
+		return nil, nil, &ConfigVersionError{Version: version, Supported: DefaultConfigVersion}
+	}
+
+	var applied []AppliedConfigMigration
+	for compareConfigVersions(current, supported) < 0 {
+		migration, ok := nextConfigMigration(current)
+		if !ok {
+			break
+		}
+		if err := migration.Migrate(doc); err != nil {
+			return nil, nil, fmt.Errorf("config migration %s -> %s failed: %w", migration.From, migration.To, err)
+		}
+		applied = append(applied, AppliedConfigMigration{
+			From:        version,
+			To:          migration.To,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+			BackupID:    backupID,
+		})
+		version = migration.To
+		current, _ = parseConfigVersion(version)
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+
+	// Record what was applied in the document itself
+	var history []interface{}
+	if existing, ok := doc["migrations"].([]interface{}); ok {
+		history = existing
+	}
+	for _, migration := range applied {
+		history = append(history, migration)
+	}
+	doc["migrations"] = history
+	doc["version"] = version
+
+	migrated, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	return migrated, applied, nil
+}
+
+// nextConfigMigration returns the migration upgrading a config at version:
+// the one registered for it, or the one with the next higher From version
+func nextConfigMigration(version [3]int) (configMigration, bool) {
+	configMigrationsMu.RLock()
+	defer configMigrationsMu.RUnlock()
+
+	var candidates []configMigration
+	for _, migration := range configMigrations {
+		from, _ := parseConfigVersion(migration.From)
+		if compareConfigVersions(from, version) >= 0 {
+			candidates = append(candidates, migration)
+		}
+	}
+	if len(candidates) == 0 {
+		return configMigration{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, _ := parseConfigVersion(candidates[i].From)
+		b, _ := parseConfigVersion(candidates[j].From)
+		return compareConfigVersions(a, b) < 0
+	})
+	return candidates[0], true
+}
+
+// parseConfigVersion parses a "major.minor.patch" version; missing parts
+// are zero
+func parseConfigVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) > 3 {
+		return parsed, fmt.Errorf("invalid config version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("invalid config version %q", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
+
+func compareConfigVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// stampConfigVersion marks a config older than DefaultConfigVersion as
+// current, so it isn't migrated again when loaded
+func stampConfigVersion(config *Config) {
+	current, err := parseConfigVersion(config.Version)
+	supported, _ := parseConfigVersion(DefaultConfigVersion)
+	if err != nil || config.Version == "" || compareConfigVersions(current, supported) < 0 {
+		config.Version = DefaultConfigVersion
+	}
+}
+
+// foldLegacySettings moves the backup location alias to backupPath and
+// drops the legacy targets map when nothing is registered in it
+func foldLegacySettings(doc map[string]interface{}) error {
+	if settings, ok := doc["settings"].(map[string]interface{}); ok {
+		if backup, ok := settings["backup"].(map[string]interface{}); ok {
+			if location, ok := backup["location"].(string); ok {
+				if path, _ := backup["backupPath"].(string); path == "" && location != "" {
+					backup["backupPath"] = location
+				}
+				delete(backup, "location")
+			}
+		}
+	}
+
+	if targets, ok := doc["targets"].(map[string]interface{}); ok && len(targets) == 0 {
+		delete(doc, "targets")
+	}
+	return nil
+}
+
+// upgradeConfig migrates a config document to DefaultConfigVersion, backing
+// up the original before the migrated config can be saved. The backup is
+// named after the original's revision, so loading the same document again
+// doesn't back it up twice.
+func (e *engineImpl) upgradeConfig(data []byte) ([]byte, bool, error) {
+	backupID := fmt.Sprintf("pre-migration-%s", ContentRevision(data))
+	migrated, applied, err := migrateConfigDocument(data, backupID)
+	if err != nil || len(applied) == 0 {
+		return data, false, err
+	}
+	from, to := applied[0].From, applied[len(applied)-1].To
+
+	if _, err := e.getStorage().Read(fmt.Sprintf("backups/%s", backupID)); err != nil {
+		info := &BackupInfo{
+			ID:          backupID,
+			Path:        filepath.Join("backups", backupID+".json"),
+			Timestamp:   time.Now(),
+			Size:        int64(len(data)),
+			Type:        "pre-migration",
+			Description: fmt.Sprintf("Config version %s before migrating to %s", from, to),
+		}
+		if err := e.writeBackup(info, data); err != nil {
+			return data, false, fmt.Errorf("failed to back up config before migrating: %w", err)
+		}
+	}
+
+	e.eventBus.emit(EventConfigLoaded, ConfigChange{
+		Type:      "config-migrated",
+		Timestamp: time.Now(),
+		Source:    "migration",
+		Details: map[string]interface{}{
+			"from":     from,
+			"to":       to,
+			"backupId": backupID,
+		},
+	})
+	return migrated, true, nil
+}
@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigMigrations(t *testing.T) {
+	const legacy = `{
+  "version": "1.0.2",
+  "servers": {
+    "api": {"transport": "stdio", "command": "api", "internal": {"enabled": true}}
+  },
+  "settings": {
+    "backup": {"enabled": true, "location": "/var/backups/agent-master"},
+    "autoSync": {"enabled": false, "watchInterval": 1000000000}
+  },
+  "targets": {}
+}`
+	storage := NewMemoryStorage()
+	if err := storage.Write(Keys.Config(), []byte(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	engine, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := engine.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Version != DefaultConfigVersion {
+		t.Errorf("Expected version %s, got %s", DefaultConfigVersion, config.Version)
+	}
+	if config.Settings.Backup.BackupPath != "/var/backups/agent-master" {
+		t.Errorf("Expected the backup location to move to backupPath, got %q", config.Settings.Backup.BackupPath)
+	}
+	if _, err := engine.GetServer("api"); err != nil {
+		t.Errorf("Expected servers to survive migration: %v", err)
+	}
+
+	// The migration is recorded and points at a backup of the original
+	if len(config.Migrations) != 1 || config.Migrations[0].From != "1.0.2" {
+		t.Fatalf("Expected one recorded migration from 1.0.2, got %+v", config.Migrations)
+	}
+	backupID := config.Migrations[0].BackupID
+	original, err := storage.Read("backups/" + backupID)
+	if err != nil || string(original) != legacy {
+		t.Errorf("Expected backup %s to hold the original config, got %s, %v", backupID, original, err)
+	}
+
+	// The migrated config is saved, so reopening doesn't migrate again
+	var saved Config
+	if err := LoadJSON(storage, Keys.Config(), &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Version != DefaultConfigVersion || len(saved.Migrations) != 1 {
+		t.Errorf("Expected the migrated config to be saved, got version %s with %d migrations", saved.Version, len(saved.Migrations))
+	}
+	if _, err := NewEngine(WithStorage(storage)); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := storage.List("backup-meta:")
+	if len(backups) != 1 {
+		t.Errorf("Expected one pre-migration backup, got %v", backups)
+	}
+
+	// Restoring the pre-migration backup migrates it again
+	if err := engine.RestoreBackup(backupID); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	restored, _ := storage.Read(Keys.Config())
+	if strings.Contains(string(restored), `"location"`) {
+		t.Errorf("Expected the restored backup to be migrated, got %s", restored)
+	}
+	if config, _ := engine.GetConfig(); config.Settings.Backup.BackupPath != "/var/backups/agent-master" {
+		t.Errorf("Expected the restored backup location in backupPath, got %q", config.Settings.Backup.BackupPath)
+	}
+
+	// Config files are migrated in place
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"servers": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.LoadConfig(path); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Version != DefaultConfigVersion || len(saved.Migrations) != 2 {
+		t.Errorf("Expected the unversioned file to be migrated in two steps, got %s", data)
+	}
+}
+
+func TestConfigVersionTooNew(t *testing.T) {
+	storage := NewMemoryStorage()
+	if err := storage.Write(Keys.Config(), []byte(`{"version": "2.0.0", "servers": {}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var versionErr *ConfigVersionError
+	if _, err := NewEngine(WithStorage(storage)); !errors.As(err, &versionErr) {
+		t.Fatalf("Expected a ConfigVersionError, got %v", err)
+	}
+	if data, _ := storage.Read(Keys.Config()); !strings.Contains(string(data), `"2.0.0"`) {
+		t.Errorf("Expected the newer config to be left alone, got %s", data)
+	}
+
+	// Backups from a newer major version aren't restored either
+	current, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &BackupInfo{ID: "from-the-future", Type: "manual"}
+	if err := current.(*engineImpl).writeBackup(info, []byte(`{"version": "2.0.0", "servers": {}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := current.RestoreBackup(info.ID); !errors.As(err, &versionErr) {
+		t.Errorf("Expected restoring a newer backup to fail with a ConfigVersionError, got %v", err)
+	}
+
+	// Newer minor versions of the same major load as they are
+	if err := storage.Write(Keys.Config(), []byte(`{"version": "1.9.0", "servers": {}}`)); err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected a newer minor version to load: %v", err)
+	}
+	if config, _ := engine.GetConfig(); config.Version != "1.9.0" {
+		t.Errorf("Expected version 1.9.0 to be kept, got %s", config.Version)
+	}
+}
+
+func TestDeprecatedOptionAliases(t *testing.T) {
+	engine, err := NewEngine(WithStorage(NewMemoryStorage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("old", ServerConfig{Transport: "stdio", Command: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`{"mcpServers": {"new": {"command": "new"}}}`)
+	if err := engine.Import(data, ImportFormatJSON, ImportOptions{MergeMode: "replace"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.GetServer("old"); err == nil {
+		t.Error("Expected MergeMode replace to remove existing servers")
+	}
+
+	sync := SyncOptions{BackupFirst: true}
+	sync.normalize()
+	if !sync.CreateBackup {
+		t.Error("Expected BackupFirst to set CreateBackup")
+	}
+}
@@ -17,16 +17,23 @@ const (
 	EventSyncCompleted    EventType = "sync.completed"
 	EventSyncFailed       EventType = "sync.failed"
 	EventConflictDetected EventType = "sync.conflict"
+	EventDriftDetected    EventType = "sync.drift"
 
 	// Auto-Sync Events
 	EventAutoSyncStarted EventType = "autosync.started"
 	EventAutoSyncStopped EventType = "autosync.stopped"
 	EventFileChanged     EventType = "autosync.file.changed"
 
+	// Scheduler Events
+	EventJobCompleted EventType = "scheduler.job.completed"
+	EventJobFailed    EventType = "scheduler.job.failed"
+
 	// Project Events
 	EventProjectDiscovered EventType = "project.discovered"
 	EventProjectRegistered EventType = "project.registered"
 	EventProjectRemoved    EventType = "project.removed"
+	EventProjectUpdated    EventType = "project.updated"
+	EventProjectSynced     EventType = "project.synced"
 
 	// Error Events
 	EventError   EventType = "error"
@@ -54,7 +61,7 @@ const (
 	DefaultDebounceDelay  = 500  // milliseconds
 	DefaultMaxBackups     = 10
 	DefaultMaxSyncWorkers = 5
-	DefaultConfigVersion  = "1.0.2"
+	DefaultConfigVersion  = "1.1.0"
 )
 
 // File patterns
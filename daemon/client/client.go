@@ -557,6 +557,50 @@ func (c *Client) GetAutoSyncStatus(ctx context.Context) (*pb.AutoSyncStatus, err
 	return resp, err
 }
 
+// Scheduled jobs
+
+// ListScheduledJobs lists scheduled jobs with their next and last runs
+func (c *Client) ListScheduledJobs(ctx context.Context) ([]*pb.ScheduledJob, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	var resp *pb.ListScheduledJobsResponse
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, c.options.RequestTimeout)
+		defer cancel()
+		
+		var err error
+		resp, err = c.client.ListScheduledJobs(ctx, &emptypb.Empty{})
+		return err
+	})
+	
+	if err != nil {
+		return nil, err
+	}
+	
+	return resp.Jobs, nil
+}
+
+// TriggerScheduledJob runs a scheduled job immediately
+func (c *Client) TriggerScheduledJob(ctx context.Context, name string) (*pb.ScheduledJob, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	var resp *pb.ScheduledJob
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, c.options.RequestTimeout)
+		defer cancel()
+		
+		var err error
+		resp, err = c.client.TriggerScheduledJob(ctx, &pb.TriggerScheduledJobRequest{Name: name})
+		return err
+	})
+	
+	return resp, err
+}
+
 // Configuration
 
 // GetConfig retrieves the current configuration
@@ -690,6 +734,19 @@ func (c *Client) RestoreBackup(ctx context.Context, backupID string) error {
 	})
 }
 
+// Storage Migration
+
+// MigrateStorage copies the daemon's storage to another backend, switching
+// to it once verified if req.SwitchStorage is set
+func (c *Client) MigrateStorage(ctx context.Context, req *pb.MigrateStorageRequest) (*pb.MigrateStorageResponse, error) {
+	if err := c.ensureConnected(); err != nil {
+		return nil, err
+	}
+
+	// Migrations can outlast the request timeout and resume, so no retry
+	return c.client.MigrateStorage(ctx, req)
+}
+
 // Daemon Lifecycle
 
 // GetStatus retrieves the daemon status
@@ -5,12 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	engine "github.com/b-open-io/agent-master-engine"
 )
 
 // Config holds daemon configuration
 type Config struct {
 	// Storage
-	StoragePath string `json:"storage_path,omitempty"`
+	StoragePath    string `json:"storage_path,omitempty"`
+	StorageBackend string `json:"storage_backend,omitempty"` // "file" (default), "bolt" or "redis"
+	RedisAddress   string `json:"redis_address,omitempty"`
+	RedisPrefix    string `json:"redis_prefix,omitempty"`
+	CacheEntries   int    `json:"cache_entries,omitempty"` // Keys cached in memory, 0 for no cache
 	
 	// Network
 	SocketPath string `json:"socket_path,omitempty"`
@@ -23,9 +29,21 @@ type Config struct {
 	// Behavior
 	IdleTimeout   time.Duration `json:"idle_timeout,omitempty"`
 	EnableSystemd bool          `json:"enable_systemd,omitempty"`
+	LockTimeout   time.Duration `json:"lock_timeout,omitempty"` // Wait for file locks held by other processes
 	
 	// Security
-	AllowedClients []string `json:"allowed_clients,omitempty"`
+	AllowedClients    []string `json:"allowed_clients,omitempty"`
+	EncryptionKeyFile string   `json:"encryption_key_file,omitempty"` // Encrypt storage with this key
+}
+
+// configFilePath returns the config file the engine loads and saves, or ""
+// when the config is kept in the storage backend. Only the file backend keeps
+// it in config.json, and not when encrypted since the file is sealed then.
+func (c Config) configFilePath() string {
+	if (c.StorageBackend != "" && c.StorageBackend != "file") || c.EncryptionKeyFile != "" {
+		return ""
+	}
+	return filepath.Join(c.StoragePath, "config.json")
 }
 
 // SetDefaults sets default values for config
@@ -43,11 +61,18 @@ func (c *Config) SetDefaults() {
 		c.LogLevel = "info"
 	}
 	
+	if c.LockTimeout == 0 {
+		c.LockTimeout = engine.DefaultLockTimeout
+	}
+	
 	// Expand paths
 	c.StoragePath = expandPath(c.StoragePath)
 	if c.LogFile != "" {
 		c.LogFile = expandPath(c.LogFile)
 	}
+	if c.EncryptionKeyFile != "" {
+		c.EncryptionKeyFile = expandPath(c.EncryptionKeyFile)
+	}
 }
 
 // LoadFromFile loads config from JSON file
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
 	engine "github.com/b-open-io/agent-master-engine"
@@ -150,11 +151,81 @@ func autoSyncStatusFromEngine(status *engine.AutoSyncStatus) *pb.AutoSyncStatus
 		Running:         status.Running,
 		LastSync:        timestamppb.New(status.LastSync),
 		WatchIntervalMs: status.WatchInterval.Milliseconds(),
+		LastError:       status.LastError,
+		WatchedPaths:    status.WatchedPaths,
+		EventsReceived:  status.EventsReceived,
+		EventsIgnored:   status.EventsIgnored,
+		SyncsPerformed:  status.SyncsPerformed,
+		Destinations:    make(map[string]*pb.DestinationSyncStatus),
+	}
+	
+	// Only report a next sync while one is actually pending
+	if !status.NextSync.IsZero() {
+		result.NextSync = timestamppb.New(status.NextSync)
+	}
+	
+	for name, dest := range status.Destinations {
+		result.Destinations[name] = destinationSyncStatusToProto(dest)
+	}
+	
+	return result
+}
+
+func destinationSyncStatusToProto(status engine.DestinationSyncStatus) *pb.DestinationSyncStatus {
+	result := &pb.DestinationSyncStatus{
+		Name:            status.Name,
+		LastError:       status.LastError,
+		PendingDebounce: status.PendingDebounce,
+		ServersWritten:  int32(status.ServersWritten),
+		SyncCount:       int32(status.SyncCount),
+		FailureCount:    int32(status.FailureCount),
+	}
+	
+	if !status.LastAttempt.IsZero() {
+		result.LastAttempt = timestamppb.New(status.LastAttempt)
+	}
+	if !status.LastSuccess.IsZero() {
+		result.LastSuccess = timestamppb.New(status.LastSuccess)
+	}
+	
+	return result
+}
+
+func scheduledJobStatusToProto(job *engine.ScheduledJobStatus) *pb.ScheduledJob {
+	result := &pb.ScheduledJob{
+		Name:           job.Name,
+		Schedule:       job.Schedule,
+		Action:         job.Action,
+		Destinations:   job.Destinations,
+		Disabled:       job.Disabled,
+		LastDurationMs: job.LastDuration.Milliseconds(),
+		LastResult:     job.LastResult,
+		LastError:      job.LastError,
+		RunCount:       int32(job.RunCount),
+	}
+	
+	if !job.NextRun.IsZero() {
+		result.NextRun = timestamppb.New(job.NextRun)
+	}
+	if !job.LastRun.IsZero() {
+		result.LastRun = timestamppb.New(job.LastRun)
 	}
 	
 	return result
 }
 
+func migrationResultToProto(result *engine.MigrationResult) *pb.MigrateStorageResponse {
+	return &pb.MigrateStorageResponse{
+		Total:      int32(result.Total),
+		Copied:     result.Copied,
+		Unchanged:  int32(result.Unchanged),
+		Resumed:    result.Resumed,
+		Switched:   result.Switched,
+		DryRun:     result.DryRun,
+		DurationMs: result.Duration.Milliseconds(),
+	}
+}
+
 // Helper to convert server info from engine format
 func serverInfoToProto(info engine.ServerInfo) *pb.ServerInfo {
 	return &pb.ServerInfo{
@@ -325,4 +396,32 @@ func engineBackupInfoToPB(backup *engine.BackupInfo) *pb.BackupInfo {
 		CreatedAt:   timestamppb.New(backup.Timestamp),
 		SizeBytes:   backup.Size,
 	}
-}
\ No newline at end of file
+}
+
+func destinationInfoToProto(name, description string, available bool) *pb.DestinationInfo {
+	return &pb.DestinationInfo{
+		Name:        name,
+		Type:        destinationTypeToProto(name),
+		Description: description,
+		Available:   available,
+	}
+}
+
+func destinationTypeToProto(name string) pb.DestinationType {
+	switch {
+	case name == "claude" || name == "claude-code":
+		return pb.DestinationType_CLAUDE
+	case strings.HasPrefix(name, "vscode"):
+		return pb.DestinationType_VSCODE
+	case name == "cursor":
+		return pb.DestinationType_CURSOR
+	case name == "windsurf":
+		return pb.DestinationType_WINDSURF
+	case name == "zed":
+		return pb.DestinationType_ZED
+	case name == "generic-json":
+		return pb.DestinationType_FILE
+	default:
+		return pb.DestinationType_CUSTOM
+	}
+}
@@ -13,7 +13,10 @@ import (
 
 	engine "github.com/b-open-io/agent-master-engine"
 	pb "github.com/b-open-io/agent-master-engine/daemon/proto"
+	"github.com/b-open-io/agent-master-engine/presets"
+	redisstorage "github.com/b-open-io/agent-master-engine/storage/redis"
 	"github.com/coreos/go-systemd/v22/daemon"
+	goredis "github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
@@ -21,6 +24,40 @@ import (
 
 // Version variables are defined in version.go
 
+// boltStorageFile is the database in StoragePath used by the bolt backend
+const boltStorageFile = "agent-master.db"
+
+// defaultRedisPrefix is the key prefix of the redis backend
+const defaultRedisPrefix = "agent-master"
+
+// openStorage opens a storage backend by name, for the daemon's own storage
+// and as a migration destination
+func openStorage(backend, path, redisAddress, redisPrefix string) (engine.Storage, error) {
+	switch backend {
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("path is required for file storage")
+		}
+		return engine.NewFileStorage(path)
+	case "bolt":
+		if path == "" {
+			return nil, fmt.Errorf("path is required for bolt storage")
+		}
+		return engine.NewBoltStorage(path)
+	case "redis":
+		if redisAddress == "" {
+			return nil, fmt.Errorf("redis address is required for redis storage")
+		}
+		if redisPrefix == "" {
+			redisPrefix = defaultRedisPrefix
+		}
+		client := goredis.NewClient(&goredis.Options{Addr: redisAddress})
+		return redisstorage.New(client, redisPrefix), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", backend)
+	}
+}
+
 // Daemon represents the agent-master daemon
 type Daemon struct {
 	config    Config
@@ -34,6 +71,8 @@ type Daemon struct {
 	startTime    time.Time
 	connections  int64
 	lastActivity time.Time
+	definitions  map[string]presets.DestinationDefinition // Loaded from the definitions file
+	unavailable  map[string]presets.Preset                // Presets whose clients aren't installed
 	mu           sync.RWMutex
 	
 	// Shutdown
@@ -54,16 +93,38 @@ func New(config Config) (*Daemon, error) {
 	}
 	
 	// Create engine
-	eng, err := engine.NewEngine(
-		engine.WithFileStorage(config.StoragePath),
-	)
+	var storage engine.Option
+	switch config.StorageBackend {
+	case "", "file":
+		storage = engine.WithFileStorage(config.StoragePath)
+	case "bolt":
+		storage = engine.WithBoltStorage(filepath.Join(config.StoragePath, boltStorageFile))
+	case "redis":
+		redisStorage, err := openStorage("redis", "", config.RedisAddress, config.RedisPrefix)
+		if err != nil {
+			return nil, err
+		}
+		storage = engine.WithStorage(redisStorage)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", config.StorageBackend)
+	}
+	opts := []engine.Option{
+		storage,
+		engine.WithLockTimeout(config.LockTimeout),
+	}
+	if config.CacheEntries > 0 {
+		opts = append(opts, engine.WithCache(config.CacheEntries))
+	}
+	if config.EncryptionKeyFile != "" {
+		opts = append(opts, engine.WithEncryptionKeyFile(config.EncryptionKeyFile))
+	}
+	eng, err := engine.NewEngine(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create engine: %w", err)
 	}
 	
 	// Load existing config
-	configPath := filepath.Join(config.StoragePath, "config.json")
-	if err := eng.LoadConfig(configPath); err != nil && !os.IsNotExist(err) {
+	if err := eng.LoadConfig(config.configFilePath()); err != nil && !os.IsNotExist(err) {
 		logger.Error("Failed to load config", "error", err)
 	}
 	
@@ -77,6 +138,7 @@ func New(config Config) (*Daemon, error) {
 		lastActivity: time.Now(),
 		ctx:          ctx,
 		cancel:       cancel,
+		unavailable:  make(map[string]presets.Preset),
 	}
 	
 	// Register preset destinations
@@ -85,6 +147,9 @@ func New(config Config) (*Daemon, error) {
 		// Continue anyway - this is not fatal
 	}
 	
+	// Register user-defined destinations
+	d.loadDefinitions()
+	
 	return d, nil
 }
 
@@ -131,9 +196,12 @@ func (d *Daemon) Run(ctx context.Context) error {
 	reflection.Register(d.server)
 	
 	// Start background tasks
-	d.wg.Add(2)
+	d.wg.Add(5)
 	go d.idleMonitor()
 	go d.autoSyncMonitor()
+	go d.schedulerMonitor()
+	go d.definitionsMonitor()
+	go d.presetMonitor()
 	
 	// Systemd notification
 	if d.config.EnableSystemd {
@@ -211,6 +279,11 @@ func (d *Daemon) shutdown() {
 		d.logger.Info("Stopping auto-sync")
 		d.engine.StopAutoSync()
 	}
+	
+	// Release the storage's watches and locks
+	if err := d.engine.Close(); err != nil {
+		d.logger.Warn("Failed to close engine", "error", err)
+	}
 }
 
 // createListener creates the network listener
@@ -374,6 +447,34 @@ func (d *Daemon) autoSyncMonitor() {
 	<-d.ctx.Done()
 }
 
+// schedulerMonitor runs scheduled jobs if configured
+func (d *Daemon) schedulerMonitor() {
+	defer d.wg.Done()
+	
+	config, err := d.engine.GetConfig()
+	if err != nil {
+		d.logger.Error("Failed to get config for scheduler", "error", err)
+		return
+	}
+	
+	if !config.Settings.Schedule.Enabled {
+		return
+	}
+	
+	d.logger.Info("Starting scheduler", "jobs", len(config.Settings.Schedule.Jobs))
+	if err := d.engine.StartScheduler(); err != nil {
+		d.logger.Error("Failed to start scheduler", "error", err)
+		return
+	}
+	
+	// Wait for shutdown
+	<-d.ctx.Done()
+	
+	if err := d.engine.StopScheduler(); err != nil {
+		d.logger.Error("Failed to stop scheduler", "error", err)
+	}
+}
+
 // systemdWatchdog sends watchdog notifications
 func (d *Daemon) systemdWatchdog() {
 	defer d.wg.Done()
@@ -398,6 +499,32 @@ func (d *Daemon) systemdWatchdog() {
 	}
 }
 
+// RotateEncryptionKey re-encrypts the daemon's storage with a new key and
+// replaces the key file with it. The new key is saved next to the key file
+// before rotating, so it survives a rotation that fails part way.
+func (d *Daemon) RotateEncryptionKey() error {
+	keyFile := d.config.EncryptionKeyFile
+	if keyFile == "" {
+		return fmt.Errorf("storage is not encrypted")
+	}
+	key, err := engine.GenerateEncryptionKey()
+	if err != nil {
+		return err
+	}
+	pending := keyFile + ".new"
+	if err := engine.SaveKeyFile(pending, key); err != nil {
+		return fmt.Errorf("failed to save new key: %w", err)
+	}
+	if err := d.engine.RotateEncryptionKey(key); err != nil {
+		return fmt.Errorf("failed to rotate encryption key (new key kept in %s): %w", pending, err)
+	}
+	if err := os.Rename(pending, keyFile); err != nil {
+		return fmt.Errorf("failed to replace key file (new key kept in %s): %w", pending, err)
+	}
+	d.logger.Info("Rotated encryption key", "key_file", keyFile)
+	return nil
+}
+
 // Helper methods
 
 func (d *Daemon) getLockPath() string {
@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	engine "github.com/b-open-io/agent-master-engine"
+	pb "github.com/b-open-io/agent-master-engine/daemon/proto"
+)
+
+// newStorageDaemon creates a daemon with New, closing its engine on cleanup
+func newStorageDaemon(t *testing.T, config Config) *Daemon {
+	t.Helper()
+	config.LogLevel = "error"
+	d, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(func() {
+		d.cancel()
+		d.engine.Close()
+	})
+	return d
+}
+
+func TestBoltBackendStoresConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	storagePath := t.TempDir()
+
+	d := newStorageDaemon(t, Config{StoragePath: storagePath, StorageBackend: "bolt"})
+	if err := d.engine.AddServer("api", engine.ServerConfig{Transport: "stdio", Command: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	d.engine.Close()
+
+	if _, err := os.Stat(filepath.Join(storagePath, "config.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no config.json with the bolt backend, got %v", err)
+	}
+	storage, err := engine.NewBoltStorage(filepath.Join(storagePath, boltStorageFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	var stored engine.Config
+	if err := engine.LoadJSON(storage, engine.Keys.Config(), &stored); err != nil {
+		t.Fatalf("Expected the config in bolt: %v", err)
+	}
+	if _, ok := stored.Servers["api"]; !ok {
+		t.Errorf("Expected the server in the stored config, got %+v", stored.Servers)
+	}
+}
+
+func TestRestartAfterMigration(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	storagePath := t.TempDir()
+
+	d := newStorageDaemon(t, Config{StoragePath: storagePath})
+	if err := d.engine.AddServer("before", engine.ServerConfig{Transport: "stdio", Command: "before"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewService(d).MigrateStorage(context.Background(), &pb.MigrateStorageRequest{
+		Backend:       "bolt",
+		Path:          filepath.Join(storagePath, boltStorageFile),
+		SwitchStorage: true,
+	})
+	if err != nil {
+		t.Fatalf("MigrateStorage failed: %v", err)
+	}
+
+	// Edits after the switch go to bolt
+	if err := d.engine.AddServer("after", engine.ServerConfig{Transport: "stdio", Command: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	d.engine.Close()
+
+	// Restarting with the new backend loads the migrated config, not the
+	// config.json left from before the switch
+	restarted := newStorageDaemon(t, Config{StoragePath: storagePath, StorageBackend: "bolt"})
+	for _, name := range []string{"before", "after"} {
+		if _, err := restarted.engine.GetServer(name); err != nil {
+			t.Errorf("Expected %s after the restart: %v", name, err)
+		}
+	}
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	storagePath := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "storage.key")
+	oldKey, err := engine.GenerateEncryptionKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.SaveKeyFile(keyFile, oldKey); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{StoragePath: storagePath, EncryptionKeyFile: keyFile}
+
+	d := newStorageDaemon(t, config)
+	if err := d.engine.AddServer("api", engine.ServerConfig{Transport: "stdio", Command: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.RotateEncryptionKey(); err != nil {
+		t.Fatalf("RotateEncryptionKey failed: %v", err)
+	}
+	d.engine.Close()
+
+	// The key file holds the new key, and the old one no longer opens storage
+	if _, err := os.Stat(keyFile + ".new"); !os.IsNotExist(err) {
+		t.Errorf("Expected the pending key file to be renamed, got %v", err)
+	}
+	stale, err := engine.NewEngine(engine.WithFileStorage(storagePath), engine.WithEncryption(oldKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stale.LoadConfig(""); err == nil {
+		t.Error("Expected the old key to no longer open the config")
+	}
+	stale.Close()
+
+	restarted := newStorageDaemon(t, config)
+	if _, err := restarted.engine.GetServer("api"); err != nil {
+		t.Errorf("Expected the server after restarting with the new key: %v", err)
+	}
+}
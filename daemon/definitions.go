@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/b-open-io/agent-master-engine/presets"
+	"github.com/fsnotify/fsnotify"
+)
+
+// definitionsReloadDelay debounces bursts of writes to the definitions file
+const definitionsReloadDelay = 250 * time.Millisecond
+
+// definitionsPath returns the path of the user-defined destinations file
+func (d *Daemon) definitionsPath() string {
+	return filepath.Join(d.config.StoragePath, presets.DefinitionsFileName)
+}
+
+// loadDefinitions registers the destinations in the definitions file,
+// replacing changed ones and removing ones that were deleted from the file.
+// If the file is invalid the currently registered definitions are kept.
+func (d *Daemon) loadDefinitions() {
+	defs, err := presets.LoadDefinitions(d.definitionsPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		d.logger.Error("Failed to load destination definitions", "path", d.definitionsPath(), "error", err)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	loaded := make(map[string]presets.DestinationDefinition, len(defs))
+	for _, def := range defs {
+		previous, wasLoaded := d.definitions[def.Name]
+		if !wasLoaded {
+			if _, err := d.engine.GetDestination(def.Name); err == nil {
+				d.logger.Warn("Destination definition conflicts with a built-in destination", "name", def.Name)
+				continue
+			}
+		}
+		loaded[def.Name] = def
+		if wasLoaded && reflect.DeepEqual(previous, def) {
+			continue
+		}
+
+		dest, err := presets.NewDefinitionDestination(def)
+		if err != nil {
+			d.logger.Warn("Failed to create defined destination", "name", def.Name, "error", err)
+			delete(loaded, def.Name)
+			continue
+		}
+		if err := d.engine.RegisterDestination(def.Name, dest); err != nil {
+			d.logger.Warn("Failed to register defined destination", "name", def.Name, "error", err)
+			delete(loaded, def.Name)
+			continue
+		}
+		d.logger.Info("Registered defined destination", "name", def.Name, "path", def.Path(), "format", def.Format)
+	}
+
+	for name := range d.definitions {
+		if _, ok := loaded[name]; ok {
+			continue
+		}
+		if err := d.engine.RemoveDestination(name); err != nil {
+			d.logger.Warn("Failed to remove defined destination", "name", name, "error", err)
+			continue
+		}
+		d.logger.Info("Removed defined destination", "name", name)
+	}
+
+	d.definitions = loaded
+}
+
+// definitionsMonitor reloads destination definitions when the file changes
+func (d *Daemon) definitionsMonitor() {
+	defer d.wg.Done()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		d.logger.Error("Failed to watch destination definitions", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	// Watch the directory so the file can be created later or replaced atomically
+	if err := watcher.Add(d.config.StoragePath); err != nil {
+		d.logger.Error("Failed to watch destination definitions", "path", d.config.StoragePath, "error", err)
+		return
+	}
+
+	reload := time.NewTimer(definitionsReloadDelay)
+	reload.Stop()
+	defer reload.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Base(event.Name) != presets.DefinitionsFileName || event.Op == fsnotify.Chmod {
+				continue
+			}
+			reload.Reset(definitionsReloadDelay)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			d.logger.Warn("Destination definitions watcher error", "error", err)
+
+		case <-reload.C:
+			d.logger.Debug("Reloading destination definitions")
+			d.loadDefinitions()
+
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	engine "github.com/b-open-io/agent-master-engine"
+	"github.com/b-open-io/agent-master-engine/presets"
+)
+
+// newTestDaemon returns a daemon on an in-memory engine that has registered
+// nothing yet, with its storage path in a temporary directory
+func newTestDaemon(t *testing.T) *Daemon {
+	t.Helper()
+	eng, err := engine.NewEngine(engine.WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		config:      Config{StoragePath: t.TempDir()},
+		engine:      eng,
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ctx:         ctx,
+		cancel:      cancel,
+		unavailable: make(map[string]presets.Preset),
+	}
+	t.Cleanup(func() {
+		cancel()
+		d.wg.Wait()
+		eng.Close()
+	})
+	return d
+}
+
+func writeDefinitions(t *testing.T, d *Daemon, data string) {
+	t.Helper()
+	if err := os.WriteFile(d.definitionsPath(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	d := newTestDaemon(t)
+	builtin := engine.NewFileDestination("builtin", filepath.Join(t.TempDir(), "builtin.json"), engine.ExportFormatJSON)
+	if err := d.engine.RegisterDestination("builtin", builtin); err != nil {
+		t.Fatal(err)
+	}
+
+	// A missing file registers nothing
+	d.loadDefinitions()
+	if len(d.definitions) != 0 {
+		t.Errorf("Expected no definitions, got %v", d.definitions)
+	}
+
+	// Definitions are registered, except over built-in destinations
+	writeDefinitions(t, d, `{"destinations": [
+  {"name": "editor", "paths": {"default": "~/.editor/mcp.json"}, "serversPath": "mcpServers"},
+  {"name": "builtin", "paths": {"default": "~/.other/mcp.json"}, "serversPath": "mcpServers"}
+]}`)
+	d.loadDefinitions()
+	if _, err := d.engine.GetDestination("editor"); err != nil {
+		t.Errorf("Expected editor to be registered: %v", err)
+	}
+	if dest, _ := d.engine.GetDestination("builtin"); dest != builtin {
+		t.Error("Expected the built-in destination to be kept")
+	}
+	if _, ok := d.definitions["builtin"]; ok {
+		t.Error("Expected the conflicting definition to be skipped")
+	}
+
+	// An invalid file keeps what is registered
+	writeDefinitions(t, d, `{"destinations": [{"name": "broken"}]}`)
+	d.loadDefinitions()
+	if _, err := d.engine.GetDestination("editor"); err != nil {
+		t.Errorf("Expected editor to survive an invalid file: %v", err)
+	}
+
+	// Definitions removed from the file are unregistered
+	writeDefinitions(t, d, `{"destinations": []}`)
+	d.loadDefinitions()
+	if _, err := d.engine.GetDestination("editor"); err == nil {
+		t.Error("Expected editor to be removed")
+	}
+	if _, err := d.engine.GetDestination("builtin"); err != nil {
+		t.Errorf("Expected the built-in destination to stay registered: %v", err)
+	}
+}
+
+func TestDefinitionsMonitor(t *testing.T) {
+	d := newTestDaemon(t)
+	d.wg.Add(1)
+	go d.definitionsMonitor()
+
+	waitFor := func(name string, registered bool) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			_, err := d.engine.GetDestination(name)
+			if (err == nil) == registered {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %s to be registered=%v", name, registered)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// Give the watcher a moment to start before the file appears
+	time.Sleep(50 * time.Millisecond)
+	writeDefinitions(t, d, `{"destinations": [{"name": "editor", "paths": {"default": "~/.editor/mcp.json"}, "serversPath": "mcpServers"}]}`)
+	waitFor("editor", true)
+
+	if err := os.Remove(d.definitionsPath()); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("editor", false)
+}
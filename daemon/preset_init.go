@@ -1,55 +1,26 @@
 package daemon
 
 import (
+	"time"
+	
 	engine "github.com/b-open-io/agent-master-engine"
 	"github.com/b-open-io/agent-master-engine/presets"
 )
 
+// presetDetectionInterval is how often the daemon looks for newly installed clients
+const presetDetectionInterval = 30 * time.Second
+
 // registerPresetDestinations registers all available preset destinations with the engine
 func (d *Daemon) registerPresetDestinations() error {
 	d.logger.Info("Registering preset destinations")
 	
-	// Get all available presets
-	presetNames := []string{"claude", "vscode-mcp", "cursor", "generic-json"}
-	
-	// Also register common aliases
-	aliases := map[string]string{
-		"vscode": "vscode-mcp",  // Alias for backward compatibility
-	}
-	
-	// Register each preset
-	for _, name := range presetNames {
-		dest, err := presets.NewDestination(name)
-		if err != nil {
-			d.logger.Warn("Failed to create preset destination", "preset", name, "error", err)
-			continue
-		}
-		
-		if err := d.engine.RegisterDestination(name, dest); err != nil {
-			d.logger.Warn("Failed to register preset destination", "preset", name, "error", err)
-			continue
-		}
-		
-		d.logger.Debug("Registered preset destination", "name", name)
-	}
-	
-	// Register aliases
-	for alias, target := range aliases {
-		dest, err := presets.NewDestination(target)
-		if err != nil {
-			d.logger.Warn("Failed to create preset destination for alias", "alias", alias, "target", target, "error", err)
-			continue
-		}
-		
-		if err := d.engine.RegisterDestination(alias, dest); err != nil {
-			d.logger.Warn("Failed to register preset alias", "alias", alias, "error", err)
-			continue
-		}
-		
-		d.logger.Debug("Registered preset alias", "alias", alias, "target", target)
+	// Register presets whose clients are installed; the rest are checked
+	// again by presetMonitor
+	for _, name := range presets.ListPresets() {
+		d.registerPreset(name)
 	}
 	
-	// Register additional presets that might not be in the CommonPresets map
+	// Register additional presets that are not in the presets registry
 	// but are referenced in the CLI
 	additionalPresets := map[string]struct {
 		path   string
@@ -70,6 +41,14 @@ func (d *Daemon) registerPresetDestinations() error {
 		d.logger.Warn("Failed to register some custom destinations", "error", err)
 	}
 	
+	// Register Claude Code, which keeps global and per-project servers in ~/.claude.json
+	claudeCode := engine.NewClaudeCodeDestination(engine.NewClaudeCodeAdapterWithPath("~/.claude.json"))
+	if err := d.engine.RegisterDestination(claudeCode.GetID(), claudeCode); err != nil {
+		d.logger.Warn("Failed to register Claude Code destination", "error", err)
+	} else {
+		d.logger.Debug("Registered Claude Code destination", "description", claudeCode.GetDescription())
+	}
+	
 	// List all registered destinations
 	destinations := d.engine.ListDestinations()
 	d.logger.Info("Preset destinations registered", "count", len(destinations), "names", destinations)
@@ -77,6 +56,86 @@ func (d *Daemon) registerPresetDestinations() error {
 	return nil
 }
 
+// presetAliases are extra names for presets, kept for backward compatibility
+var presetAliases = map[string]string{
+	"vscode": "vscode-mcp",
+}
+
+// registerPreset registers a preset and its aliases if its client is
+// installed, and otherwise records it as unavailable
+func (d *Daemon) registerPreset(name string) bool {
+	preset, ok := presets.GetPreset(name)
+	if !ok {
+		return false
+	}
+	
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	
+	if !preset.Installed() {
+		if _, known := d.unavailable[name]; !known {
+			d.logger.Debug("Preset client not installed", "preset", name)
+		}
+		d.unavailable[name] = preset
+		return false
+	}
+	
+	dest, err := presets.NewDestination(name)
+	if err != nil {
+		d.logger.Warn("Failed to create preset destination", "preset", name, "error", err)
+		return false
+	}
+	if err := d.engine.RegisterDestination(name, dest); err != nil {
+		d.logger.Warn("Failed to register preset destination", "preset", name, "error", err)
+		return false
+	}
+	delete(d.unavailable, name)
+	d.logger.Debug("Registered preset destination", "name", name)
+	
+	for alias, target := range presetAliases {
+		if target != name {
+			continue
+		}
+		if err := d.engine.RegisterDestination(alias, dest); err != nil {
+			d.logger.Warn("Failed to register preset alias", "alias", alias, "error", err)
+			continue
+		}
+		d.logger.Debug("Registered preset alias", "alias", alias, "target", target)
+	}
+	
+	return true
+}
+
+// presetMonitor registers preset destinations as soon as their clients are installed
+func (d *Daemon) presetMonitor() {
+	defer d.wg.Done()
+	
+	ticker := time.NewTicker(presetDetectionInterval)
+	defer ticker.Stop()
+	
+	for {
+		select {
+		case <-ticker.C:
+			d.detectPresets()
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// detectPresets registers the presets that aren't registered yet and whose
+// clients are installed now
+func (d *Daemon) detectPresets() {
+	for _, name := range presets.ListPresets() {
+		if _, err := d.engine.GetDestination(name); err == nil {
+			continue
+		}
+		if d.registerPreset(name) {
+			d.logger.Info("Detected newly installed client", "preset", name)
+		}
+	}
+}
+
 // registerCustomDestinations registers destinations that aren't in the presets package
 func (d *Daemon) registerCustomDestinations(destinations map[string]struct {
 	path   string
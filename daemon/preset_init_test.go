@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/b-open-io/agent-master-engine/presets"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestPresetDetection(t *testing.T) {
+	d := newTestDaemon(t)
+	clientDir := filepath.Join(t.TempDir(), "editor")
+	if err := presets.RegisterPreset(presets.Preset{
+		Name:         "test-editor",
+		Description:  "Test editor",
+		DefaultPath:  filepath.Join(clientDir, "mcp.json"),
+		ConfigFormat: "flat",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	available := func() (bool, bool) {
+		t.Helper()
+		resp, err := NewService(d).ListDestinations(context.Background(), &emptypb.Empty{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		info, listed := resp.Destinations["test-editor"]
+		return listed, listed && info.Available
+	}
+
+	// Presets whose client isn't installed are listed as unavailable
+	if d.registerPreset("test-editor") {
+		t.Error("Expected the preset not to be registered before its client is installed")
+	}
+	if listed, ok := available(); !listed || ok {
+		t.Errorf("Expected test-editor to be listed as unavailable, got listed=%v available=%v", listed, ok)
+	}
+
+	// Detection picks the client up once it is installed
+	d.detectPresets()
+	if _, err := d.engine.GetDestination("test-editor"); err == nil {
+		t.Fatal("Expected detection to wait for the client")
+	}
+	if err := os.MkdirAll(clientDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	d.detectPresets()
+	if _, err := d.engine.GetDestination("test-editor"); err != nil {
+		t.Errorf("Expected the installed client to be registered: %v", err)
+	}
+	if listed, ok := available(); !listed || !ok {
+		t.Errorf("Expected test-editor to be listed as available, got listed=%v available=%v", listed, ok)
+	}
+	d.mu.RLock()
+	_, stillUnavailable := d.unavailable["test-editor"]
+	d.mu.RUnlock()
+	if stillUnavailable {
+		t.Error("Expected test-editor to leave the unavailable list")
+	}
+}
@@ -1615,12 +1615,18 @@ func (x *AutoSyncConfig) GetIgnorePatterns() []string {
 }
 
 type AutoSyncStatus struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Enabled         bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	Running         bool                   `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
-	LastSync        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
-	WatchIntervalMs int64                  `protobuf:"varint,4,opt,name=watch_interval_ms,json=watchIntervalMs,proto3" json:"watch_interval_ms,omitempty"`
-	LastError       string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	state           protoimpl.MessageState            `protogen:"open.v1"`
+	Enabled         bool                              `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Running         bool                              `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	LastSync        *timestamppb.Timestamp            `protobuf:"bytes,3,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
+	WatchIntervalMs int64                             `protobuf:"varint,4,opt,name=watch_interval_ms,json=watchIntervalMs,proto3" json:"watch_interval_ms,omitempty"`
+	LastError       string                            `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	NextSync        *timestamppb.Timestamp            `protobuf:"bytes,6,opt,name=next_sync,json=nextSync,proto3" json:"next_sync,omitempty"`
+	WatchedPaths    []string                          `protobuf:"bytes,7,rep,name=watched_paths,json=watchedPaths,proto3" json:"watched_paths,omitempty"`
+	EventsReceived  int64                             `protobuf:"varint,8,opt,name=events_received,json=eventsReceived,proto3" json:"events_received,omitempty"`
+	EventsIgnored   int64                             `protobuf:"varint,9,opt,name=events_ignored,json=eventsIgnored,proto3" json:"events_ignored,omitempty"`
+	SyncsPerformed  int64                             `protobuf:"varint,10,opt,name=syncs_performed,json=syncsPerformed,proto3" json:"syncs_performed,omitempty"`
+	Destinations    map[string]*DestinationSyncStatus `protobuf:"bytes,11,rep,name=destinations,proto3" json:"destinations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -1690,6 +1696,361 @@ func (x *AutoSyncStatus) GetLastError() string {
 	return ""
 }
 
+func (x *AutoSyncStatus) GetNextSync() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextSync
+	}
+	return nil
+}
+
+func (x *AutoSyncStatus) GetWatchedPaths() []string {
+	if x != nil {
+		return x.WatchedPaths
+	}
+	return nil
+}
+
+func (x *AutoSyncStatus) GetEventsReceived() int64 {
+	if x != nil {
+		return x.EventsReceived
+	}
+	return 0
+}
+
+func (x *AutoSyncStatus) GetEventsIgnored() int64 {
+	if x != nil {
+		return x.EventsIgnored
+	}
+	return 0
+}
+
+func (x *AutoSyncStatus) GetSyncsPerformed() int64 {
+	if x != nil {
+		return x.SyncsPerformed
+	}
+	return 0
+}
+
+func (x *AutoSyncStatus) GetDestinations() map[string]*DestinationSyncStatus {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+type DestinationSyncStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	LastAttempt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_attempt,json=lastAttempt,proto3" json:"last_attempt,omitempty"`
+	LastSuccess     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_success,json=lastSuccess,proto3" json:"last_success,omitempty"`
+	LastError       string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	PendingDebounce bool                   `protobuf:"varint,5,opt,name=pending_debounce,json=pendingDebounce,proto3" json:"pending_debounce,omitempty"`
+	ServersWritten  int32                  `protobuf:"varint,6,opt,name=servers_written,json=serversWritten,proto3" json:"servers_written,omitempty"`
+	SyncCount       int32                  `protobuf:"varint,7,opt,name=sync_count,json=syncCount,proto3" json:"sync_count,omitempty"`
+	FailureCount    int32                  `protobuf:"varint,8,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DestinationSyncStatus) Reset() {
+	*x = DestinationSyncStatus{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DestinationSyncStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DestinationSyncStatus) ProtoMessage() {}
+
+func (x *DestinationSyncStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DestinationSyncStatus.ProtoReflect.Descriptor instead.
+func (*DestinationSyncStatus) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DestinationSyncStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DestinationSyncStatus) GetLastAttempt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttempt
+	}
+	return nil
+}
+
+func (x *DestinationSyncStatus) GetLastSuccess() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSuccess
+	}
+	return nil
+}
+
+func (x *DestinationSyncStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DestinationSyncStatus) GetPendingDebounce() bool {
+	if x != nil {
+		return x.PendingDebounce
+	}
+	return false
+}
+
+func (x *DestinationSyncStatus) GetServersWritten() int32 {
+	if x != nil {
+		return x.ServersWritten
+	}
+	return 0
+}
+
+func (x *DestinationSyncStatus) GetSyncCount() int32 {
+	if x != nil {
+		return x.SyncCount
+	}
+	return 0
+}
+
+func (x *DestinationSyncStatus) GetFailureCount() int32 {
+	if x != nil {
+		return x.FailureCount
+	}
+	return 0
+}
+
+// Scheduled jobs
+type ScheduledJob struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Schedule       string                 `protobuf:"bytes,2,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	Action         string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Destinations   []string               `protobuf:"bytes,4,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	Disabled       bool                   `protobuf:"varint,5,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	NextRun        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_run,json=nextRun,proto3" json:"next_run,omitempty"`
+	LastRun        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_run,json=lastRun,proto3" json:"last_run,omitempty"`
+	LastDurationMs int64                  `protobuf:"varint,8,opt,name=last_duration_ms,json=lastDurationMs,proto3" json:"last_duration_ms,omitempty"`
+	LastResult     string                 `protobuf:"bytes,9,opt,name=last_result,json=lastResult,proto3" json:"last_result,omitempty"`
+	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	RunCount       int32                  `protobuf:"varint,11,opt,name=run_count,json=runCount,proto3" json:"run_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduledJob) Reset() {
+	*x = ScheduledJob{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledJob) ProtoMessage() {}
+
+func (x *ScheduledJob) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledJob.ProtoReflect.Descriptor instead.
+func (*ScheduledJob) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScheduledJob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ScheduledJob) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *ScheduledJob) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ScheduledJob) GetDestinations() []string {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+func (x *ScheduledJob) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *ScheduledJob) GetNextRun() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRun
+	}
+	return nil
+}
+
+func (x *ScheduledJob) GetLastRun() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRun
+	}
+	return nil
+}
+
+func (x *ScheduledJob) GetLastDurationMs() int64 {
+	if x != nil {
+		return x.LastDurationMs
+	}
+	return 0
+}
+
+func (x *ScheduledJob) GetLastResult() string {
+	if x != nil {
+		return x.LastResult
+	}
+	return ""
+}
+
+func (x *ScheduledJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ScheduledJob) GetRunCount() int32 {
+	if x != nil {
+		return x.RunCount
+	}
+	return 0
+}
+
+type ListScheduledJobsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*ScheduledJob        `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledJobsResponse) Reset() {
+	*x = ListScheduledJobsResponse{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledJobsResponse) ProtoMessage() {}
+
+func (x *ListScheduledJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledJobsResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListScheduledJobsResponse) GetJobs() []*ScheduledJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type TriggerScheduledJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerScheduledJobRequest) Reset() {
+	*x = TriggerScheduledJobRequest{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerScheduledJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerScheduledJobRequest) ProtoMessage() {}
+
+func (x *TriggerScheduledJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerScheduledJobRequest.ProtoReflect.Descriptor instead.
+func (*TriggerScheduledJobRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TriggerScheduledJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 // Configuration
 type Config struct {
 	state         protoimpl.MessageState   `protogen:"open.v1"`
@@ -1702,7 +2063,7 @@ type Config struct {
 
 func (x *Config) Reset() {
 	*x = Config{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[26]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1714,7 +2075,7 @@ func (x *Config) String() string {
 func (*Config) ProtoMessage() {}
 
 func (x *Config) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[26]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1727,7 +2088,7 @@ func (x *Config) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Config.ProtoReflect.Descriptor instead.
 func (*Config) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{26}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *Config) GetVersion() string {
@@ -1762,7 +2123,7 @@ type Settings struct {
 
 func (x *Settings) Reset() {
 	*x = Settings{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[27]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1774,7 +2135,7 @@ func (x *Settings) String() string {
 func (*Settings) ProtoMessage() {}
 
 func (x *Settings) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[27]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1787,7 +2148,7 @@ func (x *Settings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Settings.ProtoReflect.Descriptor instead.
 func (*Settings) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{27}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *Settings) GetAutoSync() *AutoSyncSettings {
@@ -1822,7 +2183,7 @@ type AutoSyncSettings struct {
 
 func (x *AutoSyncSettings) Reset() {
 	*x = AutoSyncSettings{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[28]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1834,7 +2195,7 @@ func (x *AutoSyncSettings) String() string {
 func (*AutoSyncSettings) ProtoMessage() {}
 
 func (x *AutoSyncSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[28]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1847,7 +2208,7 @@ func (x *AutoSyncSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AutoSyncSettings.ProtoReflect.Descriptor instead.
 func (*AutoSyncSettings) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{28}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *AutoSyncSettings) GetEnabled() bool {
@@ -1882,7 +2243,7 @@ type BackupSettings struct {
 
 func (x *BackupSettings) Reset() {
 	*x = BackupSettings{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[29]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1894,7 +2255,7 @@ func (x *BackupSettings) String() string {
 func (*BackupSettings) ProtoMessage() {}
 
 func (x *BackupSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[29]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1907,7 +2268,7 @@ func (x *BackupSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackupSettings.ProtoReflect.Descriptor instead.
 func (*BackupSettings) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{29}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *BackupSettings) GetEnabled() bool {
@@ -1941,7 +2302,7 @@ type ValidationSettings struct {
 
 func (x *ValidationSettings) Reset() {
 	*x = ValidationSettings{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[30]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1953,7 +2314,7 @@ func (x *ValidationSettings) String() string {
 func (*ValidationSettings) ProtoMessage() {}
 
 func (x *ValidationSettings) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[30]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1966,7 +2327,7 @@ func (x *ValidationSettings) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidationSettings.ProtoReflect.Descriptor instead.
 func (*ValidationSettings) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{30}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ValidationSettings) GetEnabled() bool {
@@ -1992,7 +2353,7 @@ type LoadConfigRequest struct {
 
 func (x *LoadConfigRequest) Reset() {
 	*x = LoadConfigRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[31]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2004,7 +2365,7 @@ func (x *LoadConfigRequest) String() string {
 func (*LoadConfigRequest) ProtoMessage() {}
 
 func (x *LoadConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[31]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2017,7 +2378,7 @@ func (x *LoadConfigRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LoadConfigRequest.ProtoReflect.Descriptor instead.
 func (*LoadConfigRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{31}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *LoadConfigRequest) GetPath() string {
@@ -2043,7 +2404,7 @@ type DaemonStatus struct {
 
 func (x *DaemonStatus) Reset() {
 	*x = DaemonStatus{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[32]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2055,7 +2416,7 @@ func (x *DaemonStatus) String() string {
 func (*DaemonStatus) ProtoMessage() {}
 
 func (x *DaemonStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[32]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2068,7 +2429,7 @@ func (x *DaemonStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DaemonStatus.ProtoReflect.Descriptor instead.
 func (*DaemonStatus) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{32}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *DaemonStatus) GetRunning() bool {
@@ -2130,7 +2491,7 @@ type SubscribeRequest struct {
 
 func (x *SubscribeRequest) Reset() {
 	*x = SubscribeRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[33]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2142,7 +2503,7 @@ func (x *SubscribeRequest) String() string {
 func (*SubscribeRequest) ProtoMessage() {}
 
 func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[33]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2155,7 +2516,7 @@ func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{33}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *SubscribeRequest) GetTypes() []EventType {
@@ -2182,7 +2543,7 @@ type Event struct {
 
 func (x *Event) Reset() {
 	*x = Event{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[34]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2194,7 +2555,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[34]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2207,7 +2568,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{34}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *Event) GetType() EventType {
@@ -2306,7 +2667,7 @@ type ConfigChangeEvent struct {
 
 func (x *ConfigChangeEvent) Reset() {
 	*x = ConfigChangeEvent{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[35]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2318,7 +2679,7 @@ func (x *ConfigChangeEvent) String() string {
 func (*ConfigChangeEvent) ProtoMessage() {}
 
 func (x *ConfigChangeEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[35]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2331,7 +2692,7 @@ func (x *ConfigChangeEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConfigChangeEvent.ProtoReflect.Descriptor instead.
 func (*ConfigChangeEvent) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{35}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ConfigChangeEvent) GetChangeType() string {
@@ -2366,7 +2727,7 @@ type SyncCompleteEvent struct {
 
 func (x *SyncCompleteEvent) Reset() {
 	*x = SyncCompleteEvent{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[36]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2378,7 +2739,7 @@ func (x *SyncCompleteEvent) String() string {
 func (*SyncCompleteEvent) ProtoMessage() {}
 
 func (x *SyncCompleteEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[36]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2391,7 +2752,7 @@ func (x *SyncCompleteEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SyncCompleteEvent.ProtoReflect.Descriptor instead.
 func (*SyncCompleteEvent) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{36}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *SyncCompleteEvent) GetDestination() string {
@@ -2425,7 +2786,7 @@ type ErrorEvent struct {
 
 func (x *ErrorEvent) Reset() {
 	*x = ErrorEvent{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[37]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2437,7 +2798,7 @@ func (x *ErrorEvent) String() string {
 func (*ErrorEvent) ProtoMessage() {}
 
 func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[37]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2450,7 +2811,7 @@ func (x *ErrorEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ErrorEvent.ProtoReflect.Descriptor instead.
 func (*ErrorEvent) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{37}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *ErrorEvent) GetMessage() string {
@@ -2477,7 +2838,7 @@ type AutoSyncEvent struct {
 
 func (x *AutoSyncEvent) Reset() {
 	*x = AutoSyncEvent{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[38]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2489,7 +2850,7 @@ func (x *AutoSyncEvent) String() string {
 func (*AutoSyncEvent) ProtoMessage() {}
 
 func (x *AutoSyncEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[38]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2502,7 +2863,7 @@ func (x *AutoSyncEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AutoSyncEvent.ProtoReflect.Descriptor instead.
 func (*AutoSyncEvent) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{38}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *AutoSyncEvent) GetStatus() string {
@@ -2529,7 +2890,7 @@ type ScanForProjectsRequest struct {
 
 func (x *ScanForProjectsRequest) Reset() {
 	*x = ScanForProjectsRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[39]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2541,7 +2902,7 @@ func (x *ScanForProjectsRequest) String() string {
 func (*ScanForProjectsRequest) ProtoMessage() {}
 
 func (x *ScanForProjectsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[39]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2554,7 +2915,7 @@ func (x *ScanForProjectsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScanForProjectsRequest.ProtoReflect.Descriptor instead.
 func (*ScanForProjectsRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{39}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *ScanForProjectsRequest) GetRootPath() string {
@@ -2573,7 +2934,7 @@ type ScanForProjectsResponse struct {
 
 func (x *ScanForProjectsResponse) Reset() {
 	*x = ScanForProjectsResponse{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[40]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2585,7 +2946,7 @@ func (x *ScanForProjectsResponse) String() string {
 func (*ScanForProjectsResponse) ProtoMessage() {}
 
 func (x *ScanForProjectsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[40]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2598,7 +2959,7 @@ func (x *ScanForProjectsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ScanForProjectsResponse.ProtoReflect.Descriptor instead.
 func (*ScanForProjectsResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{40}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *ScanForProjectsResponse) GetProjects() []*ProjectInfo {
@@ -2618,7 +2979,7 @@ type RegisterProjectRequest struct {
 
 func (x *RegisterProjectRequest) Reset() {
 	*x = RegisterProjectRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[41]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2630,7 +2991,7 @@ func (x *RegisterProjectRequest) String() string {
 func (*RegisterProjectRequest) ProtoMessage() {}
 
 func (x *RegisterProjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[41]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2643,7 +3004,7 @@ func (x *RegisterProjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterProjectRequest.ProtoReflect.Descriptor instead.
 func (*RegisterProjectRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{41}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *RegisterProjectRequest) GetPath() string {
@@ -2669,7 +3030,7 @@ type GetProjectConfigRequest struct {
 
 func (x *GetProjectConfigRequest) Reset() {
 	*x = GetProjectConfigRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[42]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2681,7 +3042,7 @@ func (x *GetProjectConfigRequest) String() string {
 func (*GetProjectConfigRequest) ProtoMessage() {}
 
 func (x *GetProjectConfigRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[42]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2694,7 +3055,7 @@ func (x *GetProjectConfigRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProjectConfigRequest.ProtoReflect.Descriptor instead.
 func (*GetProjectConfigRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{42}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *GetProjectConfigRequest) GetPath() string {
@@ -2713,7 +3074,7 @@ type ProjectConfigResponse struct {
 
 func (x *ProjectConfigResponse) Reset() {
 	*x = ProjectConfigResponse{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[43]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2725,7 +3086,7 @@ func (x *ProjectConfigResponse) String() string {
 func (*ProjectConfigResponse) ProtoMessage() {}
 
 func (x *ProjectConfigResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[43]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2738,7 +3099,7 @@ func (x *ProjectConfigResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectConfigResponse.ProtoReflect.Descriptor instead.
 func (*ProjectConfigResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{43}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *ProjectConfigResponse) GetConfig() *ProjectConfig {
@@ -2757,7 +3118,7 @@ type ListProjectsResponse struct {
 
 func (x *ListProjectsResponse) Reset() {
 	*x = ListProjectsResponse{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[44]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2769,7 +3130,7 @@ func (x *ListProjectsResponse) String() string {
 func (*ListProjectsResponse) ProtoMessage() {}
 
 func (x *ListProjectsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[44]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2782,7 +3143,7 @@ func (x *ListProjectsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProjectsResponse.ProtoReflect.Descriptor instead.
 func (*ListProjectsResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{44}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *ListProjectsResponse) GetProjects() []*ProjectInfo {
@@ -2805,7 +3166,7 @@ type ProjectInfo struct {
 
 func (x *ProjectInfo) Reset() {
 	*x = ProjectInfo{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[45]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2817,7 +3178,7 @@ func (x *ProjectInfo) String() string {
 func (*ProjectInfo) ProtoMessage() {}
 
 func (x *ProjectInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[45]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2830,7 +3191,7 @@ func (x *ProjectInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectInfo.ProtoReflect.Descriptor instead.
 func (*ProjectInfo) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{45}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *ProjectInfo) GetName() string {
@@ -2880,7 +3241,7 @@ type ProjectConfig struct {
 
 func (x *ProjectConfig) Reset() {
 	*x = ProjectConfig{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[46]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2892,7 +3253,7 @@ func (x *ProjectConfig) String() string {
 func (*ProjectConfig) ProtoMessage() {}
 
 func (x *ProjectConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[46]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2905,7 +3266,7 @@ func (x *ProjectConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectConfig.ProtoReflect.Descriptor instead.
 func (*ProjectConfig) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{46}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *ProjectConfig) GetName() string {
@@ -2946,7 +3307,7 @@ type CreateBackupRequest struct {
 
 func (x *CreateBackupRequest) Reset() {
 	*x = CreateBackupRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[47]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2958,7 +3319,7 @@ func (x *CreateBackupRequest) String() string {
 func (*CreateBackupRequest) ProtoMessage() {}
 
 func (x *CreateBackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[47]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2971,7 +3332,7 @@ func (x *CreateBackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBackupRequest.ProtoReflect.Descriptor instead.
 func (*CreateBackupRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{47}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *CreateBackupRequest) GetDescription() string {
@@ -2990,7 +3351,7 @@ type BackupResponse struct {
 
 func (x *BackupResponse) Reset() {
 	*x = BackupResponse{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[48]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3002,7 +3363,7 @@ func (x *BackupResponse) String() string {
 func (*BackupResponse) ProtoMessage() {}
 
 func (x *BackupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[48]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3015,7 +3376,7 @@ func (x *BackupResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackupResponse.ProtoReflect.Descriptor instead.
 func (*BackupResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{48}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *BackupResponse) GetBackup() *BackupInfo {
@@ -3034,7 +3395,7 @@ type ListBackupsResponse struct {
 
 func (x *ListBackupsResponse) Reset() {
 	*x = ListBackupsResponse{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[49]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3046,7 +3407,7 @@ func (x *ListBackupsResponse) String() string {
 func (*ListBackupsResponse) ProtoMessage() {}
 
 func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[49]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3059,7 +3420,7 @@ func (x *ListBackupsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBackupsResponse.ProtoReflect.Descriptor instead.
 func (*ListBackupsResponse) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{49}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{53}
 }
 
 func (x *ListBackupsResponse) GetBackups() []*BackupInfo {
@@ -3078,7 +3439,7 @@ type RestoreBackupRequest struct {
 
 func (x *RestoreBackupRequest) Reset() {
 	*x = RestoreBackupRequest{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[50]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3090,7 +3451,7 @@ func (x *RestoreBackupRequest) String() string {
 func (*RestoreBackupRequest) ProtoMessage() {}
 
 func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[50]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3103,7 +3464,7 @@ func (x *RestoreBackupRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RestoreBackupRequest.ProtoReflect.Descriptor instead.
 func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{50}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{54}
 }
 
 func (x *RestoreBackupRequest) GetBackupId() string {
@@ -3126,7 +3487,7 @@ type BackupInfo struct {
 
 func (x *BackupInfo) Reset() {
 	*x = BackupInfo{}
-	mi := &file_daemon_proto_daemon_proto_msgTypes[51]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3138,7 +3499,7 @@ func (x *BackupInfo) String() string {
 func (*BackupInfo) ProtoMessage() {}
 
 func (x *BackupInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_daemon_proto_daemon_proto_msgTypes[51]
+	mi := &file_daemon_proto_daemon_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3151,7 +3512,7 @@ func (x *BackupInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackupInfo.ProtoReflect.Descriptor instead.
 func (*BackupInfo) Descriptor() ([]byte, []int) {
-	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{51}
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{55}
 }
 
 func (x *BackupInfo) GetId() string {
@@ -3189,6 +3550,191 @@ func (x *BackupInfo) GetSizeBytes() int64 {
 	return 0
 }
 
+// Storage migration types
+type MigrateStorageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backend       string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"` // "file", "bolt" or "redis"
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`       // Directory for file, database file for bolt
+	RedisAddress  string                 `protobuf:"bytes,3,opt,name=redis_address,json=redisAddress,proto3" json:"redis_address,omitempty"`
+	RedisPrefix   string                 `protobuf:"bytes,4,opt,name=redis_prefix,json=redisPrefix,proto3" json:"redis_prefix,omitempty"`
+	DryRun        bool                   `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Restart       bool                   `protobuf:"varint,6,opt,name=restart,proto3" json:"restart,omitempty"`                                  // Ignore the checkpoint of an interrupted migration
+	SwitchStorage bool                   `protobuf:"varint,7,opt,name=switch_storage,json=switchStorage,proto3" json:"switch_storage,omitempty"` // Use the new storage once verified
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateStorageRequest) Reset() {
+	*x = MigrateStorageRequest{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateStorageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateStorageRequest) ProtoMessage() {}
+
+func (x *MigrateStorageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateStorageRequest.ProtoReflect.Descriptor instead.
+func (*MigrateStorageRequest) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *MigrateStorageRequest) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *MigrateStorageRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MigrateStorageRequest) GetRedisAddress() string {
+	if x != nil {
+		return x.RedisAddress
+	}
+	return ""
+}
+
+func (x *MigrateStorageRequest) GetRedisPrefix() string {
+	if x != nil {
+		return x.RedisPrefix
+	}
+	return ""
+}
+
+func (x *MigrateStorageRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *MigrateStorageRequest) GetRestart() bool {
+	if x != nil {
+		return x.Restart
+	}
+	return false
+}
+
+func (x *MigrateStorageRequest) GetSwitchStorage() bool {
+	if x != nil {
+		return x.SwitchStorage
+	}
+	return false
+}
+
+type MigrateStorageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Copied        []string               `protobuf:"bytes,2,rep,name=copied,proto3" json:"copied,omitempty"`
+	Unchanged     int32                  `protobuf:"varint,3,opt,name=unchanged,proto3" json:"unchanged,omitempty"`
+	Resumed       bool                   `protobuf:"varint,4,opt,name=resumed,proto3" json:"resumed,omitempty"`
+	Switched      bool                   `protobuf:"varint,5,opt,name=switched,proto3" json:"switched,omitempty"`
+	DryRun        bool                   `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MigrateStorageResponse) Reset() {
+	*x = MigrateStorageResponse{}
+	mi := &file_daemon_proto_daemon_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MigrateStorageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MigrateStorageResponse) ProtoMessage() {}
+
+func (x *MigrateStorageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_daemon_proto_daemon_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MigrateStorageResponse.ProtoReflect.Descriptor instead.
+func (*MigrateStorageResponse) Descriptor() ([]byte, []int) {
+	return file_daemon_proto_daemon_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *MigrateStorageResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *MigrateStorageResponse) GetCopied() []string {
+	if x != nil {
+		return x.Copied
+	}
+	return nil
+}
+
+func (x *MigrateStorageResponse) GetUnchanged() int32 {
+	if x != nil {
+		return x.Unchanged
+	}
+	return 0
+}
+
+func (x *MigrateStorageResponse) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+func (x *MigrateStorageResponse) GetSwitched() bool {
+	if x != nil {
+		return x.Switched
+	}
+	return false
+}
+
+func (x *MigrateStorageResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *MigrateStorageResponse) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
 var File_daemon_proto_daemon_proto protoreflect.FileDescriptor
 
 const file_daemon_proto_daemon_proto_rawDesc = "" +
@@ -3302,14 +3848,54 @@ const file_daemon_proto_daemon_proto_rawDesc = "" +
 	"\x11debounce_delay_ms\x18\x03 \x01(\x03R\x0fdebounceDelayMs\x12)\n" +
 	"\x10target_whitelist\x18\x04 \x03(\tR\x0ftargetWhitelist\x12)\n" +
 	"\x10target_blacklist\x18\x05 \x03(\tR\x0ftargetBlacklist\x12'\n" +
-	"\x0fignore_patterns\x18\x06 \x03(\tR\x0eignorePatterns\"\xc8\x01\n" +
+	"\x0fignore_patterns\x18\x06 \x03(\tR\x0eignorePatterns\"\xcd\x04\n" +
 	"\x0eAutoSyncStatus\x12\x18\n" +
 	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x18\n" +
 	"\arunning\x18\x02 \x01(\bR\arunning\x127\n" +
 	"\tlast_sync\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\blastSync\x12*\n" +
 	"\x11watch_interval_ms\x18\x04 \x01(\x03R\x0fwatchIntervalMs\x12\x1d\n" +
 	"\n" +
-	"last_error\x18\x05 \x01(\tR\tlastError\"\xd9\x01\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x127\n" +
+	"\tnext_sync\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bnextSync\x12#\n" +
+	"\rwatched_paths\x18\a \x03(\tR\fwatchedPaths\x12'\n" +
+	"\x0fevents_received\x18\b \x01(\x03R\x0eeventsReceived\x12%\n" +
+	"\x0eevents_ignored\x18\t \x01(\x03R\reventsIgnored\x12'\n" +
+	"\x0fsyncs_performed\x18\n" +
+	" \x01(\x03R\x0esyncsPerformed\x12L\n" +
+	"\fdestinations\x18\v \x03(\v2(.daemon.AutoSyncStatus.DestinationsEntryR\fdestinations\x1a^\n" +
+	"\x11DestinationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.daemon.DestinationSyncStatusR\x05value:\x028\x01\"\xe0\x02\n" +
+	"\x15DestinationSyncStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12=\n" +
+	"\flast_attempt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vlastAttempt\x12=\n" +
+	"\flast_success\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vlastSuccess\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12)\n" +
+	"\x10pending_debounce\x18\x05 \x01(\bR\x0fpendingDebounce\x12'\n" +
+	"\x0fservers_written\x18\x06 \x01(\x05R\x0eserversWritten\x12\x1d\n" +
+	"\n" +
+	"sync_count\x18\a \x01(\x05R\tsyncCount\x12#\n" +
+	"\rfailure_count\x18\b \x01(\x05R\ffailureCount\"\x8b\x03\n" +
+	"\fScheduledJob\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bschedule\x18\x02 \x01(\tR\bschedule\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\"\n" +
+	"\fdestinations\x18\x04 \x03(\tR\fdestinations\x12\x1a\n" +
+	"\bdisabled\x18\x05 \x01(\bR\bdisabled\x125\n" +
+	"\bnext_run\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\anextRun\x125\n" +
+	"\blast_run\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\alastRun\x12(\n" +
+	"\x10last_duration_ms\x18\b \x01(\x03R\x0elastDurationMs\x12\x1f\n" +
+	"\vlast_result\x18\t \x01(\tR\n" +
+	"lastResult\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x12\x1b\n" +
+	"\trun_count\x18\v \x01(\x05R\brunCount\"E\n" +
+	"\x19ListScheduledJobsResponse\x12(\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x14.daemon.ScheduledJobR\x04jobs\"0\n" +
+	"\x1aTriggerScheduledJobRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xd9\x01\n" +
 	"\x06Config\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x125\n" +
 	"\aservers\x18\x02 \x03(\v2\x1b.daemon.Config.ServersEntryR\aservers\x12,\n" +
@@ -3418,7 +4004,24 @@ const file_daemon_proto_daemon_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes*b\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\"\xe7\x01\n" +
+	"\x15MigrateStorageRequest\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12#\n" +
+	"\rredis_address\x18\x03 \x01(\tR\fredisAddress\x12!\n" +
+	"\fredis_prefix\x18\x04 \x01(\tR\vredisPrefix\x12\x17\n" +
+	"\adry_run\x18\x05 \x01(\bR\x06dryRun\x12\x18\n" +
+	"\arestart\x18\x06 \x01(\bR\arestart\x12%\n" +
+	"\x0eswitch_storage\x18\a \x01(\bR\rswitchStorage\"\xd4\x01\n" +
+	"\x16MigrateStorageResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x16\n" +
+	"\x06copied\x18\x02 \x03(\tR\x06copied\x12\x1c\n" +
+	"\tunchanged\x18\x03 \x01(\x05R\tunchanged\x12\x18\n" +
+	"\aresumed\x18\x04 \x01(\bR\aresumed\x12\x1a\n" +
+	"\bswitched\x18\x05 \x01(\bR\bswitched\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\x12\x1f\n" +
+	"\vduration_ms\x18\a \x01(\x03R\n" +
+	"durationMs*b\n" +
 	"\x0fDestinationType\x12\b\n" +
 	"\x04FILE\x10\x00\x12\n" +
 	"\n" +
@@ -3443,7 +4046,7 @@ const file_daemon_proto_daemon_proto_rawDesc = "" +
 	"\rCONFIG_CHANGE\x10\x00\x12\x11\n" +
 	"\rSYNC_COMPLETE\x10\x01\x12\t\n" +
 	"\x05ERROR\x10\x02\x12\x14\n" +
-	"\x10AUTO_SYNC_STATUS\x10\x032\x85\x10\n" +
+	"\x10AUTO_SYNC_STATUS\x10\x032\xf7\x11\n" +
 	"\x11AgentMasterDaemon\x12=\n" +
 	"\tAddServer\x12\x18.daemon.AddServerRequest\x1a\x16.daemon.ServerResponse\x12C\n" +
 	"\fUpdateServer\x12\x1b.daemon.UpdateServerRequest\x1a\x16.daemon.ServerResponse\x12C\n" +
@@ -3460,7 +4063,9 @@ const file_daemon_proto_daemon_proto_rawDesc = "" +
 	"\vPreviewSync\x12\x1a.daemon.PreviewSyncRequest\x1a\x13.daemon.SyncPreview\x12?\n" +
 	"\rStartAutoSync\x12\x16.daemon.AutoSyncConfig\x1a\x16.google.protobuf.Empty\x12>\n" +
 	"\fStopAutoSync\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x12C\n" +
-	"\x11GetAutoSyncStatus\x12\x16.google.protobuf.Empty\x1a\x16.daemon.AutoSyncStatus\x123\n" +
+	"\x11GetAutoSyncStatus\x12\x16.google.protobuf.Empty\x1a\x16.daemon.AutoSyncStatus\x12N\n" +
+	"\x11ListScheduledJobs\x12\x16.google.protobuf.Empty\x1a!.daemon.ListScheduledJobsResponse\x12O\n" +
+	"\x13TriggerScheduledJob\x12\".daemon.TriggerScheduledJobRequest\x1a\x14.daemon.ScheduledJob\x123\n" +
 	"\tGetConfig\x12\x16.google.protobuf.Empty\x1a\x0e.daemon.Config\x123\n" +
 	"\tSetConfig\x12\x0e.daemon.Config\x1a\x16.google.protobuf.Empty\x12?\n" +
 	"\n" +
@@ -3472,7 +4077,8 @@ const file_daemon_proto_daemon_proto_rawDesc = "" +
 	"\tSubscribe\x12\x18.daemon.SubscribeRequest\x1a\r.daemon.Event0\x01\x12C\n" +
 	"\fCreateBackup\x12\x1b.daemon.CreateBackupRequest\x1a\x16.daemon.BackupResponse\x12B\n" +
 	"\vListBackups\x12\x16.google.protobuf.Empty\x1a\x1b.daemon.ListBackupsResponse\x12E\n" +
-	"\rRestoreBackup\x12\x1c.daemon.RestoreBackupRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
+	"\rRestoreBackup\x12\x1c.daemon.RestoreBackupRequest\x1a\x16.google.protobuf.Empty\x12O\n" +
+	"\x0eMigrateStorage\x12\x1d.daemon.MigrateStorageRequest\x1a\x1e.daemon.MigrateStorageResponse\x12R\n" +
 	"\x0fScanForProjects\x12\x1e.daemon.ScanForProjectsRequest\x1a\x1f.daemon.ScanForProjectsResponse\x12I\n" +
 	"\x0fRegisterProject\x12\x1e.daemon.RegisterProjectRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
 	"\x10GetProjectConfig\x12\x1f.daemon.GetProjectConfigRequest\x1a\x1d.daemon.ProjectConfigResponse\x12D\n" +
@@ -3491,7 +4097,7 @@ func file_daemon_proto_daemon_proto_rawDescGZIP() []byte {
 }
 
 var file_daemon_proto_daemon_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_daemon_proto_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
+var file_daemon_proto_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 66)
 var file_daemon_proto_daemon_proto_goTypes = []any{
 	(DestinationType)(0),               // 0: daemon.DestinationType
 	(ChangeType)(0),                    // 1: daemon.ChangeType
@@ -3522,158 +4128,179 @@ var file_daemon_proto_daemon_proto_goTypes = []any{
 	(*ServerChange)(nil),               // 26: daemon.ServerChange
 	(*AutoSyncConfig)(nil),             // 27: daemon.AutoSyncConfig
 	(*AutoSyncStatus)(nil),             // 28: daemon.AutoSyncStatus
-	(*Config)(nil),                     // 29: daemon.Config
-	(*Settings)(nil),                   // 30: daemon.Settings
-	(*AutoSyncSettings)(nil),           // 31: daemon.AutoSyncSettings
-	(*BackupSettings)(nil),             // 32: daemon.BackupSettings
-	(*ValidationSettings)(nil),         // 33: daemon.ValidationSettings
-	(*LoadConfigRequest)(nil),          // 34: daemon.LoadConfigRequest
-	(*DaemonStatus)(nil),               // 35: daemon.DaemonStatus
-	(*SubscribeRequest)(nil),           // 36: daemon.SubscribeRequest
-	(*Event)(nil),                      // 37: daemon.Event
-	(*ConfigChangeEvent)(nil),          // 38: daemon.ConfigChangeEvent
-	(*SyncCompleteEvent)(nil),          // 39: daemon.SyncCompleteEvent
-	(*ErrorEvent)(nil),                 // 40: daemon.ErrorEvent
-	(*AutoSyncEvent)(nil),              // 41: daemon.AutoSyncEvent
-	(*ScanForProjectsRequest)(nil),     // 42: daemon.ScanForProjectsRequest
-	(*ScanForProjectsResponse)(nil),    // 43: daemon.ScanForProjectsResponse
-	(*RegisterProjectRequest)(nil),     // 44: daemon.RegisterProjectRequest
-	(*GetProjectConfigRequest)(nil),    // 45: daemon.GetProjectConfigRequest
-	(*ProjectConfigResponse)(nil),      // 46: daemon.ProjectConfigResponse
-	(*ListProjectsResponse)(nil),       // 47: daemon.ListProjectsResponse
-	(*ProjectInfo)(nil),                // 48: daemon.ProjectInfo
-	(*ProjectConfig)(nil),              // 49: daemon.ProjectConfig
-	(*CreateBackupRequest)(nil),        // 50: daemon.CreateBackupRequest
-	(*BackupResponse)(nil),             // 51: daemon.BackupResponse
-	(*ListBackupsResponse)(nil),        // 52: daemon.ListBackupsResponse
-	(*RestoreBackupRequest)(nil),       // 53: daemon.RestoreBackupRequest
-	(*BackupInfo)(nil),                 // 54: daemon.BackupInfo
-	nil,                                // 55: daemon.ServerConfig.EnvEntry
-	nil,                                // 56: daemon.ServerConfig.MetadataEntry
-	nil,                                // 57: daemon.RegisterDestinationRequest.OptionsEntry
-	nil,                                // 58: daemon.ListDestinationsResponse.DestinationsEntry
-	nil,                                // 59: daemon.MultiSyncResult.ResultsEntry
-	nil,                                // 60: daemon.Config.ServersEntry
-	nil,                                // 61: daemon.ProjectConfig.MetadataEntry
-	(*timestamppb.Timestamp)(nil),      // 62: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),              // 63: google.protobuf.Empty
+	(*DestinationSyncStatus)(nil),      // 29: daemon.DestinationSyncStatus
+	(*ScheduledJob)(nil),               // 30: daemon.ScheduledJob
+	(*ListScheduledJobsResponse)(nil),  // 31: daemon.ListScheduledJobsResponse
+	(*TriggerScheduledJobRequest)(nil), // 32: daemon.TriggerScheduledJobRequest
+	(*Config)(nil),                     // 33: daemon.Config
+	(*Settings)(nil),                   // 34: daemon.Settings
+	(*AutoSyncSettings)(nil),           // 35: daemon.AutoSyncSettings
+	(*BackupSettings)(nil),             // 36: daemon.BackupSettings
+	(*ValidationSettings)(nil),         // 37: daemon.ValidationSettings
+	(*LoadConfigRequest)(nil),          // 38: daemon.LoadConfigRequest
+	(*DaemonStatus)(nil),               // 39: daemon.DaemonStatus
+	(*SubscribeRequest)(nil),           // 40: daemon.SubscribeRequest
+	(*Event)(nil),                      // 41: daemon.Event
+	(*ConfigChangeEvent)(nil),          // 42: daemon.ConfigChangeEvent
+	(*SyncCompleteEvent)(nil),          // 43: daemon.SyncCompleteEvent
+	(*ErrorEvent)(nil),                 // 44: daemon.ErrorEvent
+	(*AutoSyncEvent)(nil),              // 45: daemon.AutoSyncEvent
+	(*ScanForProjectsRequest)(nil),     // 46: daemon.ScanForProjectsRequest
+	(*ScanForProjectsResponse)(nil),    // 47: daemon.ScanForProjectsResponse
+	(*RegisterProjectRequest)(nil),     // 48: daemon.RegisterProjectRequest
+	(*GetProjectConfigRequest)(nil),    // 49: daemon.GetProjectConfigRequest
+	(*ProjectConfigResponse)(nil),      // 50: daemon.ProjectConfigResponse
+	(*ListProjectsResponse)(nil),       // 51: daemon.ListProjectsResponse
+	(*ProjectInfo)(nil),                // 52: daemon.ProjectInfo
+	(*ProjectConfig)(nil),              // 53: daemon.ProjectConfig
+	(*CreateBackupRequest)(nil),        // 54: daemon.CreateBackupRequest
+	(*BackupResponse)(nil),             // 55: daemon.BackupResponse
+	(*ListBackupsResponse)(nil),        // 56: daemon.ListBackupsResponse
+	(*RestoreBackupRequest)(nil),       // 57: daemon.RestoreBackupRequest
+	(*BackupInfo)(nil),                 // 58: daemon.BackupInfo
+	(*MigrateStorageRequest)(nil),      // 59: daemon.MigrateStorageRequest
+	(*MigrateStorageResponse)(nil),     // 60: daemon.MigrateStorageResponse
+	nil,                                // 61: daemon.ServerConfig.EnvEntry
+	nil,                                // 62: daemon.ServerConfig.MetadataEntry
+	nil,                                // 63: daemon.RegisterDestinationRequest.OptionsEntry
+	nil,                                // 64: daemon.ListDestinationsResponse.DestinationsEntry
+	nil,                                // 65: daemon.MultiSyncResult.ResultsEntry
+	nil,                                // 66: daemon.AutoSyncStatus.DestinationsEntry
+	nil,                                // 67: daemon.Config.ServersEntry
+	nil,                                // 68: daemon.ProjectConfig.MetadataEntry
+	(*timestamppb.Timestamp)(nil),      // 69: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 70: google.protobuf.Empty
 }
 var file_daemon_proto_daemon_proto_depIdxs = []int32{
-	55, // 0: daemon.ServerConfig.env:type_name -> daemon.ServerConfig.EnvEntry
-	56, // 1: daemon.ServerConfig.metadata:type_name -> daemon.ServerConfig.MetadataEntry
+	61, // 0: daemon.ServerConfig.env:type_name -> daemon.ServerConfig.EnvEntry
+	62, // 1: daemon.ServerConfig.metadata:type_name -> daemon.ServerConfig.MetadataEntry
 	3,  // 2: daemon.ServerInfo.config:type_name -> daemon.ServerConfig
-	62, // 3: daemon.ServerInfo.created_at:type_name -> google.protobuf.Timestamp
-	62, // 4: daemon.ServerInfo.updated_at:type_name -> google.protobuf.Timestamp
+	69, // 3: daemon.ServerInfo.created_at:type_name -> google.protobuf.Timestamp
+	69, // 4: daemon.ServerInfo.updated_at:type_name -> google.protobuf.Timestamp
 	3,  // 5: daemon.AddServerRequest.config:type_name -> daemon.ServerConfig
 	3,  // 6: daemon.UpdateServerRequest.config:type_name -> daemon.ServerConfig
 	12, // 7: daemon.ListServersRequest.filter:type_name -> daemon.ServerFilter
 	4,  // 8: daemon.ServerResponse.server:type_name -> daemon.ServerInfo
 	4,  // 9: daemon.ListServersResponse.servers:type_name -> daemon.ServerInfo
 	0,  // 10: daemon.RegisterDestinationRequest.type:type_name -> daemon.DestinationType
-	57, // 11: daemon.RegisterDestinationRequest.options:type_name -> daemon.RegisterDestinationRequest.OptionsEntry
-	58, // 12: daemon.ListDestinationsResponse.destinations:type_name -> daemon.ListDestinationsResponse.DestinationsEntry
+	63, // 11: daemon.RegisterDestinationRequest.options:type_name -> daemon.RegisterDestinationRequest.OptionsEntry
+	64, // 12: daemon.ListDestinationsResponse.destinations:type_name -> daemon.ListDestinationsResponse.DestinationsEntry
 	0,  // 13: daemon.DestinationInfo.type:type_name -> daemon.DestinationType
 	22, // 14: daemon.SyncToRequest.options:type_name -> daemon.SyncOptions
 	22, // 15: daemon.SyncToMultipleRequest.options:type_name -> daemon.SyncOptions
-	62, // 16: daemon.SyncResult.timestamp:type_name -> google.protobuf.Timestamp
-	59, // 17: daemon.MultiSyncResult.results:type_name -> daemon.MultiSyncResult.ResultsEntry
+	69, // 16: daemon.SyncResult.timestamp:type_name -> google.protobuf.Timestamp
+	65, // 17: daemon.MultiSyncResult.results:type_name -> daemon.MultiSyncResult.ResultsEntry
 	26, // 18: daemon.SyncPreview.changes:type_name -> daemon.ServerChange
 	1,  // 19: daemon.ServerChange.type:type_name -> daemon.ChangeType
 	3,  // 20: daemon.ServerChange.before:type_name -> daemon.ServerConfig
 	3,  // 21: daemon.ServerChange.after:type_name -> daemon.ServerConfig
-	62, // 22: daemon.AutoSyncStatus.last_sync:type_name -> google.protobuf.Timestamp
-	60, // 23: daemon.Config.servers:type_name -> daemon.Config.ServersEntry
-	30, // 24: daemon.Config.settings:type_name -> daemon.Settings
-	31, // 25: daemon.Settings.auto_sync:type_name -> daemon.AutoSyncSettings
-	32, // 26: daemon.Settings.backup:type_name -> daemon.BackupSettings
-	33, // 27: daemon.Settings.validation:type_name -> daemon.ValidationSettings
-	62, // 28: daemon.DaemonStatus.start_time:type_name -> google.protobuf.Timestamp
-	2,  // 29: daemon.SubscribeRequest.types:type_name -> daemon.EventType
-	2,  // 30: daemon.Event.type:type_name -> daemon.EventType
-	62, // 31: daemon.Event.timestamp:type_name -> google.protobuf.Timestamp
-	38, // 32: daemon.Event.config_change:type_name -> daemon.ConfigChangeEvent
-	39, // 33: daemon.Event.sync_complete:type_name -> daemon.SyncCompleteEvent
-	40, // 34: daemon.Event.error:type_name -> daemon.ErrorEvent
-	41, // 35: daemon.Event.auto_sync:type_name -> daemon.AutoSyncEvent
-	48, // 36: daemon.ScanForProjectsResponse.projects:type_name -> daemon.ProjectInfo
-	49, // 37: daemon.RegisterProjectRequest.config:type_name -> daemon.ProjectConfig
-	49, // 38: daemon.ProjectConfigResponse.config:type_name -> daemon.ProjectConfig
-	48, // 39: daemon.ListProjectsResponse.projects:type_name -> daemon.ProjectInfo
-	49, // 40: daemon.ProjectInfo.config:type_name -> daemon.ProjectConfig
-	62, // 41: daemon.ProjectInfo.detected_at:type_name -> google.protobuf.Timestamp
-	61, // 42: daemon.ProjectConfig.metadata:type_name -> daemon.ProjectConfig.MetadataEntry
-	3,  // 43: daemon.ProjectConfig.servers:type_name -> daemon.ServerConfig
-	54, // 44: daemon.BackupResponse.backup:type_name -> daemon.BackupInfo
-	54, // 45: daemon.ListBackupsResponse.backups:type_name -> daemon.BackupInfo
-	62, // 46: daemon.BackupInfo.created_at:type_name -> google.protobuf.Timestamp
-	18, // 47: daemon.ListDestinationsResponse.DestinationsEntry.value:type_name -> daemon.DestinationInfo
-	23, // 48: daemon.MultiSyncResult.ResultsEntry.value:type_name -> daemon.SyncResult
-	3,  // 49: daemon.Config.ServersEntry.value:type_name -> daemon.ServerConfig
-	5,  // 50: daemon.AgentMasterDaemon.AddServer:input_type -> daemon.AddServerRequest
-	6,  // 51: daemon.AgentMasterDaemon.UpdateServer:input_type -> daemon.UpdateServerRequest
-	7,  // 52: daemon.AgentMasterDaemon.RemoveServer:input_type -> daemon.RemoveServerRequest
-	8,  // 53: daemon.AgentMasterDaemon.GetServer:input_type -> daemon.GetServerRequest
-	11, // 54: daemon.AgentMasterDaemon.ListServers:input_type -> daemon.ListServersRequest
-	9,  // 55: daemon.AgentMasterDaemon.EnableServer:input_type -> daemon.EnableServerRequest
-	10, // 56: daemon.AgentMasterDaemon.DisableServer:input_type -> daemon.DisableServerRequest
-	15, // 57: daemon.AgentMasterDaemon.RegisterDestination:input_type -> daemon.RegisterDestinationRequest
-	16, // 58: daemon.AgentMasterDaemon.RemoveDestination:input_type -> daemon.RemoveDestinationRequest
-	63, // 59: daemon.AgentMasterDaemon.ListDestinations:input_type -> google.protobuf.Empty
-	19, // 60: daemon.AgentMasterDaemon.SyncTo:input_type -> daemon.SyncToRequest
-	20, // 61: daemon.AgentMasterDaemon.SyncToMultiple:input_type -> daemon.SyncToMultipleRequest
-	21, // 62: daemon.AgentMasterDaemon.PreviewSync:input_type -> daemon.PreviewSyncRequest
-	27, // 63: daemon.AgentMasterDaemon.StartAutoSync:input_type -> daemon.AutoSyncConfig
-	63, // 64: daemon.AgentMasterDaemon.StopAutoSync:input_type -> google.protobuf.Empty
-	63, // 65: daemon.AgentMasterDaemon.GetAutoSyncStatus:input_type -> google.protobuf.Empty
-	63, // 66: daemon.AgentMasterDaemon.GetConfig:input_type -> google.protobuf.Empty
-	29, // 67: daemon.AgentMasterDaemon.SetConfig:input_type -> daemon.Config
-	34, // 68: daemon.AgentMasterDaemon.LoadConfig:input_type -> daemon.LoadConfigRequest
-	63, // 69: daemon.AgentMasterDaemon.SaveConfig:input_type -> google.protobuf.Empty
-	63, // 70: daemon.AgentMasterDaemon.GetStatus:input_type -> google.protobuf.Empty
-	63, // 71: daemon.AgentMasterDaemon.Shutdown:input_type -> google.protobuf.Empty
-	36, // 72: daemon.AgentMasterDaemon.Subscribe:input_type -> daemon.SubscribeRequest
-	50, // 73: daemon.AgentMasterDaemon.CreateBackup:input_type -> daemon.CreateBackupRequest
-	63, // 74: daemon.AgentMasterDaemon.ListBackups:input_type -> google.protobuf.Empty
-	53, // 75: daemon.AgentMasterDaemon.RestoreBackup:input_type -> daemon.RestoreBackupRequest
-	42, // 76: daemon.AgentMasterDaemon.ScanForProjects:input_type -> daemon.ScanForProjectsRequest
-	44, // 77: daemon.AgentMasterDaemon.RegisterProject:input_type -> daemon.RegisterProjectRequest
-	45, // 78: daemon.AgentMasterDaemon.GetProjectConfig:input_type -> daemon.GetProjectConfigRequest
-	63, // 79: daemon.AgentMasterDaemon.ListProjects:input_type -> google.protobuf.Empty
-	13, // 80: daemon.AgentMasterDaemon.AddServer:output_type -> daemon.ServerResponse
-	13, // 81: daemon.AgentMasterDaemon.UpdateServer:output_type -> daemon.ServerResponse
-	63, // 82: daemon.AgentMasterDaemon.RemoveServer:output_type -> google.protobuf.Empty
-	13, // 83: daemon.AgentMasterDaemon.GetServer:output_type -> daemon.ServerResponse
-	14, // 84: daemon.AgentMasterDaemon.ListServers:output_type -> daemon.ListServersResponse
-	13, // 85: daemon.AgentMasterDaemon.EnableServer:output_type -> daemon.ServerResponse
-	13, // 86: daemon.AgentMasterDaemon.DisableServer:output_type -> daemon.ServerResponse
-	63, // 87: daemon.AgentMasterDaemon.RegisterDestination:output_type -> google.protobuf.Empty
-	63, // 88: daemon.AgentMasterDaemon.RemoveDestination:output_type -> google.protobuf.Empty
-	17, // 89: daemon.AgentMasterDaemon.ListDestinations:output_type -> daemon.ListDestinationsResponse
-	23, // 90: daemon.AgentMasterDaemon.SyncTo:output_type -> daemon.SyncResult
-	24, // 91: daemon.AgentMasterDaemon.SyncToMultiple:output_type -> daemon.MultiSyncResult
-	25, // 92: daemon.AgentMasterDaemon.PreviewSync:output_type -> daemon.SyncPreview
-	63, // 93: daemon.AgentMasterDaemon.StartAutoSync:output_type -> google.protobuf.Empty
-	63, // 94: daemon.AgentMasterDaemon.StopAutoSync:output_type -> google.protobuf.Empty
-	28, // 95: daemon.AgentMasterDaemon.GetAutoSyncStatus:output_type -> daemon.AutoSyncStatus
-	29, // 96: daemon.AgentMasterDaemon.GetConfig:output_type -> daemon.Config
-	63, // 97: daemon.AgentMasterDaemon.SetConfig:output_type -> google.protobuf.Empty
-	63, // 98: daemon.AgentMasterDaemon.LoadConfig:output_type -> google.protobuf.Empty
-	63, // 99: daemon.AgentMasterDaemon.SaveConfig:output_type -> google.protobuf.Empty
-	35, // 100: daemon.AgentMasterDaemon.GetStatus:output_type -> daemon.DaemonStatus
-	63, // 101: daemon.AgentMasterDaemon.Shutdown:output_type -> google.protobuf.Empty
-	37, // 102: daemon.AgentMasterDaemon.Subscribe:output_type -> daemon.Event
-	51, // 103: daemon.AgentMasterDaemon.CreateBackup:output_type -> daemon.BackupResponse
-	52, // 104: daemon.AgentMasterDaemon.ListBackups:output_type -> daemon.ListBackupsResponse
-	63, // 105: daemon.AgentMasterDaemon.RestoreBackup:output_type -> google.protobuf.Empty
-	43, // 106: daemon.AgentMasterDaemon.ScanForProjects:output_type -> daemon.ScanForProjectsResponse
-	63, // 107: daemon.AgentMasterDaemon.RegisterProject:output_type -> google.protobuf.Empty
-	46, // 108: daemon.AgentMasterDaemon.GetProjectConfig:output_type -> daemon.ProjectConfigResponse
-	47, // 109: daemon.AgentMasterDaemon.ListProjects:output_type -> daemon.ListProjectsResponse
-	80, // [80:110] is the sub-list for method output_type
-	50, // [50:80] is the sub-list for method input_type
-	50, // [50:50] is the sub-list for extension type_name
-	50, // [50:50] is the sub-list for extension extendee
-	0,  // [0:50] is the sub-list for field type_name
+	69, // 22: daemon.AutoSyncStatus.last_sync:type_name -> google.protobuf.Timestamp
+	69, // 23: daemon.AutoSyncStatus.next_sync:type_name -> google.protobuf.Timestamp
+	66, // 24: daemon.AutoSyncStatus.destinations:type_name -> daemon.AutoSyncStatus.DestinationsEntry
+	69, // 25: daemon.DestinationSyncStatus.last_attempt:type_name -> google.protobuf.Timestamp
+	69, // 26: daemon.DestinationSyncStatus.last_success:type_name -> google.protobuf.Timestamp
+	69, // 27: daemon.ScheduledJob.next_run:type_name -> google.protobuf.Timestamp
+	69, // 28: daemon.ScheduledJob.last_run:type_name -> google.protobuf.Timestamp
+	30, // 29: daemon.ListScheduledJobsResponse.jobs:type_name -> daemon.ScheduledJob
+	67, // 30: daemon.Config.servers:type_name -> daemon.Config.ServersEntry
+	34, // 31: daemon.Config.settings:type_name -> daemon.Settings
+	35, // 32: daemon.Settings.auto_sync:type_name -> daemon.AutoSyncSettings
+	36, // 33: daemon.Settings.backup:type_name -> daemon.BackupSettings
+	37, // 34: daemon.Settings.validation:type_name -> daemon.ValidationSettings
+	69, // 35: daemon.DaemonStatus.start_time:type_name -> google.protobuf.Timestamp
+	2,  // 36: daemon.SubscribeRequest.types:type_name -> daemon.EventType
+	2,  // 37: daemon.Event.type:type_name -> daemon.EventType
+	69, // 38: daemon.Event.timestamp:type_name -> google.protobuf.Timestamp
+	42, // 39: daemon.Event.config_change:type_name -> daemon.ConfigChangeEvent
+	43, // 40: daemon.Event.sync_complete:type_name -> daemon.SyncCompleteEvent
+	44, // 41: daemon.Event.error:type_name -> daemon.ErrorEvent
+	45, // 42: daemon.Event.auto_sync:type_name -> daemon.AutoSyncEvent
+	52, // 43: daemon.ScanForProjectsResponse.projects:type_name -> daemon.ProjectInfo
+	53, // 44: daemon.RegisterProjectRequest.config:type_name -> daemon.ProjectConfig
+	53, // 45: daemon.ProjectConfigResponse.config:type_name -> daemon.ProjectConfig
+	52, // 46: daemon.ListProjectsResponse.projects:type_name -> daemon.ProjectInfo
+	53, // 47: daemon.ProjectInfo.config:type_name -> daemon.ProjectConfig
+	69, // 48: daemon.ProjectInfo.detected_at:type_name -> google.protobuf.Timestamp
+	68, // 49: daemon.ProjectConfig.metadata:type_name -> daemon.ProjectConfig.MetadataEntry
+	3,  // 50: daemon.ProjectConfig.servers:type_name -> daemon.ServerConfig
+	58, // 51: daemon.BackupResponse.backup:type_name -> daemon.BackupInfo
+	58, // 52: daemon.ListBackupsResponse.backups:type_name -> daemon.BackupInfo
+	69, // 53: daemon.BackupInfo.created_at:type_name -> google.protobuf.Timestamp
+	18, // 54: daemon.ListDestinationsResponse.DestinationsEntry.value:type_name -> daemon.DestinationInfo
+	23, // 55: daemon.MultiSyncResult.ResultsEntry.value:type_name -> daemon.SyncResult
+	29, // 56: daemon.AutoSyncStatus.DestinationsEntry.value:type_name -> daemon.DestinationSyncStatus
+	3,  // 57: daemon.Config.ServersEntry.value:type_name -> daemon.ServerConfig
+	5,  // 58: daemon.AgentMasterDaemon.AddServer:input_type -> daemon.AddServerRequest
+	6,  // 59: daemon.AgentMasterDaemon.UpdateServer:input_type -> daemon.UpdateServerRequest
+	7,  // 60: daemon.AgentMasterDaemon.RemoveServer:input_type -> daemon.RemoveServerRequest
+	8,  // 61: daemon.AgentMasterDaemon.GetServer:input_type -> daemon.GetServerRequest
+	11, // 62: daemon.AgentMasterDaemon.ListServers:input_type -> daemon.ListServersRequest
+	9,  // 63: daemon.AgentMasterDaemon.EnableServer:input_type -> daemon.EnableServerRequest
+	10, // 64: daemon.AgentMasterDaemon.DisableServer:input_type -> daemon.DisableServerRequest
+	15, // 65: daemon.AgentMasterDaemon.RegisterDestination:input_type -> daemon.RegisterDestinationRequest
+	16, // 66: daemon.AgentMasterDaemon.RemoveDestination:input_type -> daemon.RemoveDestinationRequest
+	70, // 67: daemon.AgentMasterDaemon.ListDestinations:input_type -> google.protobuf.Empty
+	19, // 68: daemon.AgentMasterDaemon.SyncTo:input_type -> daemon.SyncToRequest
+	20, // 69: daemon.AgentMasterDaemon.SyncToMultiple:input_type -> daemon.SyncToMultipleRequest
+	21, // 70: daemon.AgentMasterDaemon.PreviewSync:input_type -> daemon.PreviewSyncRequest
+	27, // 71: daemon.AgentMasterDaemon.StartAutoSync:input_type -> daemon.AutoSyncConfig
+	70, // 72: daemon.AgentMasterDaemon.StopAutoSync:input_type -> google.protobuf.Empty
+	70, // 73: daemon.AgentMasterDaemon.GetAutoSyncStatus:input_type -> google.protobuf.Empty
+	70, // 74: daemon.AgentMasterDaemon.ListScheduledJobs:input_type -> google.protobuf.Empty
+	32, // 75: daemon.AgentMasterDaemon.TriggerScheduledJob:input_type -> daemon.TriggerScheduledJobRequest
+	70, // 76: daemon.AgentMasterDaemon.GetConfig:input_type -> google.protobuf.Empty
+	33, // 77: daemon.AgentMasterDaemon.SetConfig:input_type -> daemon.Config
+	38, // 78: daemon.AgentMasterDaemon.LoadConfig:input_type -> daemon.LoadConfigRequest
+	70, // 79: daemon.AgentMasterDaemon.SaveConfig:input_type -> google.protobuf.Empty
+	70, // 80: daemon.AgentMasterDaemon.GetStatus:input_type -> google.protobuf.Empty
+	70, // 81: daemon.AgentMasterDaemon.Shutdown:input_type -> google.protobuf.Empty
+	40, // 82: daemon.AgentMasterDaemon.Subscribe:input_type -> daemon.SubscribeRequest
+	54, // 83: daemon.AgentMasterDaemon.CreateBackup:input_type -> daemon.CreateBackupRequest
+	70, // 84: daemon.AgentMasterDaemon.ListBackups:input_type -> google.protobuf.Empty
+	57, // 85: daemon.AgentMasterDaemon.RestoreBackup:input_type -> daemon.RestoreBackupRequest
+	59, // 86: daemon.AgentMasterDaemon.MigrateStorage:input_type -> daemon.MigrateStorageRequest
+	46, // 87: daemon.AgentMasterDaemon.ScanForProjects:input_type -> daemon.ScanForProjectsRequest
+	48, // 88: daemon.AgentMasterDaemon.RegisterProject:input_type -> daemon.RegisterProjectRequest
+	49, // 89: daemon.AgentMasterDaemon.GetProjectConfig:input_type -> daemon.GetProjectConfigRequest
+	70, // 90: daemon.AgentMasterDaemon.ListProjects:input_type -> google.protobuf.Empty
+	13, // 91: daemon.AgentMasterDaemon.AddServer:output_type -> daemon.ServerResponse
+	13, // 92: daemon.AgentMasterDaemon.UpdateServer:output_type -> daemon.ServerResponse
+	70, // 93: daemon.AgentMasterDaemon.RemoveServer:output_type -> google.protobuf.Empty
+	13, // 94: daemon.AgentMasterDaemon.GetServer:output_type -> daemon.ServerResponse
+	14, // 95: daemon.AgentMasterDaemon.ListServers:output_type -> daemon.ListServersResponse
+	13, // 96: daemon.AgentMasterDaemon.EnableServer:output_type -> daemon.ServerResponse
+	13, // 97: daemon.AgentMasterDaemon.DisableServer:output_type -> daemon.ServerResponse
+	70, // 98: daemon.AgentMasterDaemon.RegisterDestination:output_type -> google.protobuf.Empty
+	70, // 99: daemon.AgentMasterDaemon.RemoveDestination:output_type -> google.protobuf.Empty
+	17, // 100: daemon.AgentMasterDaemon.ListDestinations:output_type -> daemon.ListDestinationsResponse
+	23, // 101: daemon.AgentMasterDaemon.SyncTo:output_type -> daemon.SyncResult
+	24, // 102: daemon.AgentMasterDaemon.SyncToMultiple:output_type -> daemon.MultiSyncResult
+	25, // 103: daemon.AgentMasterDaemon.PreviewSync:output_type -> daemon.SyncPreview
+	70, // 104: daemon.AgentMasterDaemon.StartAutoSync:output_type -> google.protobuf.Empty
+	70, // 105: daemon.AgentMasterDaemon.StopAutoSync:output_type -> google.protobuf.Empty
+	28, // 106: daemon.AgentMasterDaemon.GetAutoSyncStatus:output_type -> daemon.AutoSyncStatus
+	31, // 107: daemon.AgentMasterDaemon.ListScheduledJobs:output_type -> daemon.ListScheduledJobsResponse
+	30, // 108: daemon.AgentMasterDaemon.TriggerScheduledJob:output_type -> daemon.ScheduledJob
+	33, // 109: daemon.AgentMasterDaemon.GetConfig:output_type -> daemon.Config
+	70, // 110: daemon.AgentMasterDaemon.SetConfig:output_type -> google.protobuf.Empty
+	70, // 111: daemon.AgentMasterDaemon.LoadConfig:output_type -> google.protobuf.Empty
+	70, // 112: daemon.AgentMasterDaemon.SaveConfig:output_type -> google.protobuf.Empty
+	39, // 113: daemon.AgentMasterDaemon.GetStatus:output_type -> daemon.DaemonStatus
+	70, // 114: daemon.AgentMasterDaemon.Shutdown:output_type -> google.protobuf.Empty
+	41, // 115: daemon.AgentMasterDaemon.Subscribe:output_type -> daemon.Event
+	55, // 116: daemon.AgentMasterDaemon.CreateBackup:output_type -> daemon.BackupResponse
+	56, // 117: daemon.AgentMasterDaemon.ListBackups:output_type -> daemon.ListBackupsResponse
+	70, // 118: daemon.AgentMasterDaemon.RestoreBackup:output_type -> google.protobuf.Empty
+	60, // 119: daemon.AgentMasterDaemon.MigrateStorage:output_type -> daemon.MigrateStorageResponse
+	47, // 120: daemon.AgentMasterDaemon.ScanForProjects:output_type -> daemon.ScanForProjectsResponse
+	70, // 121: daemon.AgentMasterDaemon.RegisterProject:output_type -> google.protobuf.Empty
+	50, // 122: daemon.AgentMasterDaemon.GetProjectConfig:output_type -> daemon.ProjectConfigResponse
+	51, // 123: daemon.AgentMasterDaemon.ListProjects:output_type -> daemon.ListProjectsResponse
+	91, // [91:124] is the sub-list for method output_type
+	58, // [58:91] is the sub-list for method input_type
+	58, // [58:58] is the sub-list for extension type_name
+	58, // [58:58] is the sub-list for extension extendee
+	0,  // [0:58] is the sub-list for field type_name
 }
 
 func init() { file_daemon_proto_daemon_proto_init() }
@@ -3681,7 +4308,7 @@ func file_daemon_proto_daemon_proto_init() {
 	if File_daemon_proto_daemon_proto != nil {
 		return
 	}
-	file_daemon_proto_daemon_proto_msgTypes[34].OneofWrappers = []any{
+	file_daemon_proto_daemon_proto_msgTypes[38].OneofWrappers = []any{
 		(*Event_ConfigChange)(nil),
 		(*Event_SyncComplete)(nil),
 		(*Event_Error)(nil),
@@ -3693,7 +4320,7 @@ func file_daemon_proto_daemon_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_daemon_proto_daemon_proto_rawDesc), len(file_daemon_proto_daemon_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   59,
+			NumMessages:   66,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
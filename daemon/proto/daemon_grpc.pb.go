@@ -36,6 +36,8 @@ const (
 	AgentMasterDaemon_StartAutoSync_FullMethodName       = "/daemon.AgentMasterDaemon/StartAutoSync"
 	AgentMasterDaemon_StopAutoSync_FullMethodName        = "/daemon.AgentMasterDaemon/StopAutoSync"
 	AgentMasterDaemon_GetAutoSyncStatus_FullMethodName   = "/daemon.AgentMasterDaemon/GetAutoSyncStatus"
+	AgentMasterDaemon_ListScheduledJobs_FullMethodName   = "/daemon.AgentMasterDaemon/ListScheduledJobs"
+	AgentMasterDaemon_TriggerScheduledJob_FullMethodName = "/daemon.AgentMasterDaemon/TriggerScheduledJob"
 	AgentMasterDaemon_GetConfig_FullMethodName           = "/daemon.AgentMasterDaemon/GetConfig"
 	AgentMasterDaemon_SetConfig_FullMethodName           = "/daemon.AgentMasterDaemon/SetConfig"
 	AgentMasterDaemon_LoadConfig_FullMethodName          = "/daemon.AgentMasterDaemon/LoadConfig"
@@ -46,6 +48,7 @@ const (
 	AgentMasterDaemon_CreateBackup_FullMethodName        = "/daemon.AgentMasterDaemon/CreateBackup"
 	AgentMasterDaemon_ListBackups_FullMethodName         = "/daemon.AgentMasterDaemon/ListBackups"
 	AgentMasterDaemon_RestoreBackup_FullMethodName       = "/daemon.AgentMasterDaemon/RestoreBackup"
+	AgentMasterDaemon_MigrateStorage_FullMethodName      = "/daemon.AgentMasterDaemon/MigrateStorage"
 	AgentMasterDaemon_ScanForProjects_FullMethodName     = "/daemon.AgentMasterDaemon/ScanForProjects"
 	AgentMasterDaemon_RegisterProject_FullMethodName     = "/daemon.AgentMasterDaemon/RegisterProject"
 	AgentMasterDaemon_GetProjectConfig_FullMethodName    = "/daemon.AgentMasterDaemon/GetProjectConfig"
@@ -78,6 +81,9 @@ type AgentMasterDaemonClient interface {
 	StartAutoSync(ctx context.Context, in *AutoSyncConfig, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	StopAutoSync(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	GetAutoSyncStatus(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AutoSyncStatus, error)
+	// Scheduled jobs
+	ListScheduledJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListScheduledJobsResponse, error)
+	TriggerScheduledJob(ctx context.Context, in *TriggerScheduledJobRequest, opts ...grpc.CallOption) (*ScheduledJob, error)
 	// Configuration
 	GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Config, error)
 	SetConfig(ctx context.Context, in *Config, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -92,6 +98,8 @@ type AgentMasterDaemonClient interface {
 	CreateBackup(ctx context.Context, in *CreateBackupRequest, opts ...grpc.CallOption) (*BackupResponse, error)
 	ListBackups(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListBackupsResponse, error)
 	RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Storage migration
+	MigrateStorage(ctx context.Context, in *MigrateStorageRequest, opts ...grpc.CallOption) (*MigrateStorageResponse, error)
 	// Project management
 	ScanForProjects(ctx context.Context, in *ScanForProjectsRequest, opts ...grpc.CallOption) (*ScanForProjectsResponse, error)
 	RegisterProject(ctx context.Context, in *RegisterProjectRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
@@ -267,6 +275,26 @@ func (c *agentMasterDaemonClient) GetAutoSyncStatus(ctx context.Context, in *emp
 	return out, nil
 }
 
+func (c *agentMasterDaemonClient) ListScheduledJobs(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListScheduledJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledJobsResponse)
+	err := c.cc.Invoke(ctx, AgentMasterDaemon_ListScheduledJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentMasterDaemonClient) TriggerScheduledJob(ctx context.Context, in *TriggerScheduledJobRequest, opts ...grpc.CallOption) (*ScheduledJob, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduledJob)
+	err := c.cc.Invoke(ctx, AgentMasterDaemon_TriggerScheduledJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentMasterDaemonClient) GetConfig(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*Config, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Config)
@@ -376,6 +404,16 @@ func (c *agentMasterDaemonClient) RestoreBackup(ctx context.Context, in *Restore
 	return out, nil
 }
 
+func (c *agentMasterDaemonClient) MigrateStorage(ctx context.Context, in *MigrateStorageRequest, opts ...grpc.CallOption) (*MigrateStorageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MigrateStorageResponse)
+	err := c.cc.Invoke(ctx, AgentMasterDaemon_MigrateStorage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentMasterDaemonClient) ScanForProjects(ctx context.Context, in *ScanForProjectsRequest, opts ...grpc.CallOption) (*ScanForProjectsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ScanForProjectsResponse)
@@ -442,6 +480,9 @@ type AgentMasterDaemonServer interface {
 	StartAutoSync(context.Context, *AutoSyncConfig) (*emptypb.Empty, error)
 	StopAutoSync(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	GetAutoSyncStatus(context.Context, *emptypb.Empty) (*AutoSyncStatus, error)
+	// Scheduled jobs
+	ListScheduledJobs(context.Context, *emptypb.Empty) (*ListScheduledJobsResponse, error)
+	TriggerScheduledJob(context.Context, *TriggerScheduledJobRequest) (*ScheduledJob, error)
 	// Configuration
 	GetConfig(context.Context, *emptypb.Empty) (*Config, error)
 	SetConfig(context.Context, *Config) (*emptypb.Empty, error)
@@ -456,6 +497,8 @@ type AgentMasterDaemonServer interface {
 	CreateBackup(context.Context, *CreateBackupRequest) (*BackupResponse, error)
 	ListBackups(context.Context, *emptypb.Empty) (*ListBackupsResponse, error)
 	RestoreBackup(context.Context, *RestoreBackupRequest) (*emptypb.Empty, error)
+	// Storage migration
+	MigrateStorage(context.Context, *MigrateStorageRequest) (*MigrateStorageResponse, error)
 	// Project management
 	ScanForProjects(context.Context, *ScanForProjectsRequest) (*ScanForProjectsResponse, error)
 	RegisterProject(context.Context, *RegisterProjectRequest) (*emptypb.Empty, error)
@@ -519,6 +562,12 @@ func (UnimplementedAgentMasterDaemonServer) StopAutoSync(context.Context, *empty
 func (UnimplementedAgentMasterDaemonServer) GetAutoSyncStatus(context.Context, *emptypb.Empty) (*AutoSyncStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAutoSyncStatus not implemented")
 }
+func (UnimplementedAgentMasterDaemonServer) ListScheduledJobs(context.Context, *emptypb.Empty) (*ListScheduledJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledJobs not implemented")
+}
+func (UnimplementedAgentMasterDaemonServer) TriggerScheduledJob(context.Context, *TriggerScheduledJobRequest) (*ScheduledJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerScheduledJob not implemented")
+}
 func (UnimplementedAgentMasterDaemonServer) GetConfig(context.Context, *emptypb.Empty) (*Config, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
 }
@@ -549,6 +598,9 @@ func (UnimplementedAgentMasterDaemonServer) ListBackups(context.Context, *emptyp
 func (UnimplementedAgentMasterDaemonServer) RestoreBackup(context.Context, *RestoreBackupRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestoreBackup not implemented")
 }
+func (UnimplementedAgentMasterDaemonServer) MigrateStorage(context.Context, *MigrateStorageRequest) (*MigrateStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MigrateStorage not implemented")
+}
 func (UnimplementedAgentMasterDaemonServer) ScanForProjects(context.Context, *ScanForProjectsRequest) (*ScanForProjectsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ScanForProjects not implemented")
 }
@@ -870,6 +922,42 @@ func _AgentMasterDaemon_GetAutoSyncStatus_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentMasterDaemon_ListScheduledJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentMasterDaemonServer).ListScheduledJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentMasterDaemon_ListScheduledJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentMasterDaemonServer).ListScheduledJobs(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentMasterDaemon_TriggerScheduledJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerScheduledJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentMasterDaemonServer).TriggerScheduledJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentMasterDaemon_TriggerScheduledJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentMasterDaemonServer).TriggerScheduledJob(ctx, req.(*TriggerScheduledJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentMasterDaemon_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
@@ -1043,6 +1131,24 @@ func _AgentMasterDaemon_RestoreBackup_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentMasterDaemon_MigrateStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrateStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentMasterDaemonServer).MigrateStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentMasterDaemon_MigrateStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentMasterDaemonServer).MigrateStorage(ctx, req.(*MigrateStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentMasterDaemon_ScanForProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ScanForProjectsRequest)
 	if err := dec(in); err != nil {
@@ -1186,6 +1292,14 @@ var AgentMasterDaemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAutoSyncStatus",
 			Handler:    _AgentMasterDaemon_GetAutoSyncStatus_Handler,
 		},
+		{
+			MethodName: "ListScheduledJobs",
+			Handler:    _AgentMasterDaemon_ListScheduledJobs_Handler,
+		},
+		{
+			MethodName: "TriggerScheduledJob",
+			Handler:    _AgentMasterDaemon_TriggerScheduledJob_Handler,
+		},
 		{
 			MethodName: "GetConfig",
 			Handler:    _AgentMasterDaemon_GetConfig_Handler,
@@ -1222,6 +1336,10 @@ var AgentMasterDaemon_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RestoreBackup",
 			Handler:    _AgentMasterDaemon_RestoreBackup_Handler,
 		},
+		{
+			MethodName: "MigrateStorage",
+			Handler:    _AgentMasterDaemon_MigrateStorage_Handler,
+		},
 		{
 			MethodName: "ScanForProjects",
 			Handler:    _AgentMasterDaemon_ScanForProjects_Handler,
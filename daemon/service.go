@@ -2,7 +2,9 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync/atomic"
 	"time"
 
@@ -86,6 +88,29 @@ func (s *Service) ListServers(ctx context.Context, req *pb.ListServersRequest) (
 	return s.ListServersCorrected(ctx, req)
 }
 
+// Destination management
+
+func (s *Service) ListDestinations(ctx context.Context, req *emptypb.Empty) (*pb.ListDestinationsResponse, error) {
+	resp := &pb.ListDestinationsResponse{
+		Destinations: make(map[string]*pb.DestinationInfo),
+	}
+	
+	for name, dest := range s.daemon.engine.ListDestinations() {
+		resp.Destinations[name] = destinationInfoToProto(name, dest.GetDescription(), true)
+	}
+	
+	// Presets whose clients aren't installed are listed as unavailable
+	s.daemon.mu.RLock()
+	for name, preset := range s.daemon.unavailable {
+		if _, registered := resp.Destinations[name]; !registered {
+			resp.Destinations[name] = destinationInfoToProto(name, preset.Description, false)
+		}
+	}
+	s.daemon.mu.RUnlock()
+	
+	return resp, nil
+}
+
 // Sync operations
 
 func (s *Service) SyncTo(ctx context.Context, req *pb.SyncToRequest) (*pb.SyncResult, error) {
@@ -185,6 +210,39 @@ func (s *Service) GetAutoSyncStatus(ctx context.Context, req *emptypb.Empty) (*p
 	return autoSyncStatusFromEngine(autoSyncStatus), nil
 }
 
+// Scheduled jobs
+
+func (s *Service) ListScheduledJobs(ctx context.Context, req *emptypb.Empty) (*pb.ListScheduledJobsResponse, error) {
+	jobs, err := s.daemon.engine.ListScheduledJobs()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled jobs: %v", err)
+	}
+	
+	pbJobs := make([]*pb.ScheduledJob, len(jobs))
+	for i, job := range jobs {
+		pbJobs[i] = scheduledJobStatusToProto(job)
+	}
+	
+	return &pb.ListScheduledJobsResponse{Jobs: pbJobs}, nil
+}
+
+func (s *Service) TriggerScheduledJob(ctx context.Context, req *pb.TriggerScheduledJobRequest) (*pb.ScheduledJob, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "job name is required")
+	}
+	
+	job, err := s.daemon.engine.RunScheduledJob(ctx, req.Name)
+	if err != nil {
+		var notFound *engine.JobNotFoundError
+		if errors.As(err, &notFound) {
+			return nil, status.Errorf(codes.NotFound, "scheduled job not found: %v", err)
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to run scheduled job: %v", err)
+	}
+	
+	return scheduledJobStatusToProto(job), nil
+}
+
 // Daemon lifecycle
 
 func (s *Service) GetStatus(ctx context.Context, req *emptypb.Empty) (*pb.DaemonStatus, error) {
@@ -343,6 +401,46 @@ func (s *Service) RestoreBackup(ctx context.Context, req *pb.RestoreBackupReques
 	return &emptypb.Empty{}, nil
 }
 
+// Storage migration
+
+func (s *Service) MigrateStorage(ctx context.Context, req *pb.MigrateStorageRequest) (*pb.MigrateStorageResponse, error) {
+	dst, err := openStorage(req.Backend, expandPath(req.Path), req.RedisAddress, req.RedisPrefix)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to open destination storage: %v", err)
+	}
+	
+	result, err := s.daemon.engine.MigrateStorage(ctx, dst, engine.MigrationOptions{
+		DryRun:  req.DryRun,
+		Restart: req.Restart,
+		Switch:  req.SwitchStorage,
+		Progress: func(progress engine.MigrationProgress) {
+			s.daemon.updateActivity()
+		},
+	})
+	if result == nil || !result.Switched {
+		if closer, ok := dst.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	if err != nil {
+		var verifyErr *engine.VerificationError
+		if errors.As(err, &verifyErr) {
+			return nil, status.Errorf(codes.Aborted, "migration not verified, run it again: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to migrate storage: %v", err)
+	}
+	
+	if result.Switched {
+		s.daemon.logger.Info("Switched storage; set storage_backend in the daemon config to keep it after a restart",
+			"backend", req.Backend,
+			"path", req.Path,
+			"redis_address", req.RedisAddress,
+		)
+	}
+	
+	return migrationResultToProto(result), nil
+}
+
 // Helper to format addresses
 func formatAddresses(listener string, port int) []string {
 	var addrs []string
@@ -0,0 +1,516 @@
+package engine
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptionCipher names the cipher in sealed values
+const encryptionCipher = "aes-256-gcm"
+
+// scrypt parameters for passphrase keys
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptSaltLen = 16
+)
+
+// EncryptionKey is an AES-256 key for EncryptedStorage, either random or
+// derived from a passphrase
+type EncryptionKey struct {
+	key  []byte
+	id   string
+	salt []byte // Set for passphrase keys
+
+	passphrase string
+	mu         sync.Mutex
+	derived    map[string]*EncryptionKey // Keys for other salts, by salt
+}
+
+// NewEncryptionKey creates a key from 32 raw bytes
+func NewEncryptionKey(raw []byte) (*EncryptionKey, error) {
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(raw))
+	}
+	key := append([]byte(nil), raw...)
+	sum := sha256.Sum256(key)
+	return &EncryptionKey{key: key, id: hex.EncodeToString(sum[:4])}, nil
+}
+
+// GenerateEncryptionKey creates a random key
+func GenerateEncryptionKey() (*EncryptionKey, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return NewEncryptionKey(raw)
+}
+
+// PassphraseKey derives a key from a passphrase with scrypt. Values record
+// their salt, so they can be decrypted with the passphrase alone.
+func PassphraseKey(passphrase string) (*EncryptionKey, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is required")
+	}
+	salt := make([]byte, scryptSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	key.derived = map[string]*EncryptionKey{string(salt): key}
+	return key, nil
+}
+
+func deriveKey(passphrase string, salt []byte) (*EncryptionKey, error) {
+	raw, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	key, err := NewEncryptionKey(raw)
+	if err != nil {
+		return nil, err
+	}
+	key.salt = salt
+	key.passphrase = passphrase
+	return key, nil
+}
+
+// LoadKeyFile reads a base64 key written by SaveKeyFile
+func LoadKeyFile(path string) (*EncryptionKey, error) {
+	data, err := os.ReadFile(expandPath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	return NewEncryptionKey(raw)
+}
+
+// SaveKeyFile writes a random key to path, readable only by its owner
+func SaveKeyFile(path string, key *EncryptionKey) error {
+	if key.passphrase != "" {
+		return fmt.Errorf("passphrase keys can't be saved to a key file")
+	}
+	data := base64.StdEncoding.EncodeToString(key.key) + "\n"
+	return WriteFileAtomic(expandPath(path), []byte(data), SecretFileMode)
+}
+
+// ID identifies the key in sealed values without revealing it
+func (k *EncryptionKey) ID() string {
+	return k.id
+}
+
+// sealedValue is how EncryptedStorage stores a value. It is JSON, so sealed
+// values are still valid in file storage and ExportStorage.
+type sealedValue struct {
+	Encrypted string `json:"encrypted"`
+	KeyID     string `json:"keyId"`
+	Salt      string `json:"salt,omitempty"`
+	Data      string `json:"data"` // Nonce followed by ciphertext
+}
+
+// seal encrypts data with the key, authenticating the storage key with it so
+// values can't be swapped between keys
+func (k *EncryptionKey) seal(storageKey string, data []byte) ([]byte, error) {
+	aead, err := k.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := sealedValue{
+		Encrypted: encryptionCipher,
+		KeyID:     k.id,
+		Data:      base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, data, []byte(storageKey))),
+	}
+	if k.salt != nil {
+		sealed.Salt = base64.StdEncoding.EncodeToString(k.salt)
+	}
+	return json.Marshal(sealed)
+}
+
+// open decrypts a sealed value, reporting false if it was sealed with
+// another key
+func (k *EncryptionKey) open(storageKey string, sealed *sealedValue) ([]byte, bool, error) {
+	key := k
+	if sealed.Salt != "" && k.passphrase != "" {
+		var err error
+		if key, err = k.forSalt(sealed.Salt); err != nil {
+			return nil, false, err
+		}
+	}
+	if sealed.KeyID != key.id {
+		return nil, false, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sealed.Data)
+	if err != nil {
+		return nil, true, err
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return nil, true, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, true, fmt.Errorf("sealed value is truncated")
+	}
+	data, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(storageKey))
+	return data, true, err
+}
+
+// forSalt returns the passphrase key derived with salt
+func (k *EncryptionKey) forSalt(encodedSalt string) (*EncryptionKey, error) {
+	salt, err := base64.StdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if key, ok := k.derived[string(salt)]; ok {
+		return key, nil
+	}
+	key, err := deriveKey(k.passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	k.derived[string(salt)] = key
+	return key, nil
+}
+
+func (k *EncryptionKey) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseSealed reports whether data is a value sealed by EncryptedStorage
+func parseSealed(data []byte) (*sealedValue, bool) {
+	var sealed sealedValue
+	if json.Unmarshal(data, &sealed) != nil || sealed.Encrypted == "" || sealed.Data == "" {
+		return nil, false
+	}
+	return &sealed, true
+}
+
+// EncryptedStorage encrypts values with AES-256-GCM before they reach the
+// storage it wraps, so tokens in server env and headers aren't stored in
+// plaintext in config, backups or a shared backend. Keys stay readable for
+// List. Values written before encryption was enabled are read as they are
+// until Rotate encrypts them.
+type EncryptedStorage struct {
+	backend Storage
+	mu      sync.RWMutex
+	key     *EncryptionKey
+	oldKeys []*EncryptionKey // Still accepted while Rotate runs
+}
+
+// NewEncryptedStorage wraps backend, encrypting new values with key
+func NewEncryptedStorage(backend Storage, key *EncryptionKey) (*EncryptedStorage, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("encryption key is required")
+	}
+	return &EncryptedStorage{backend: backend, key: key}, nil
+}
+
+// Backend returns the wrapped storage, which holds sealed values
+func (es *EncryptedStorage) Backend() Storage {
+	return es.backend
+}
+
+// Read reads and decrypts data from storage
+func (es *EncryptedStorage) Read(key string) ([]byte, error) {
+	data, err := es.backend.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	return es.decrypt(key, data)
+}
+
+// Write encrypts and writes data to storage
+func (es *EncryptedStorage) Write(key string, data []byte) error {
+	sealed, err := es.encrypt(key, data)
+	if err != nil {
+		return err
+	}
+	return es.backend.Write(key, sealed)
+}
+
+// ReadRevision reads and decrypts a key with the revision of its sealed
+// value
+func (es *EncryptedStorage) ReadRevision(key string) ([]byte, string, error) {
+	var sealed []byte
+	var revision string
+	var err error
+	if revisioned, ok := es.backend.(RevisionedStorage); ok {
+		sealed, revision, err = revisioned.ReadRevision(key)
+	} else {
+		sealed, err = es.backend.Read(key)
+		revision = ContentRevision(sealed)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := es.decrypt(key, sealed)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, revision, nil
+}
+
+// WriteIfRevision encrypts and writes a key if it is still at revision. The
+// check is only atomic across processes if the wrapped storage is a
+// RevisionedStorage.
+func (es *EncryptedStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	sealed, err := es.encrypt(key, data)
+	if err != nil {
+		return "", err
+	}
+	if revisioned, ok := es.backend.(RevisionedStorage); ok {
+		return revisioned.WriteIfRevision(key, sealed, revision)
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	current, err := es.backend.Read(key)
+	if err := checkRevision(key, current, err == nil, revision); err != nil {
+		return "", err
+	}
+	if err := es.backend.Write(key, sealed); err != nil {
+		return "", err
+	}
+	return ContentRevision(sealed), nil
+}
+
+// Delete removes data from storage
+func (es *EncryptedStorage) Delete(key string) error {
+	return es.backend.Delete(key)
+}
+
+// List lists keys with given prefix
+func (es *EncryptedStorage) List(prefix string) ([]string, error) {
+	return es.backend.List(prefix)
+}
+
+// Watch watches for changes to a key, passing handlers the decrypted data.
+// Changes that can't be decrypted with a known key are not delivered.
+func (es *EncryptedStorage) Watch(key string, handler func([]byte)) (func(), error) {
+	return es.backend.Watch(key, func(sealed []byte) {
+		if sealed == nil {
+			handler(nil)
+			return
+		}
+		data, err := es.decrypt(key, sealed)
+		if err != nil {
+			return
+		}
+		handler(data)
+	})
+}
+
+// Rotate re-encrypts every value with newKey, including values still in
+// plaintext. Values already re-encrypted stay readable if it fails, so it
+// can be run again. Each value is rewritten only if it didn't change since
+// it was read, so concurrent writes aren't lost.
+func (es *EncryptedStorage) Rotate(newKey *EncryptionKey) error {
+	if newKey == nil {
+		return fmt.Errorf("encryption key is required")
+	}
+
+	es.mu.Lock()
+	es.oldKeys = append(es.oldKeys, es.key)
+	es.key = newKey
+	es.mu.Unlock()
+
+	keys, err := es.backend.List("")
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+	for _, key := range keys {
+		if err := es.reencrypt(key); err != nil {
+			return err
+		}
+	}
+
+	es.mu.Lock()
+	es.oldKeys = nil
+	es.mu.Unlock()
+	return nil
+}
+
+// rotateAttempts bounds how often Rotate retries a key that keeps changing
+// under it
+const rotateAttempts = 10
+
+// reencrypt rewrites a key with the current key, reading it again when a
+// concurrent write gets in between. Keys deleted meanwhile are skipped.
+func (es *EncryptedStorage) reencrypt(key string) error {
+	var err error
+	for attempt := 0; attempt < rotateAttempts; attempt++ {
+		data, revision, readErr := es.ReadRevision(key)
+		if isNotFoundError(readErr) {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", key, readErr)
+		}
+
+		_, err = es.WriteIfRevision(key, data, revision)
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to re-encrypt %s: %w", key, err)
+	}
+	return nil
+}
+
+// RotateEncryptionKey re-encrypts the engine's storage with newKey, and
+// reseals the config file if one is loaded. Save newKey before rotating:
+// values are unreadable without it afterwards.
+func (e *engineImpl) RotateEncryptionKey(newKey *EncryptionKey) error {
+	encrypted, ok := e.getStorage().(*EncryptedStorage)
+	if !ok {
+		return fmt.Errorf("storage is not encrypted")
+	}
+	if err := encrypted.Rotate(newKey); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.configPath != "" {
+		if err := e.saveConfigNoLock(); err != nil {
+			return fmt.Errorf("failed to reseal config file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the wrapped storage if it can be closed
+func (es *EncryptedStorage) Close() error {
+	if closer, ok := es.backend.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (es *EncryptedStorage) encrypt(key string, data []byte) ([]byte, error) {
+	es.mu.RLock()
+	current := es.key
+	es.mu.RUnlock()
+
+	sealed, err := current.seal(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt %s: %w", key, err)
+	}
+	return sealed, nil
+}
+
+func (es *EncryptedStorage) decrypt(key string, data []byte) ([]byte, error) {
+	sealed, ok := parseSealed(data)
+	if !ok {
+		return data, nil
+	}
+	if sealed.Encrypted != encryptionCipher {
+		return nil, fmt.Errorf("failed to decrypt %s: unsupported cipher %q", key, sealed.Encrypted)
+	}
+
+	es.mu.RLock()
+	keys := append([]*EncryptionKey{es.key}, es.oldKeys...)
+	es.mu.RUnlock()
+
+	for _, candidate := range keys {
+		plain, matched, err := candidate.open(key, sealed)
+		if !matched {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", key, err)
+		}
+		return plain, nil
+	}
+	return nil, fmt.Errorf("failed to decrypt %s: sealed with unknown key %s", key, sealed.KeyID)
+}
+
+// exportSealKey is the storage key authenticated with sealed exports
+const exportSealKey = "export"
+
+// sealExport encrypts an export with the storage's current key
+func (es *EncryptedStorage) sealExport(data []byte) ([]byte, error) {
+	return es.encrypt(exportSealKey, data)
+}
+
+// openExport decrypts an export sealed by sealExport
+func (es *EncryptedStorage) openExport(data []byte) ([]byte, error) {
+	return es.decrypt(exportSealKey, data)
+}
+
+// WithEncryption encrypts everything the engine stores with key, whichever
+// storage backend is used
+func WithEncryption(key *EncryptionKey) Option {
+	return func(cfg *engineConfig) error {
+		if key == nil {
+			return fmt.Errorf("encryption key is required")
+		}
+		cfg.encryptionKey = key
+		return nil
+	}
+}
+
+// WithEncryptionKeyFile encrypts storage with the key in a key file written
+// by SaveKeyFile
+func WithEncryptionKeyFile(path string) Option {
+	return func(cfg *engineConfig) error {
+		key, err := LoadKeyFile(path)
+		if err != nil {
+			return err
+		}
+		cfg.encryptionKey = key
+		return nil
+	}
+}
+
+// WithEncryptionPassphrase encrypts storage with a key derived from
+// passphrase
+func WithEncryptionPassphrase(passphrase string) Option {
+	return func(cfg *engineConfig) error {
+		key, err := PassphraseKey(passphrase)
+		if err != nil {
+			return err
+		}
+		cfg.encryptionKey = key
+		return nil
+	}
+}
@@ -0,0 +1,329 @@
+package engine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedStorage(t *testing.T) {
+	const token = "sk-secret-token"
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := NewMemoryStorage()
+	engine, err := NewEngine(WithStorage(backend), WithEncryption(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("api", ServerConfig{
+		Transport: "stdio",
+		Command:   "api",
+		Env:       map[string]string{"API_TOKEN": token},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := engine.CreateBackup("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing reaches the backend in plaintext
+	keys, _ := backend.List("")
+	for _, k := range keys {
+		data, _ := backend.Read(k)
+		if strings.Contains(string(data), token) {
+			t.Errorf("Expected %s to be encrypted, got %s", k, data)
+		}
+	}
+
+	// Exports stay sealed unless decrypted explicitly
+	storage := engine.(*engineImpl).storage.(*EncryptedStorage)
+	var sealed, plain bytes.Buffer
+	if err := ExportStorage(storage, &sealed); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sealed.String(), token) {
+		t.Error("Expected the export to be encrypted")
+	}
+	if err := ExportStorageDecrypted(storage, &plain); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plain.String(), token) {
+		t.Error("Expected the decrypted export to contain the token")
+	}
+
+	// A sealed export imports into storage with the same key
+	imported, _ := NewEncryptedStorage(NewMemoryStorage(), key)
+	if err := ImportStorage(imported, &sealed); err != nil {
+		t.Fatalf("ImportStorage failed: %v", err)
+	}
+	reopened, err := NewEngine(WithStorage(imported.Backend()), WithEncryption(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := reopened.GetServer("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.Env["API_TOKEN"] != token {
+		t.Errorf("Expected the token to survive, got %q", server.Env["API_TOKEN"])
+	}
+	if err := reopened.RestoreBackup(backup.ID); err != nil {
+		t.Errorf("RestoreBackup failed: %v", err)
+	}
+
+	// The wrong key can't read anything
+	otherKey, _ := GenerateEncryptionKey()
+	other, _ := NewEncryptedStorage(backend, otherKey)
+	if _, err := other.Read(Keys.Config()); err == nil {
+		t.Error("Expected reading with another key to fail")
+	}
+
+	// Rotation re-encrypts everything, including plaintext values
+	if err := backend.Write("legacy", []byte(`{"token":"`+token+`"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Rotate(otherKey); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if data, _ := backend.Read("legacy"); strings.Contains(string(data), token) {
+		t.Error("Expected the plaintext value to be encrypted by Rotate")
+	}
+	if data, err := other.Read("legacy"); err != nil || !strings.Contains(string(data), token) {
+		t.Errorf("Expected the new key to read rotated values: %s, %v", data, err)
+	}
+	stale, _ := NewEncryptedStorage(backend, key)
+	if _, err := stale.Read(Keys.Config()); err == nil {
+		t.Error("Expected the old key to be useless after rotation")
+	}
+}
+
+func TestEncryptionKeys(t *testing.T) {
+	// Passphrase keys derive the same key from a value's salt
+	first, err := PassphraseKey("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := PassphraseKey("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := NewMemoryStorage()
+	writer, _ := NewEncryptedStorage(backend, first)
+	if err := writer.Write("config", []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	reader, _ := NewEncryptedStorage(backend, second)
+	if data, err := reader.Read("config"); err != nil || string(data) != `{"a":1}` {
+		t.Errorf("Expected the same passphrase to decrypt, got %s, %v", data, err)
+	}
+	wrong, _ := PassphraseKey("wrong")
+	wrongReader, _ := NewEncryptedStorage(backend, wrong)
+	if _, err := wrongReader.Read("config"); err == nil {
+		t.Error("Expected a wrong passphrase to fail")
+	}
+
+	// Key files round-trip and are private
+	path := filepath.Join(t.TempDir(), "storage.key")
+	key, _ := GenerateEncryptionKey()
+	if err := SaveKeyFile(path, key); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ID() != key.ID() {
+		t.Errorf("Expected key %s, got %s", key.ID(), loaded.ID())
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode().Perm() != SecretFileMode {
+		t.Errorf("Expected mode %o, got %o", SecretFileMode, info.Mode().Perm())
+	}
+	if err := SaveKeyFile(path, first); err == nil {
+		t.Error("Expected passphrase keys not to be saved")
+	}
+}
+
+func TestEncryptedExportToFile(t *testing.T) {
+	const token = "sk-secret-token"
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewEngine(WithMemoryStorage(), WithEncryption(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("api", ServerConfig{
+		Transport: "stdio",
+		Command:   "api",
+		Env:       map[string]string{"API_TOKEN": token},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Exports are sealed unless decrypted explicitly
+	if data, err := engine.Export(ExportFormatJSON); err != nil || strings.Contains(string(data), token) {
+		t.Errorf("Expected Export to be encrypted, got %s, %v", data, err)
+	}
+	if data, err := engine.ExportDecrypted(ExportFormatJSON); err != nil || !strings.Contains(string(data), token) {
+		t.Errorf("Expected ExportDecrypted to contain the token, got %s, %v", data, err)
+	}
+	dir := t.TempDir()
+	sealedPath := filepath.Join(dir, "sealed.json")
+	if err := engine.ExportToFile(sealedPath, ExportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	sealed, _ := os.ReadFile(sealedPath)
+	if strings.Contains(string(sealed), token) {
+		t.Errorf("Expected the export to be encrypted, got %s", sealed)
+	}
+	plainPath := filepath.Join(dir, "plain.json")
+	if err := engine.ExportToFileDecrypted(plainPath, ExportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	plain, _ := os.ReadFile(plainPath)
+	if !strings.Contains(string(plain), token) {
+		t.Error("Expected the decrypted export to contain the token")
+	}
+	if info, err := os.Stat(plainPath); err == nil && info.Mode().Perm() != SecretFileMode {
+		t.Errorf("Expected a plaintext export with tokens to be private, got %v", info.Mode().Perm())
+	}
+
+	// A sealed export imports with the key, and only with it
+	withKey, _ := NewEngine(WithMemoryStorage(), WithEncryption(key))
+	if err := withKey.Import(sealed, ImportFormatJSON, ImportOptions{}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if server, err := withKey.GetServer("api"); err != nil || server.Env["API_TOKEN"] != token {
+		t.Errorf("Expected the sealed export to import, got %+v, %v", server, err)
+	}
+	withoutKey, _ := NewEngine(WithMemoryStorage())
+	if err := withoutKey.Import(sealed, ImportFormatJSON, ImportOptions{}); err == nil {
+		t.Error("Expected importing a sealed export without a key to fail")
+	}
+}
+
+func TestEncryptedConfigFile(t *testing.T) {
+	const token = "sk-secret-token"
+	key, err := GenerateEncryptionKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.json")
+	engine, err := NewEngine(WithMemoryStorage(), WithEncryption(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("api", ServerConfig{
+		Transport: "stdio",
+		Command:   "api",
+		Env:       map[string]string{"API_TOKEN": token},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The config file is sealed like the stored config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), token) {
+		t.Errorf("Expected the config file to be encrypted, got %s", data)
+	}
+
+	// It loads with the key, and only with it
+	withKey, _ := NewEngine(WithMemoryStorage(), WithEncryption(key))
+	if err := withKey.LoadConfig(path); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if server, err := withKey.GetServer("api"); err != nil || server.Env["API_TOKEN"] != token {
+		t.Errorf("Expected the sealed config file to load, got %+v, %v", server, err)
+	}
+	withoutKey, _ := NewEngine(WithMemoryStorage())
+	if err := withoutKey.LoadConfig(path); err == nil {
+		t.Error("Expected loading a sealed config file without a key to fail")
+	}
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	const token = "sk-secret-token"
+	oldKey, _ := GenerateEncryptionKey()
+	newKey, _ := GenerateEncryptionKey()
+	path := filepath.Join(t.TempDir(), "config.json")
+	engine, err := NewEngine(WithMemoryStorage(), WithEncryption(oldKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.LoadConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("api", ServerConfig{
+		Transport: "stdio",
+		Command:   "api",
+		Env:       map[string]string{"API_TOKEN": token},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.RotateEncryptionKey(newKey); err != nil {
+		t.Fatalf("RotateEncryptionKey failed: %v", err)
+	}
+
+	// The config file is resealed with the new key
+	withNewKey, _ := NewEngine(WithMemoryStorage(), WithEncryption(newKey))
+	if err := withNewKey.LoadConfig(path); err != nil {
+		t.Fatalf("Expected the config file to load with the new key: %v", err)
+	}
+	withOldKey, _ := NewEngine(WithMemoryStorage(), WithEncryption(oldKey))
+	if err := withOldKey.LoadConfig(path); err == nil {
+		t.Error("Expected the old key to no longer open the config file")
+	}
+
+	unencrypted, _ := NewEngine(WithMemoryStorage())
+	if err := unencrypted.RotateEncryptionKey(newKey); err == nil {
+		t.Error("Expected rotating unencrypted storage to fail")
+	}
+}
+
+// racingStorage writes over a key right after Rotate first reads it
+type racingStorage struct {
+	*MemoryStorage
+	key   string
+	value []byte
+	raced bool
+}
+
+func (rs *racingStorage) ReadRevision(key string) ([]byte, string, error) {
+	data, revision, err := rs.MemoryStorage.ReadRevision(key)
+	if key == rs.key && !rs.raced {
+		rs.raced = true
+		rs.MemoryStorage.Write(key, rs.value)
+	}
+	return data, revision, err
+}
+
+func TestRotateKeepsConcurrentWrites(t *testing.T) {
+	oldKey, _ := GenerateEncryptionKey()
+	newKey, _ := GenerateEncryptionKey()
+	backend := &racingStorage{MemoryStorage: NewMemoryStorage(), key: "config"}
+	storage, _ := NewEncryptedStorage(backend, oldKey)
+	if err := storage.Write("config", []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	backend.value, _ = storage.encrypt("config", []byte(`{"a":2}`))
+
+	if err := storage.Rotate(newKey); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	rotated, _ := NewEncryptedStorage(backend.MemoryStorage, newKey)
+	if data, err := rotated.Read("config"); err != nil || string(data) != `{"a":2}` {
+		t.Errorf("Expected the concurrent write to survive rotation, got %s, %v", data, err)
+	}
+}
@@ -35,6 +35,9 @@ type Engine interface {
 
 	// Import/Export (format agnostic)
 	Export(format ExportFormat) ([]byte, error)
+	ExportDecrypted(format ExportFormat) ([]byte, error)
+	ExportToFile(path string, format ExportFormat) error
+	ExportToFileDecrypted(path string, format ExportFormat) error
 	Import(data []byte, format ImportFormat, options ImportOptions) error
 	MergeConfigs(configs ...*Config) (*Config, error)
 
@@ -49,21 +52,37 @@ type Engine interface {
 	RegisterProject(path string, config ProjectConfig) error
 	GetProjectConfig(path string) (*ProjectConfig, error)
 	ListProjects() ([]*ProjectInfo, error)
+	SyncProject(ctx context.Context, path string, options SyncOptions) (*MultiSyncResult, error)
 
 	// Auto-sync Management
 	StartAutoSync(config AutoSyncConfig) error
 	StopAutoSync() error
 	GetAutoSyncStatus() (*AutoSyncStatus, error)
 
+	// Scheduled Jobs
+	StartScheduler() error
+	StopScheduler() error
+	ListScheduledJobs() ([]*ScheduledJobStatus, error)
+	RunScheduledJob(ctx context.Context, name string) (*ScheduledJobStatus, error)
+
 	// Backup/Restore
 	CreateBackup(description string) (*BackupInfo, error)
 	ListBackups() ([]*BackupInfo, error)
 	RestoreBackup(backupID string) error
 
+	// Storage Migration
+	MigrateStorage(ctx context.Context, dst Storage, options MigrationOptions) (*MigrationResult, error)
+
+	// Encryption
+	RotateEncryptionKey(newKey *EncryptionKey) error
+
 	// Event Handling
 	OnConfigChange(handler ConfigChangeHandler) func()
 	OnSyncComplete(handler SyncCompleteHandler) func()
 	OnError(handler ErrorHandler) func()
+
+	// Lifecycle
+	Close() error
 }
 
 // Storage interface for persistence layer abstraction
@@ -72,9 +91,27 @@ type Storage interface {
 	Write(key string, data []byte) error
 	Delete(key string) error
 	List(prefix string) ([]string, error)
+	// Watch calls handler with the key's new data after each change, or
+	// with nil when the key is deleted
 	Watch(key string, handler func([]byte)) (func(), error)
 }
 
+// BatchReader is implemented by storage backends that can read many keys in
+// one round trip. Keys that don't exist are left out of the result.
+type BatchReader interface {
+	ReadMany(keys []string) (map[string][]byte, error)
+}
+
+// RevisionedStorage is implemented by storage backends that support
+// conditional writes. ReadRevision returns a key's data with its revision;
+// WriteIfRevision writes only if the key is still at revision, where the empty
+// revision means the key must not exist, and returns the new revision. A
+// mismatch returns a *ConflictError.
+type RevisionedStorage interface {
+	ReadRevision(key string) ([]byte, string, error)
+	WriteIfRevision(key string, data []byte, revision string) (string, error)
+}
+
 // Moved to types.go
 
 // Moved to types.go
@@ -104,6 +141,60 @@ type Destination interface {
 	Backup() (string, error)
 }
 
+// ConfigParser is implemented by destinations whose files aren't in the JSON
+// shape produced by Transform. It turns the bytes returned by Read back into a
+// Config for previews and imports.
+type ConfigParser interface {
+	ParseConfig(data []byte) (*Config, error)
+}
+
+// ProjectDestination is implemented by destinations that write a file inside
+// each project, such as .vscode/mcp.json. SyncProject syncs to the destination
+// returned by ForProject. Project-only destinations are skipped when syncing
+// the global config to all destinations.
+type ProjectDestination interface {
+	ForProject(projectPath string) Destination
+	ProjectOnly() bool
+}
+
+// ChangeWriter is implemented by destinations that record what a sync
+// changed, such as GitDestination's commit messages. Syncing calls
+// WriteChanges instead of Write.
+type ChangeWriter interface {
+	WriteChanges(data []byte, changes []Change) error
+}
+
+// ValidatingDestination is implemented by destinations with their own rules
+// for server names and configs. SyncTo and PreviewSync sanitize names the
+// destination needs sanitized and skip servers that still fail validation,
+// reporting them as errors. Either method may return nil.
+type ValidatingDestination interface {
+	Validator() ServerValidator
+	Sanitizer() NameSanitizer
+}
+
+// RelocatableDestination is implemented by file destinations that can write
+// their format to another file. GitDestination writes the file in the
+// repository through the destination returned by WithPath.
+type RelocatableDestination interface {
+	WithPath(path string) Destination
+}
+
+// ExistenceChecker is implemented by destinations whose existence check can
+// fail, such as remote endpoints. CheckExists returns an error when it
+// can't tell, where Exists would report false. Syncing uses it instead of
+// Exists, so a failed check doesn't look like a missing destination.
+type ExistenceChecker interface {
+	CheckExists() (bool, error)
+}
+
+// LockingDestination is implemented by destinations that other processes
+// may write too. Syncing holds the lock from reading the existing config
+// until the write completes, waiting up to timeout for it.
+type LockingDestination interface {
+	Lock(timeout time.Duration) (unlock func() error, err error)
+}
+
 // Moved to types.go
 
 // Types moved to types.go
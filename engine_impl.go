@@ -3,23 +3,38 @@ package engine
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // engineImpl is the concrete implementation of Engine interface
 type engineImpl struct {
 	storage      Storage
+	storageMu    sync.RWMutex // Guards storage, which MigrateStorage may switch
 	config       *Config
 	configPath   string
 	destinations map[string]Destination
 	autoSync     *autoSyncManager
+	scheduler    *schedulerManager
 	syncManager  *SyncManager
 	eventBus     *eventBus
 	validator    ServerValidator
 	sanitizer    NameSanitizer
-	mu           sync.RWMutex
+	lockTimeout  time.Duration
+	// Revision of the stored config this engine last loaded or saved
+	configRevision string
+	unwatchConfig  func()
+	done           chan struct{} // Closed by Close
+	closeOnce      sync.Once
+	mu             sync.RWMutex
+
+	// Set when saving a changed config failed, so listings cached for the
+	// stored revision are out of date. Saves may run under the read lock.
+	unsavedConfig atomic.Bool
 }
 
 // NewEngine creates a new engine instance
@@ -27,11 +42,13 @@ func NewEngine(opts ...Option) (Engine, error) {
 	e := &engineImpl{
 		destinations: make(map[string]Destination),
 		eventBus:     newEventBus(),
+		done:         make(chan struct{}),
 	}
 
 	// Apply options
 	cfg := &engineConfig{
 		storagePath: "~/.agent-master",
+		lockTimeout: DefaultLockTimeout,
 	}
 
 	for _, opt := range opts {
@@ -45,13 +62,35 @@ func NewEngine(opts ...Option) (Engine, error) {
 	// Initialize storage
 	if cfg.storage != nil {
 		e.storage = cfg.storage
+	} else if cfg.boltPath != "" {
+		storage, err := openBoltStorage(cfg.boltPath, cfg.lockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		e.storage = storage
 	} else {
 		storage, err := NewFileStorage(cfg.storagePath)
 		if err != nil {
 			return nil, err
 		}
+		storage.SetLockTimeout(cfg.lockTimeout)
+		e.storage = storage
+	}
+	if cfg.cacheEntries > 0 {
+		storage, err := NewCachingStorage(e.storage, cfg.cacheEntries)
+		if err != nil {
+			return nil, err
+		}
+		e.storage = storage
+	}
+	if cfg.encryptionKey != nil {
+		storage, err := NewEncryptedStorage(e.storage, cfg.encryptionKey)
+		if err != nil {
+			return nil, err
+		}
 		e.storage = storage
 	}
+	e.lockTimeout = cfg.lockTimeout
 
 	// Claude adapter is now optional and should be set explicitly if needed
 	// e.SetClaudeAdapter(adapter)
@@ -62,9 +101,12 @@ func NewEngine(opts ...Option) (Engine, error) {
 	// Initialize auto-sync manager
 	e.autoSync = newAutoSyncManager(e)
 
+	// Initialize scheduler
+	e.scheduler = newSchedulerManager(e)
+
 	// Initialize config with defaults
 	e.config = &Config{
-		Version: "1.0.2",
+		Version: DefaultConfigVersion,
 		Servers: make(map[string]ServerWithMetadata),
 		Settings: Settings{
 			AutoSync: AutoSyncSettings{
@@ -74,7 +116,7 @@ func NewEngine(opts ...Option) (Engine, error) {
 			},
 			Backup: BackupSettings{
 				Enabled:    true,
-				Location:   "~/.agent-master/backups",
+				BackupPath: "~/.agent-master/backups",
 				MaxBackups: 10,
 			},
 			ConflictResolution: ConflictSettings{
@@ -98,10 +140,20 @@ func NewEngine(opts ...Option) (Engine, error) {
 
 	// Auto-load config from storage on initialization
 	if err := e.autoLoadConfig(); err != nil {
+		var versionErr *ConfigVersionError
+		if errors.As(err, &versionErr) {
+			// Running with defaults would overwrite the newer config
+			return nil, err
+		}
 		// Log warning but don't fail - we'll use defaults
 		e.eventBus.emit(EventWarning, fmt.Sprintf("failed to auto-load config: %v", err))
 	}
 
+	// Pick up config saved by other engines sharing the storage
+	if err := e.watchStoredConfig(); err != nil {
+		e.eventBus.emit(EventWarning, fmt.Sprintf("failed to watch config: %v", err))
+	}
+
 	// Auto-start auto-sync if it was enabled in persisted settings
 	if e.config.Settings.AutoSync.Enabled {
 		// Convert settings to AutoSyncConfig
@@ -115,7 +167,11 @@ func NewEngine(opts ...Option) (Engine, error) {
 		// Start auto-sync in background
 		go func() {
 			// Small delay to ensure engine is fully initialized
-			time.Sleep(100 * time.Millisecond)
+			select {
+			case <-time.After(100 * time.Millisecond):
+			case <-e.done:
+				return
+			}
 			if err := e.autoSync.Start(autoSyncConfig); err != nil {
 				e.eventBus.emit(EventError, fmt.Errorf("failed to auto-start auto-sync: %w", err))
 			}
@@ -125,11 +181,37 @@ func NewEngine(opts ...Option) (Engine, error) {
 	return e, nil
 }
 
+// Close stops auto-sync and the scheduler, stops watching the stored config
+// and closes the storage, releasing its file watches and locks. The engine
+// must not be used afterwards.
+func (e *engineImpl) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		close(e.done)
+
+		// Both fail only when not running
+		e.autoSync.Stop()
+		e.scheduler.Stop()
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if e.unwatchConfig != nil {
+			e.unwatchConfig()
+			e.unwatchConfig = nil
+		}
+		if closer, ok := e.getStorage().(io.Closer); ok {
+			err = closer.Close()
+		}
+	})
+	return err
+}
+
 // autoLoadConfig attempts to load config from storage
 func (e *engineImpl) autoLoadConfig() error {
 	// Try to load from storage
 	var loadedConfig Config
-	if err := LoadJSON(e.storage, Keys.Config(), &loadedConfig); err != nil {
+	migrated, err := e.loadStoredConfig(&loadedConfig)
+	if err != nil {
 		// If not found, that's OK - we'll use defaults
 		if !isNotFoundError(err) {
 			return fmt.Errorf("failed to load config from storage: %w", err)
@@ -151,7 +233,7 @@ func (e *engineImpl) autoLoadConfig() error {
 
 	// Set version if missing
 	if e.config.Version == "" {
-		e.config.Version = "1.0.2"
+		e.config.Version = DefaultConfigVersion
 	}
 
 	// Set default settings if missing
@@ -162,6 +244,13 @@ func (e *engineImpl) autoLoadConfig() error {
 		e.config.Settings.AutoSync.DebounceDelay = 500 * time.Millisecond
 	}
 
+	// Persist the migrated config so the migration runs once
+	if migrated {
+		if err := e.saveConfigNoLock(); err != nil {
+			return fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+
 	// Emit event
 	e.eventBus.emit(EventConfigLoaded, ConfigChange{
 		Type:      "config-auto-loaded",
@@ -189,10 +278,21 @@ func (e *engineImpl) autoLoadConfig() error {
 // Option configuration
 type Option func(*engineConfig) error
 
+// getStorage returns the storage the engine currently uses
+func (e *engineImpl) getStorage() Storage {
+	e.storageMu.RLock()
+	defer e.storageMu.RUnlock()
+	return e.storage
+}
+
 type engineConfig struct {
 	storage           Storage
 	storagePath       string
 	useDefaultTargets bool
+	lockTimeout       time.Duration
+	boltPath          string
+	encryptionKey     *EncryptionKey
+	cacheEntries      int
 }
 
 func WithStorage(storage Storage) Option {
@@ -205,6 +305,7 @@ func WithStorage(storage Storage) Option {
 func WithFileStorage(path string) Option {
 	return func(cfg *engineConfig) error {
 		cfg.storagePath = path
+		cfg.boltPath = ""
 		return nil
 	}
 }
@@ -216,6 +317,18 @@ func WithMemoryStorage() Option {
 	}
 }
 
+// WithLockTimeout sets how long writes wait for another process holding a
+// file lock on storage or a destination. Zero fails immediately.
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(cfg *engineConfig) error {
+		if timeout < 0 {
+			return fmt.Errorf("lock timeout must not be negative")
+		}
+		cfg.lockTimeout = timeout
+		return nil
+	}
+}
+
 func WithDefaultTargets() Option {
 	return func(cfg *engineConfig) error {
 		cfg.useDefaultTargets = true
@@ -235,6 +348,16 @@ func (e *engineImpl) SyncToTarget(ctx context.Context, targetName string, option
 }
 
 func (e *engineImpl) SyncTo(ctx context.Context, dest Destination, options SyncOptions) (*SyncResult, error) {
+	e.mu.RLock()
+	config := e.config
+	e.mu.RUnlock()
+
+	return e.syncConfigTo(ctx, dest, config, options)
+}
+
+// syncConfigTo syncs the given config to a destination
+func (e *engineImpl) syncConfigTo(ctx context.Context, dest Destination, config *Config, options SyncOptions) (*SyncResult, error) {
+	options.normalize()
 	start := time.Now()
 	result := &SyncResult{
 		Destination:    dest.GetID(),
@@ -248,16 +371,16 @@ func (e *engineImpl) SyncTo(ctx context.Context, dest Destination, options SyncO
 		ServersRemoved: 0,
 	}
 
-	e.mu.RLock()
-	config := e.config
-	e.mu.RUnlock()
+	// Apply the destination's own name and config rules
+	config, skipped := prepareForDestination(dest, config)
+	result.Errors = append(result.Errors, skipped...)
 
 	// Transform config for destination
 	transformedConfig, err := dest.Transform(config)
 	if err != nil {
 		result.Errors = append(result.Errors, SyncError{
 			Error:       fmt.Sprintf("transform failed: %v", err),
-			Recoverable: false,
+			Recoverable: isRecoverable(err),
 		})
 		return result, fmt.Errorf("failed to transform config: %w", err)
 	}
@@ -272,8 +395,32 @@ func (e *engineImpl) SyncTo(ctx context.Context, dest Destination, options SyncO
 		return result, fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	// Keep other processes out until the write completes
+	if locker, ok := dest.(LockingDestination); ok && !options.DryRun {
+		unlock, err := locker.Lock(e.lockTimeout)
+		if err != nil {
+			result.Errors = append(result.Errors, SyncError{
+				Error:       fmt.Sprintf("lock failed: %v", err),
+				Recoverable: isRecoverable(err),
+			})
+			return result, fmt.Errorf("failed to lock destination: %w", err)
+		}
+		defer unlock()
+	}
+
+	// A destination that can't tell whether it exists can't be diffed or
+	// written safely
+	exists, err := destinationExists(dest)
+	if err != nil {
+		result.Errors = append(result.Errors, SyncError{
+			Error:       fmt.Sprintf("existence check failed: %v", err),
+			Recoverable: isRecoverable(err),
+		})
+		return result, fmt.Errorf("failed to check destination: %w", err)
+	}
+
 	// Create backup if requested and destination supports it
-	if options.CreateBackup && dest.SupportsBackup() && dest.Exists() && !options.DryRun {
+	if options.CreateBackup && dest.SupportsBackup() && exists && !options.DryRun {
 		backupPath, err := dest.Backup()
 		if err != nil {
 			result.Errors = append(result.Errors, SyncError{
@@ -285,35 +432,44 @@ func (e *engineImpl) SyncTo(ctx context.Context, dest Destination, options SyncO
 		}
 	}
 
-	// Calculate changes by comparing with existing
-	if dest.Exists() {
-		existingData, err := dest.Read()
-		if err == nil {
-			var existing interface{}
-			json.Unmarshal(existingData, &existing)
-			result.Changes = e.calculateChanges(existing, transformedConfig)
-		}
-	} else {
-		// New destination - all servers are added
-		result.ServersAdded = len(config.Servers)
-		for name := range config.Servers {
-			result.Changes = append(result.Changes, Change{
-				Type:   "add",
-				Server: name,
-				After:  config.Servers[name].ServerConfig,
-			})
+	// Calculate changes by comparing with the servers the destination has,
+	// parsed in its own format
+	var existingServers map[string]ServerWithMetadata
+	if exists {
+		existingServers, _ = readDestinationServers(dest)
+	}
+	result.Changes = diffServers(config, existingServers)
+	for _, change := range result.Changes {
+		switch change.Type {
+		case ChangeTypeAdd:
+			result.ServersAdded++
+		case ChangeTypeUpdate:
+			result.ServersUpdated++
+		case ChangeTypeDelete:
+			result.ServersRemoved++
 		}
 	}
 
 	// Write if not dry run
 	if !options.DryRun {
-		if err := dest.Write(data); err != nil {
+		var err error
+		if cw, ok := dest.(ChangeWriter); ok {
+			err = cw.WriteChanges(data, result.Changes)
+		} else {
+			err = dest.Write(data)
+		}
+		if err != nil {
 			result.Errors = append(result.Errors, SyncError{
 				Error:       fmt.Sprintf("write failed: %v", err),
-				Recoverable: false,
+				Recoverable: isRecoverable(err),
 			})
 			return result, fmt.Errorf("failed to write to destination: %w", err)
 		}
+		for _, server := range config.Servers {
+			if server.Internal.Enabled {
+				result.ServersWritten++
+			}
+		}
 	}
 
 	result.Success = true
@@ -404,98 +560,26 @@ func (e *engineImpl) PreviewSync(dest Destination) (*SyncPreview, error) {
 		Changes:     []Change{},
 	}
 
-	// Get current config
-	currentConfig := e.config
+	// Get current config, as the destination will receive it
+	currentConfig, skipped := prepareForDestination(dest, e.config)
 	if currentConfig == nil {
 		return preview, nil
 	}
+	preview.Errors = skipped
 
 	// Read existing config from destination if it exists
-	var existingConfig interface{}
-	if dest.Exists() {
-		existingData, err := dest.Read()
-		if err == nil {
-			// Try to parse existing data
-			var existing map[string]interface{}
-			if json.Unmarshal(existingData, &existing) == nil {
-				existingConfig = existing
-			}
-		}
+	exists, err := destinationExists(dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check destination: %w", err)
 	}
-
-	// Calculate changes
-	if existingConfig != nil {
-		// Compare existing vs new
-		existingServers := make(map[string]ServerWithMetadata)
-		if existingMap, ok := existingConfig.(map[string]interface{}); ok {
-			if servers, ok := existingMap["mcpServers"].(map[string]interface{}); ok {
-				for name, serverData := range servers {
-					// Convert back to ServerWithMetadata for comparison
-					if serverMap, ok := serverData.(map[string]interface{}); ok {
-						server := ServerWithMetadata{}
-						// Basic conversion - can be enhanced
-						if transport, ok := serverMap["transport"].(string); ok {
-							server.Transport = transport
-						}
-						if command, ok := serverMap["command"].(string); ok {
-							server.Command = command
-						}
-						existingServers[name] = server
-					}
-				}
-			}
-		}
-
-		// Compare with current config
-		for name, server := range currentConfig.Servers {
-			if server.Internal.Enabled {
-				if existingServer, exists := existingServers[name]; exists {
-					// Check if server changed
-					if !isServerEqual(server.ServerConfig, existingServer.ServerConfig) {
-						preview.Changes = append(preview.Changes, Change{
-							Type:   "update",
-							Server: name,
-							Before: existingServer.ServerConfig,
-							After:  server.ServerConfig,
-						})
-					}
-				} else {
-					// Server added
-					preview.Changes = append(preview.Changes, Change{
-						Type:   "add",
-						Server: name,
-						Before: nil,
-						After:  server.ServerConfig,
-					})
-				}
-			}
-		}
-
-		// Check for removed servers
-		for name, existingServer := range existingServers {
-			if _, exists := currentConfig.Servers[name]; !exists {
-				// Server removed
-				preview.Changes = append(preview.Changes, Change{
-					Type:   "remove",
-					Server: name,
-					Before: existingServer.ServerConfig,
-					After:  nil,
-				})
-			}
-		}
-	} else {
-		// No existing config, everything is new
-		for name, server := range currentConfig.Servers {
-			if server.Internal.Enabled {
-				preview.Changes = append(preview.Changes, Change{
-					Type:   "add",
-					Server: name,
-					Before: nil,
-					After:  server.ServerConfig,
-				})
-			}
+	var existingServers map[string]ServerWithMetadata
+	if exists {
+		var found bool
+		if existingServers, found = readDestinationServers(dest); found {
+			preview.RequiresBackup = dest.SupportsBackup()
 		}
 	}
+	preview.Changes = diffServers(currentConfig, existingServers)
 
 	// Estimate time based on number of changes
 	preview.EstimatedTime = time.Duration(len(preview.Changes)*50) * time.Millisecond
@@ -514,6 +598,11 @@ func (e *engineImpl) OnConfigChange(handler ConfigChangeHandler) func() {
 		e.eventBus.on(EventAutoSyncStarted, handler),
 		e.eventBus.on(EventAutoSyncStopped, handler),
 		e.eventBus.on(EventFileChanged, handler),
+		e.eventBus.on(EventProjectUpdated, handler),
+		e.eventBus.on(EventProjectSynced, handler),
+		e.eventBus.on(EventDriftDetected, handler),
+		e.eventBus.on(EventJobCompleted, handler),
+		e.eventBus.on(EventJobFailed, handler),
 	}
 
 	// Return a function that unsubscribes from all
@@ -533,72 +622,63 @@ func (e *engineImpl) OnError(handler ErrorHandler) func() {
 	return func() {}
 }
 
-// calculateChanges compares existing and transformed configs to detect changes
-func (e *engineImpl) calculateChanges(existing, transformed interface{}) []Change {
-	changes := []Change{}
-
-	// Extract servers from both configs
-	existingServers := make(map[string]interface{})
-	transformedServers := make(map[string]interface{})
-
-	// Handle different config formats
-	switch ex := existing.(type) {
-	case map[string]interface{}:
-		if servers, ok := ex["mcpServers"].(map[string]interface{}); ok {
-			existingServers = servers
-		}
-	case *Config:
-		for name, server := range ex.Servers {
-			existingServers[name] = server.ServerConfig
-		}
+// destinationExists reports whether dest exists, with the error of
+// destinations that implement ExistenceChecker
+func destinationExists(dest Destination) (bool, error) {
+	if checker, ok := dest.(ExistenceChecker); ok {
+		return checker.CheckExists()
 	}
+	return dest.Exists(), nil
+}
 
-	switch tr := transformed.(type) {
-	case map[string]interface{}:
-		if servers, ok := tr["mcpServers"].(map[string]interface{}); ok {
-			transformedServers = servers
-		}
-	case *Config:
-		for name, server := range tr.Servers {
-			transformedServers[name] = server.ServerConfig
-		}
+// readDestinationServers reads and parses the servers of an existing
+// destination. It reports false if they can't be read or parsed.
+func readDestinationServers(dest Destination) (map[string]ServerWithMetadata, bool) {
+	data, err := dest.Read()
+	if err != nil {
+		return nil, false
 	}
-
-	// Find added servers
-	for name, server := range transformedServers {
-		if _, exists := existingServers[name]; !exists {
-			changes = append(changes, Change{
-				Type:   "add",
-				Server: name,
-				After:  server,
-			})
-		}
+	existing, err := parseDestinationConfig(dest, data)
+	if err != nil {
+		return nil, false
 	}
+	return existing.Servers, true
+}
+
+// diffServers returns the changes syncing the enabled servers of config makes
+// to the existing servers of a destination
+func diffServers(config *Config, existing map[string]ServerWithMetadata) []Change {
+	changes := []Change{}
 
-	// Find updated servers
-	for name, transformedServer := range transformedServers {
-		if existingServer, exists := existingServers[name]; exists {
-			// Simple comparison - in real implementation would do deep comparison
-			existingJSON, _ := json.Marshal(existingServer)
-			transformedJSON, _ := json.Marshal(transformedServer)
-			if string(existingJSON) != string(transformedJSON) {
+	for name, server := range config.Servers {
+		if !server.Internal.Enabled {
+			continue
+		}
+		if existingServer, exists := existing[name]; exists {
+			if !isServerEqual(server.ServerConfig, existingServer.ServerConfig) {
 				changes = append(changes, Change{
-					Type:   "update",
+					Type:   ChangeTypeUpdate,
 					Server: name,
-					Before: existingServer,
-					After:  transformedServer,
+					Before: existingServer.ServerConfig,
+					After:  server.ServerConfig,
 				})
 			}
+		} else {
+			changes = append(changes, Change{
+				Type:   ChangeTypeAdd,
+				Server: name,
+				After:  server.ServerConfig,
+			})
 		}
 	}
 
-	// Find removed servers
-	for name := range existingServers {
-		if _, exists := transformedServers[name]; !exists {
+	// Disabled servers are removed from the destination too
+	for name, existingServer := range existing {
+		if server, exists := config.Servers[name]; !exists || !server.Internal.Enabled {
 			changes = append(changes, Change{
-				Type:   "remove",
+				Type:   ChangeTypeDelete,
 				Server: name,
-				Before: existingServers[name],
+				Before: existingServer.ServerConfig,
 			})
 		}
 	}
@@ -608,6 +688,15 @@ func (e *engineImpl) calculateChanges(existing, transformed interface{}) []Chang
 
 // Destination Management methods moved to destination_manager.go
 
+// parseDestinationConfig parses data read from a destination, using the
+// destination's own parser when it has one
+func parseDestinationConfig(dest Destination, data []byte) (*Config, error) {
+	if parser, ok := dest.(ConfigParser); ok {
+		return parser.ParseConfig(data)
+	}
+	return ParseMCPConfigWithOptions(data, false)
+}
+
 // isServerEqual compares two ServerConfig instances
 func isServerEqual(a, b ServerConfig) bool {
 	// Basic comparison - can be enhanced
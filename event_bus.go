@@ -74,7 +74,9 @@ func (eb *eventBus) emit(event EventType, data interface{}) {
 		go func(h interface{}) {
 			switch event {
 			case EventConfigLoaded, EventConfigSaved, EventConfigChanged,
-				EventAutoSyncStarted, EventAutoSyncStopped, EventFileChanged:
+				EventAutoSyncStarted, EventAutoSyncStopped, EventFileChanged,
+				EventProjectUpdated, EventProjectSynced,
+				EventDriftDetected, EventJobCompleted, EventJobFailed:
 				if fn, ok := h.(func(ConfigChange)); ok {
 					if evt, ok := data.(ConfigChange); ok {
 						fn(evt)
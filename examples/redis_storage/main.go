@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	agent "github.com/b-open-io/agent-master-engine"
 	redisStorage "github.com/b-open-io/agent-master-engine/storage/redis"
@@ -105,6 +106,34 @@ func main() {
 	}
 
 	fmt.Printf("\n✅ Second engine instance found %d server(s) in Redis\n", len(servers2))
+
+	// Engines reload the config when another instance saves it
+	reloaded := make(chan struct{}, 1)
+	engine2.OnConfigChange(func(change agent.ConfigChange) {
+		if change.Type == "config-reloaded" {
+			reloaded <- struct{}{}
+		}
+	})
+
+	otherInstance, err := agent.NewEngine(
+		agent.WithStorage(redisStorage.New(redis.NewClient(&redis.Options{Addr: "localhost:6379"}), "agent-master")),
+	)
+	if err != nil {
+		log.Fatal("Failed to create third engine:", err)
+	}
+	if err := otherInstance.LoadConfig(""); err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	if err := otherInstance.UpdateServer("github-server", githubServer); err != nil {
+		log.Fatal("Failed to update server from another instance:", err)
+	}
+
+	select {
+	case <-reloaded:
+		fmt.Println("🔔 Second engine reloaded the config saved by another instance")
+	case <-time.After(2 * time.Second):
+		fmt.Println("⚠️  Second engine didn't see the change")
+	}
 	
 	fmt.Println("\n🎉 Redis storage is working! Your MCP server configurations are now:")
 	fmt.Println("   - Persisted across application restarts")
@@ -0,0 +1,402 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the error injected by a Fault without an Err of its own
+var ErrInjected = errors.New("injected fault")
+
+// Fault operations matched by Fault.Op. Storage faults use FaultRead,
+// FaultWrite, FaultDelete, FaultList and FaultWatch; destination faults use
+// FaultRead, FaultWrite, FaultTransform and FaultBackup.
+const (
+	FaultRead      = "read"
+	FaultWrite     = "write"
+	FaultDelete    = "delete"
+	FaultList      = "list"
+	FaultWatch     = "watch"
+	FaultTransform = "transform"
+	FaultBackup    = "backup"
+)
+
+// Fault describes a failure to inject into matching operations. A fault
+// with no Err, Partial or Corrupt only adds Latency.
+type Fault struct {
+	Op  string // Operation to affect, "" for all
+	Key string // Storage key prefix or destination ID to affect, "" for all
+
+	// Schedule: skip the first After matching calls, then affect every
+	// Every-th call (1 if 0), Times times in all (unlimited if 0)
+	After int
+	Every int
+	Times int
+
+	Latency time.Duration // Delay before the operation runs
+	Err     error         // Fail the operation with Err
+	Partial float64       // Writes: store this fraction of the data, then fail
+	Corrupt bool          // Reads: return damaged data
+}
+
+// InjectedFault records a fault injected into an operation
+type InjectedFault struct {
+	Op    string
+	Key   string
+	Fault Fault
+	Time  time.Time
+}
+
+// FaultInjector decides which operations fail. FaultyStorage and
+// FaultyDestination embed one; share an injector to schedule faults across
+// several wrappers.
+type FaultInjector struct {
+	mu       sync.Mutex
+	faults   []*scheduledFault
+	injected []InjectedFault
+	calls    map[string]int
+}
+
+type scheduledFault struct {
+	Fault
+	matched int
+	fired   int
+}
+
+// NewFaultInjector returns an injector with no faults
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{calls: make(map[string]int)}
+}
+
+// Inject adds a fault
+func (fi *FaultInjector) Inject(fault Fault) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.faults = append(fi.faults, &scheduledFault{Fault: fault})
+}
+
+// FailNext makes the next call of op on key fail with err, or ErrInjected
+func (fi *FaultInjector) FailNext(op, key string, err error) {
+	if err == nil {
+		err = ErrInjected
+	}
+	fi.Inject(Fault{Op: op, Key: key, Err: err, Times: 1})
+}
+
+// Reset removes all faults and forgets calls and injected faults
+func (fi *FaultInjector) Reset() {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.faults = nil
+	fi.injected = nil
+	fi.calls = make(map[string]int)
+}
+
+// Calls returns how often op was called, faulty or not
+func (fi *FaultInjector) Calls(op string) int {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.calls[op]
+}
+
+// Injected returns the faults injected so far, oldest first
+func (fi *FaultInjector) Injected() []InjectedFault {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return append([]InjectedFault(nil), fi.injected...)
+}
+
+// next returns the fault to inject into a call of op on key, if any. Each
+// call advances the schedule of every matching fault; the first one due
+// is injected.
+func (fi *FaultInjector) next(op, key string, prefix bool) (Fault, bool) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+
+	fi.calls[op]++
+	var due *scheduledFault
+	for _, fault := range fi.faults {
+		if fault.Op != "" && fault.Op != op {
+			continue
+		}
+		if prefix && !strings.HasPrefix(key, fault.Key) || !prefix && fault.Key != "" && fault.Key != key {
+			continue
+		}
+		if fault.Times > 0 && fault.fired >= fault.Times {
+			continue
+		}
+
+		fault.matched++
+		every := fault.Every
+		if every < 1 {
+			every = 1
+		}
+		if fault.matched <= fault.After || (fault.matched-fault.After-1)%every != 0 {
+			continue
+		}
+		if due == nil {
+			due = fault
+		}
+	}
+	if due == nil {
+		return Fault{}, false
+	}
+
+	due.fired++
+	fi.injected = append(fi.injected, InjectedFault{Op: op, Key: key, Fault: due.Fault, Time: time.Now()})
+	return due.Fault, true
+}
+
+// apply waits out the fault's latency and returns the error the operation
+// fails with, ignoring Partial and Corrupt
+func (f Fault) apply(op, key string) error {
+	if f.Latency > 0 {
+		time.Sleep(f.Latency)
+	}
+	if f.Err != nil {
+		return fmt.Errorf("%s %s: %w", op, key, f.Err)
+	}
+	if f.Partial > 0 {
+		return fmt.Errorf("%s %s: partial write: %w", op, key, ErrInjected)
+	}
+	return nil
+}
+
+// failing reports whether the fault fails the operation outright
+func (f Fault) failing() bool {
+	return f.Err != nil && f.Partial <= 0
+}
+
+// partial returns the prefix of data a partial write stores
+func (f Fault) partial(data []byte) []byte {
+	n := int(float64(len(data)) * f.Partial)
+	if n > len(data) {
+		n = len(data)
+	}
+	return data[:n]
+}
+
+// corrupt returns a damaged copy of data: the first half, with its first
+// byte flipped
+func corrupt(data []byte) []byte {
+	damaged := make([]byte, len(data)/2)
+	copy(damaged, data)
+	if len(damaged) == 0 {
+		return []byte{0xff}
+	}
+	damaged[0] ^= 0xff
+	return damaged
+}
+
+// FaultyStorage wraps a storage and injects the faults of its
+// FaultInjector, matching Fault.Key as a key prefix. It is meant for tests
+// of code that has to survive a misbehaving backend.
+type FaultyStorage struct {
+	*FaultInjector
+	backend Storage
+	writeMu sync.Mutex // Serializes WriteIfRevision without a revisioned backend
+}
+
+// NewFaultyStorage wraps backend with no faults injected yet
+func NewFaultyStorage(backend Storage) *FaultyStorage {
+	return &FaultyStorage{FaultInjector: NewFaultInjector(), backend: backend}
+}
+
+// Backend returns the wrapped storage
+func (fs *FaultyStorage) Backend() Storage {
+	return fs.backend
+}
+
+// Read reads data from storage, unless a fault fails or corrupts it
+func (fs *FaultyStorage) Read(key string) ([]byte, error) {
+	fault, ok := fs.next(FaultRead, key, true)
+	if ok {
+		if err := fault.apply(FaultRead, key); err != nil {
+			return nil, err
+		}
+	}
+	data, err := fs.backend.Read(key)
+	if err != nil || !ok || !fault.Corrupt {
+		return data, err
+	}
+	return corrupt(data), nil
+}
+
+// Write writes data to storage, unless a fault fails it or stores only
+// part of it
+func (fs *FaultyStorage) Write(key string, data []byte) error {
+	fault, ok := fs.next(FaultWrite, key, true)
+	if !ok {
+		return fs.backend.Write(key, data)
+	}
+	err := fault.apply(FaultWrite, key)
+	if fault.failing() {
+		return err
+	}
+	if fault.Partial > 0 {
+		if writeErr := fs.backend.Write(key, fault.partial(data)); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	return fs.backend.Write(key, data)
+}
+
+// ReadRevision reads a key with its revision, with the faults of Read
+func (fs *FaultyStorage) ReadRevision(key string) ([]byte, string, error) {
+	revisioned, ok := fs.backend.(RevisionedStorage)
+	if !ok {
+		data, err := fs.Read(key)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, ContentRevision(data), nil
+	}
+
+	fault, injected := fs.next(FaultRead, key, true)
+	if injected {
+		if err := fault.apply(FaultRead, key); err != nil {
+			return nil, "", err
+		}
+	}
+	data, revision, err := revisioned.ReadRevision(key)
+	if err != nil || !injected || !fault.Corrupt {
+		return data, revision, err
+	}
+	return corrupt(data), revision, nil
+}
+
+// WriteIfRevision writes a key if it is still at revision, with the faults
+// of Write
+func (fs *FaultyStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	fault, injected := fs.next(FaultWrite, key, true)
+	if injected {
+		err := fault.apply(FaultWrite, key)
+		if fault.failing() {
+			return "", err
+		}
+		if fault.Partial > 0 {
+			// A torn write ignores the revision, like a crash mid-write
+			if writeErr := fs.backend.Write(key, fault.partial(data)); writeErr != nil {
+				return "", writeErr
+			}
+			return "", err
+		}
+	}
+
+	if revisioned, ok := fs.backend.(RevisionedStorage); ok {
+		return revisioned.WriteIfRevision(key, data, revision)
+	}
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+	current, err := fs.backend.Read(key)
+	if err := checkRevision(key, current, err == nil, revision); err != nil {
+		return "", err
+	}
+	if err := fs.backend.Write(key, data); err != nil {
+		return "", err
+	}
+	return ContentRevision(data), nil
+}
+
+// Delete removes data from storage, unless a fault fails it
+func (fs *FaultyStorage) Delete(key string) error {
+	if fault, ok := fs.next(FaultDelete, key, true); ok {
+		if err := fault.apply(FaultDelete, key); err != nil {
+			return err
+		}
+	}
+	return fs.backend.Delete(key)
+}
+
+// List lists keys with given prefix, unless a fault fails it
+func (fs *FaultyStorage) List(prefix string) ([]string, error) {
+	if fault, ok := fs.next(FaultList, prefix, true); ok {
+		if err := fault.apply(FaultList, prefix); err != nil {
+			return nil, err
+		}
+	}
+	return fs.backend.List(prefix)
+}
+
+// Watch watches for changes to a key, unless a fault fails it
+func (fs *FaultyStorage) Watch(key string, handler func([]byte)) (func(), error) {
+	if fault, ok := fs.next(FaultWatch, key, true); ok {
+		if err := fault.apply(FaultWatch, key); err != nil {
+			return nil, err
+		}
+	}
+	return fs.backend.Watch(key, handler)
+}
+
+// FaultyDestination wraps a destination and injects the faults of its
+// FaultInjector, matching Fault.Key against the destination ID. Optional
+// interfaces of the wrapped destination, such as ChangeWriter, are not
+// passed through.
+type FaultyDestination struct {
+	*FaultInjector
+	Destination
+}
+
+// NewFaultyDestination wraps dest with no faults injected yet
+func NewFaultyDestination(dest Destination) *FaultyDestination {
+	return &FaultyDestination{FaultInjector: NewFaultInjector(), Destination: dest}
+}
+
+// Transform transforms the config, unless a fault fails it
+func (fd *FaultyDestination) Transform(config *Config) (interface{}, error) {
+	if fault, ok := fd.next(FaultTransform, fd.GetID(), false); ok {
+		if err := fault.apply(FaultTransform, fd.GetID()); err != nil {
+			return nil, err
+		}
+	}
+	return fd.Destination.Transform(config)
+}
+
+// Read reads the destination, unless a fault fails or corrupts it
+func (fd *FaultyDestination) Read() ([]byte, error) {
+	fault, ok := fd.next(FaultRead, fd.GetID(), false)
+	if ok {
+		if err := fault.apply(FaultRead, fd.GetID()); err != nil {
+			return nil, err
+		}
+	}
+	data, err := fd.Destination.Read()
+	if err != nil || !ok || !fault.Corrupt {
+		return data, err
+	}
+	return corrupt(data), nil
+}
+
+// Write writes the destination, unless a fault fails it or writes only
+// part of the data
+func (fd *FaultyDestination) Write(data []byte) error {
+	fault, ok := fd.next(FaultWrite, fd.GetID(), false)
+	if !ok {
+		return fd.Destination.Write(data)
+	}
+	err := fault.apply(FaultWrite, fd.GetID())
+	if fault.failing() {
+		return err
+	}
+	if fault.Partial > 0 {
+		if writeErr := fd.Destination.Write(fault.partial(data)); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	return fd.Destination.Write(data)
+}
+
+// Backup backs up the destination, unless a fault fails it
+func (fd *FaultyDestination) Backup() (string, error) {
+	if fault, ok := fd.next(FaultBackup, fd.GetID(), false); ok {
+		if err := fault.apply(FaultBackup, fd.GetID()); err != nil {
+			return "", err
+		}
+	}
+	return fd.Destination.Backup()
+}
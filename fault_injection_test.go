@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFaultSchedule(t *testing.T) {
+	storage := NewFaultyStorage(NewMemoryStorage())
+	storage.Inject(Fault{Op: FaultWrite, Key: "servers:", Err: ErrInjected, After: 1, Every: 2, Times: 2})
+
+	var failed []int
+	for i := 0; i < 8; i++ {
+		if err := storage.Write("servers:a", []byte(`{}`)); errors.Is(err, ErrInjected) {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) != 2 || failed[0] != 1 || failed[1] != 3 {
+		t.Errorf("Expected writes 1 and 3 to fail, got %v", failed)
+	}
+	if err := storage.Write("config", []byte(`{}`)); err != nil {
+		t.Errorf("Expected other keys to be unaffected: %v", err)
+	}
+	if storage.Calls(FaultWrite) != 9 || len(storage.Injected()) != 2 {
+		t.Errorf("Expected 9 calls and 2 injected faults, got %d and %d", storage.Calls(FaultWrite), len(storage.Injected()))
+	}
+
+	// Partial writes store a prefix, corrupted reads damage the value
+	storage.Inject(Fault{Op: FaultWrite, Key: "torn", Partial: 0.5, Times: 1})
+	if err := storage.Write("torn", []byte(`{"a":12}`)); !errors.Is(err, ErrInjected) {
+		t.Errorf("Expected a partial write error, got %v", err)
+	}
+	if data, _ := storage.Backend().Read("torn"); string(data) != `{"a"` {
+		t.Errorf("Expected half the data to be stored, got %s", data)
+	}
+	storage.Inject(Fault{Op: FaultRead, Key: "config", Corrupt: true, Times: 1})
+	if data, err := storage.Read("config"); err != nil || string(data) == `{}` {
+		t.Errorf("Expected a corrupted read, got %s, %v", data, err)
+	}
+	if data, _ := storage.Read("config"); string(data) != `{}` {
+		t.Errorf("Expected the next read to be intact, got %s", data)
+	}
+
+	// Latency delays the operation without failing it
+	storage.Reset()
+	storage.Inject(Fault{Op: FaultRead, Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := storage.Read("config"); err != nil || time.Since(start) < 50*time.Millisecond {
+		t.Errorf("Expected a slow read, took %s: %v", time.Since(start), err)
+	}
+}
+
+func TestBackupsUnderFaults(t *testing.T) {
+	storage := NewFaultyStorage(NewMemoryStorage())
+	engine, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed metadata write leaves no orphaned backup behind
+	storage.FailNext(FaultWrite, "backup-meta:", nil)
+	if _, err := engine.CreateBackup("doomed"); err == nil {
+		t.Fatal("Expected CreateBackup to fail")
+	}
+	if keys, _ := storage.List("backups/"); len(keys) != 0 {
+		t.Errorf("Expected the backup data to be cleaned up, got %v", keys)
+	}
+
+	backup, err := engine.CreateBackup("good")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A corrupted backup is refused
+	storage.Inject(Fault{Op: FaultRead, Key: "backups/", Corrupt: true, Times: 1})
+	if err := engine.RestoreBackup(backup.ID); err == nil {
+		t.Error("Expected restoring a corrupted backup to fail")
+	}
+
+	// A restore that can't be saved keeps the current config
+	storage.FailNext(FaultWrite, Keys.Config(), nil)
+	if err := engine.RestoreBackup(backup.ID); err == nil {
+		t.Error("Expected RestoreBackup to fail when the config can't be saved")
+	}
+	if _, err := engine.GetServer("b"); err != nil {
+		t.Errorf("Expected the current config to be kept: %v", err)
+	}
+
+	if err := engine.RestoreBackup(backup.ID); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if _, err := engine.GetServer("b"); err == nil {
+		t.Error("Expected the backup to be restored")
+	}
+}
+
+func TestSyncUnderFaults(t *testing.T) {
+	dir := t.TempDir()
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	healthy := NewFileDestination("healthy", filepath.Join(dir, "healthy.json"), ExportFormatJSON)
+	flaky := NewFaultyDestination(NewFileDestination("flaky", filepath.Join(dir, "flaky.json"), ExportFormatJSON))
+	flaky.FailNext(FaultWrite, "", nil)
+
+	// One failing destination doesn't stop the others
+	result, err := engine.SyncToMultiple(context.Background(), []Destination{healthy, flaky}, SyncOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SuccessCount != 1 || result.FailureCount != 1 {
+		t.Errorf("Expected 1 success and 1 failure, got %d and %d", result.SuccessCount, result.FailureCount)
+	}
+	for _, r := range result.Results {
+		if r.Destination == "flaky" && (r.Success || len(r.Errors) == 0) {
+			t.Errorf("Expected the flaky destination to report its error, got %+v", r)
+		}
+	}
+
+	// The next sync goes through
+	result, err = engine.SyncToMultiple(context.Background(), []Destination{healthy, flaky}, SyncOptions{})
+	if err != nil || result.FailureCount != 0 {
+		t.Errorf("Expected the retry to succeed, got %+v, %v", result, err)
+	}
+
+	// A torn write is reported, not taken for success
+	flaky.Inject(Fault{Op: FaultWrite, Partial: 0.5, Times: 1})
+	if result, err := engine.SyncTo(context.Background(), flaky, SyncOptions{}); err == nil && result.Success {
+		t.Error("Expected a partial write to fail the sync")
+	}
+}
+
+func TestAutoSyncUnderFaults(t *testing.T) {
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := NewFaultyDestination(NewFileDestination("flaky", filepath.Join(t.TempDir(), "flaky.json"), ExportFormatJSON))
+	if err := engine.RegisterDestination("flaky", dest); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.StartAutoSync(AutoSyncConfig{
+		Enabled:         true,
+		WatchInterval:   50 * time.Millisecond,
+		DebounceDelay:   50 * time.Millisecond,
+		TargetWhitelist: []string{"flaky"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.StopAutoSync()
+
+	waitForStatus := func(check func(DestinationSyncStatus) bool) DestinationSyncStatus {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			status, _ := engine.GetAutoSyncStatus()
+			if check(status.Destinations["flaky"]) {
+				return status.Destinations["flaky"]
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for auto-sync, status %+v", status.Destinations["flaky"])
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// Replacing the config triggers a sync
+	setServers := func(names ...string) {
+		t.Helper()
+		config := &Config{Version: DefaultConfigVersion, Servers: make(map[string]ServerWithMetadata)}
+		for _, name := range names {
+			config.Servers[name] = ServerWithMetadata{
+				ServerConfig: ServerConfig{Transport: "stdio", Command: name},
+				Internal:     InternalMetadata{Enabled: true},
+			}
+		}
+		if err := engine.SetConfig(config); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest.FailNext(FaultWrite, "", errors.New("disk full"))
+	setServers("a")
+	status := waitForStatus(func(s DestinationSyncStatus) bool { return s.FailureCount > 0 })
+	if !strings.Contains(status.LastError, "disk full") {
+		t.Errorf("Expected the injected error to be recorded, got %q", status.LastError)
+	}
+
+	// The next change syncs again and clears the error
+	setServers("a", "b")
+	status = waitForStatus(func(s DestinationSyncStatus) bool { return !s.LastSuccess.IsZero() })
+	if status.LastError != "" {
+		t.Errorf("Expected the error to be cleared, got %q", status.LastError)
+	}
+}
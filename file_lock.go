@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLockTimeout is how long writes wait for another process to release
+// a file lock before giving up
+const DefaultLockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a held lock is retried
+const lockRetryInterval = 25 * time.Millisecond
+
+// LockError is returned when a file lock is still held by another process
+// after the wait timeout. It is recoverable; the write can be retried later.
+type LockError struct {
+	Path    string
+	Holder  string // PID of the holding process, if known
+	Timeout time.Duration
+}
+
+func (e *LockError) Error() string {
+	holder := "another process"
+	if e.Holder != "" {
+		holder = "process " + e.Holder
+	}
+	return fmt.Sprintf("%s is locked by %s (waited %s)", e.Path, holder, e.Timeout)
+}
+
+func (e *LockError) recoverable() bool {
+	return true
+}
+
+// FileLock is an advisory lock on a file, shared with other processes
+// using the same lock path
+type FileLock struct {
+	path string
+	file *os.File
+}
+
+// LockPath returns the lock file used for path: a hidden file next to the
+// real file path points to
+func LockPath(path string) string {
+	if target, err := resolveSymlinks(path); err == nil {
+		path = target
+	}
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+}
+
+// AcquireFileLock takes an exclusive lock for path, waiting up to timeout
+// for another process to release it. A zero timeout tries once.
+func AcquireFileLock(path string, timeout time.Duration) (*FileLock, error) {
+	lockPath := LockPath(path)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to acquire lock: %w", err)
+		}
+		if locked {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			file.Close()
+			return nil, &LockError{Path: path, Holder: readLockHolder(lockPath), Timeout: timeout}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// Record our PID for error messages in other processes
+	file.Truncate(0)
+	file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+
+	return &FileLock{path: lockPath, file: file}, nil
+}
+
+// Unlock releases the lock. The lock file is kept, since removing it would
+// let another process lock a file that is no longer the shared one.
+func (l *FileLock) Unlock() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	unlockFile(l.file)
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// readLockHolder returns the PID written to a lock file, if any
+func readLockHolder(lockPath string) string {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// isSidecarFile reports whether name is a lock or temp file written next to a
+// config file
+func isSidecarFile(name string) bool {
+	return strings.HasPrefix(name, ".") &&
+		(strings.HasSuffix(name, ".lock") || strings.Contains(name, ".tmp-"))
+}
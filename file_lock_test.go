@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileLockingAcrossEngines(t *testing.T) {
+	tmpDir := t.TempDir()
+	storageDir := filepath.Join(tmpDir, "storage")
+
+	// Each engine has its own FileStorage, like two processes would
+	storageA, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageB, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engineA, err := NewEngine(WithStorage(storageA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engineB, err := NewEngine(WithStorage(storageB), WithLockTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent read-modify-write cycles don't lose updates
+	increment := func(data []byte) ([]byte, error) {
+		n, _ := strconv.Atoi(string(data))
+		return []byte(strconv.Itoa(n + 1)), nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		for _, storage := range []*FileStorage{storageA, storageB} {
+			wg.Add(1)
+			go func(storage *FileStorage) {
+				defer wg.Done()
+				if err := storage.Update("counter", increment); err != nil {
+					t.Errorf("Update failed: %v", err)
+				}
+			}(storage)
+		}
+	}
+	wg.Wait()
+
+	data, err := storageA.Read("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "40" {
+		t.Errorf("Expected counter 40, got %s", data)
+	}
+
+	// Lock files aren't listed as keys
+	keys, err := storageB.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "counter" {
+		t.Errorf("Expected only the counter key, got %v", keys)
+	}
+
+	// Both engines sync to the same destination at once
+	if err := engineA.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	// B hasn't seen A's config yet, so it reloads before changing it
+	if err := engineB.LoadConfig(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := engineB.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	destPath := filepath.Join(tmpDir, "mcp.json")
+	dest := NewFileDestination("shared", destPath, ExportFormatJSON)
+
+	for _, engine := range []Engine{engineA, engineB} {
+		wg.Add(1)
+		go func(engine Engine) {
+			defer wg.Done()
+			if _, err := engine.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+				t.Errorf("SyncTo failed: %v", err)
+			}
+		}(engine)
+	}
+	wg.Wait()
+
+	written, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(written) {
+		t.Errorf("Expected valid JSON, got %s", written)
+	}
+
+	// A lock held elsewhere fails the sync after the timeout
+	lock, err := AcquireFileLock(destPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lock.Unlock()
+
+	result, err := engineB.SyncTo(context.Background(), dest, SyncOptions{})
+	var lockErr *LockError
+	if !errors.As(err, &lockErr) {
+		t.Fatalf("Expected a LockError, got %v", err)
+	}
+	if lockErr.Holder != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Expected the holder's PID, got %q", lockErr.Holder)
+	}
+	if len(result.Errors) != 1 || !result.Errors[0].Recoverable {
+		t.Errorf("Expected one recoverable error, got %+v", result.Errors)
+	}
+
+	// Storage writes wait for the key's lock the same way
+	keyLock, err := AcquireFileLock(filepath.Join(storageDir, "counter.json"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keyLock.Unlock()
+
+	storageB.SetLockTimeout(0)
+	if err := storageB.Write("counter", []byte("0")); !errors.As(err, &lockErr) {
+		t.Errorf("Expected a LockError, got %v", err)
+	}
+}
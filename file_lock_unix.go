@@ -0,0 +1,23 @@
+//go:build !windows
+// +build !windows
+
+package engine
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes a non-blocking exclusive flock on file
+func tryLockFile(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the flock on file
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
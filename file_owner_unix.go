@@ -0,0 +1,16 @@
+//go:build !windows
+// +build !windows
+
+package engine
+
+import (
+	"os"
+	"syscall"
+)
+
+// preserveOwner gives f the owner and group of existing, ignoring failures
+func preserveOwner(f *os.File, existing os.FileInfo) {
+	if stat, ok := existing.Sys().(*syscall.Stat_t); ok {
+		f.Chown(int(stat.Uid), int(stat.Gid))
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+// +build windows
+
+package engine
+
+import "os"
+
+// preserveOwner is a no-op; Windows files inherit ACLs from their directory
+func preserveOwner(f *os.File, existing os.FileInfo) {}
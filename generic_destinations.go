@@ -61,19 +61,24 @@ func (f *FileDestination) Read() ([]byte, error) {
 func (f *FileDestination) Write(data []byte) error {
 	path := expandPath(f.Path)
 
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
+	// Write atomically, keeping symlinks and permissions
+	return WriteFileAtomic(path, data, DefaultFileMode)
+}
 
-	// Write atomically
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
+// WithPath returns a copy of the destination writing to path
+func (f *FileDestination) WithPath(path string) Destination {
+	relocated := *f
+	relocated.Path = path
+	return &relocated
+}
 
-	return os.Rename(tmpPath, path)
+// Lock takes the file's advisory lock
+func (f *FileDestination) Lock(timeout time.Duration) (func() error, error) {
+	lock, err := AcquireFileLock(expandPath(f.Path), timeout)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Unlock, nil
 }
 
 // Exists checks if the destination exists
@@ -114,7 +119,7 @@ func (f *FileDestination) Backup() (string, error) {
 		filepath.Base(path), timestamp))
 
 	// Write backup
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(backupPath, data, DefaultFileMode); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,256 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitDestination writes the transformed config to a file inside a git working
+// tree and commits every sync, optionally pushing to a remote. Backups are the
+// commit that last changed the file, so restoring is a checkout of it.
+type GitDestination struct {
+	ID       string
+	RepoPath string      // Working tree of an existing repository
+	File     string      // Path of the config file inside the repository
+	Inner    Destination // Provides the file format when set
+
+	Remote string // Pushed to after each commit when set
+	Branch string // Remote branch, defaults to the current branch
+
+	// Commit identity; git's own configuration is used when empty
+	AuthorName  string
+	AuthorEmail string
+}
+
+// NewGitDestination creates a destination committing to repoPath/file.
+// The config format comes from inner, or mcpServers JSON when inner is nil.
+// An inner destination must implement RelocatableDestination so its file can
+// be written inside the repository.
+func NewGitDestination(id, repoPath, file string, inner Destination) *GitDestination {
+	return &GitDestination{
+		ID:       id,
+		RepoPath: repoPath,
+		File:     file,
+		Inner:    inner,
+	}
+}
+
+// GitError is returned when a git command fails. Failed pushes are
+// recoverable since the commit is already made locally.
+type GitError struct {
+	Args   []string
+	Output string
+	Err    error
+}
+
+func (e *GitError) Error() string {
+	if e.Output != "" {
+		return fmt.Sprintf("git %s: %v: %s", strings.Join(e.Args, " "), e.Err, e.Output)
+	}
+	return fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+}
+
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *GitError) recoverable() bool {
+	return len(e.Args) > 0 && e.Args[0] == "push"
+}
+
+// GetID returns the destination identifier
+func (g *GitDestination) GetID() string {
+	return g.ID
+}
+
+// GetDescription returns a human-readable description
+func (g *GitDestination) GetDescription() string {
+	return fmt.Sprintf("Git repository %s (%s)", g.RepoPath, g.File)
+}
+
+// Transform uses the inner destination's format, or mcpServers
+func (g *GitDestination) Transform(config *Config) (interface{}, error) {
+	if g.Inner != nil {
+		return g.Inner.Transform(config)
+	}
+
+	servers := make(map[string]ServerConfig)
+	for name, server := range config.Servers {
+		if server.Internal.Enabled {
+			servers[name] = server.ServerConfig
+		}
+	}
+	return map[string]interface{}{
+		"mcpServers": servers,
+	}, nil
+}
+
+// Read reads the file from the working tree
+func (g *GitDestination) Read() ([]byte, error) {
+	return os.ReadFile(g.path())
+}
+
+// ParseConfig parses the file with the inner destination's parser
+func (g *GitDestination) ParseConfig(data []byte) (*Config, error) {
+	if g.Inner != nil {
+		return parseDestinationConfig(g.Inner, data)
+	}
+	return ParseMCPConfigWithOptions(data, false)
+}
+
+// Write writes and commits the file
+func (g *GitDestination) Write(data []byte) error {
+	return g.WriteChanges(data, nil)
+}
+
+// WriteChanges writes the file and commits it with a message describing the
+// changes. Nothing is committed if the file didn't change. With an inner
+// destination, the inner destination serializes and merges the file.
+func (g *GitDestination) WriteChanges(data []byte, changes []Change) error {
+	if g.Inner != nil {
+		inner, ok := g.Inner.(RelocatableDestination)
+		if !ok {
+			return fmt.Errorf("destination %s can't write to %s", g.Inner.GetID(), g.File)
+		}
+		if err := inner.WithPath(g.path()).Write(data); err != nil {
+			return err
+		}
+	} else if err := WriteFileAtomic(g.path(), data, DefaultFileMode); err != nil {
+		return err
+	}
+
+	return g.commit(g.commitMessage(changes))
+}
+
+// Lock takes an advisory lock of the file kept in the git directory, so
+// the working tree has no untracked lock file
+func (g *GitDestination) Lock(timeout time.Duration) (func() error, error) {
+	path, err := g.git("rev-parse", "--git-path", filepath.Join("agent-master", g.File))
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(expandPath(g.RepoPath), path)
+	}
+
+	lock, err := AcquireFileLock(path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Unlock, nil
+}
+
+// Exists checks if the file exists in the working tree
+func (g *GitDestination) Exists() bool {
+	_, err := os.Stat(g.path())
+	return err == nil
+}
+
+// SupportsBackup returns true; backups are commits
+func (g *GitDestination) SupportsBackup() bool {
+	return true
+}
+
+// Backup returns the hash of the commit that last changed the file, or an
+// empty string if the file was never committed
+func (g *GitDestination) Backup() (string, error) {
+	out, err := g.git("log", "-1", "--format=%H", "--", g.File)
+	if err != nil {
+		// A repository without commits has no backup yet
+		if _, headErr := g.git("rev-parse", "--verify", "-q", "HEAD"); headErr != nil {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// Restore checks the file out from a backup commit and commits the result
+func (g *GitDestination) Restore(commit string) error {
+	if _, err := g.git("checkout", commit, "--", g.File); err != nil {
+		return err
+	}
+	short := commit
+	if len(short) > 12 {
+		short = short[:12]
+	}
+	return g.commit(fmt.Sprintf("Restore %s to %s", g.File, short))
+}
+
+func (g *GitDestination) path() string {
+	return filepath.Join(expandPath(g.RepoPath), g.File)
+}
+
+// commit stages and commits the file if it changed, then pushes
+func (g *GitDestination) commit(message string) error {
+	if _, err := g.git("add", "--", g.File); err != nil {
+		return err
+	}
+	if _, err := g.git("diff", "--cached", "--quiet", "--", g.File); err == nil {
+		return nil // Unchanged
+	}
+	if _, err := g.git("commit", "-q", "-m", message, "--", g.File); err != nil {
+		return err
+	}
+
+	if g.Remote == "" {
+		return nil
+	}
+	ref := "HEAD"
+	if g.Branch != "" {
+		ref = "HEAD:" + g.Branch
+	}
+	_, err := g.git("push", "-q", g.Remote, ref)
+	return err
+}
+
+// commitMessage summarizes the changes of a sync
+func (g *GitDestination) commitMessage(changes []Change) string {
+	subject := fmt.Sprintf("Sync %s", g.ID)
+	if len(changes) == 0 {
+		return subject
+	}
+
+	counts := make(map[string]int)
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		counts[change.Type]++
+		lines = append(lines, fmt.Sprintf("- %s %s", change.Type, change.Server))
+	}
+	sort.Strings(lines)
+
+	var summary []string
+	for _, kind := range []string{ChangeTypeAdd, ChangeTypeUpdate, ChangeTypeDelete} {
+		if counts[kind] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+
+	return fmt.Sprintf("%s: %s\n\n%s\n", subject, strings.Join(summary, ", "), strings.Join(lines, "\n"))
+}
+
+// git runs a git command in the repository and returns its trimmed output
+func (g *GitDestination) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", expandPath(g.RepoPath)}, args...)...)
+	cmd.Env = os.Environ()
+	if g.AuthorName != "" {
+		cmd.Env = append(cmd.Env, "GIT_AUTHOR_NAME="+g.AuthorName, "GIT_COMMITTER_NAME="+g.AuthorName)
+	}
+	if g.AuthorEmail != "" {
+		cmd.Env = append(cmd.Env, "GIT_AUTHOR_EMAIL="+g.AuthorEmail, "GIT_COMMITTER_EMAIL="+g.AuthorEmail)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", &GitError{Args: args, Output: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runGit runs a git command for test setup
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestGitDestination(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	tmpDir := t.TempDir()
+	remote := filepath.Join(tmpDir, "remote.git")
+	repo := filepath.Join(tmpDir, "dotfiles")
+	runGit(t, tmpDir, "init", "-q", "--bare", "-b", "main", remote)
+	runGit(t, tmpDir, "init", "-q", "-b", "main", repo)
+	runGit(t, repo, "remote", "add", "origin", remote)
+
+	dest := NewGitDestination("dotfiles", repo, filepath.Join("mcp", "servers.json"), nil)
+	dest.Remote = "origin"
+	dest.Branch = "main"
+	dest.AuthorName = "Test"
+	dest.AuthorEmail = "test@example.com"
+
+	if backup, err := dest.Backup(); err != nil || backup != "" {
+		t.Errorf("Expected no backup before the first commit, got %q, %v", backup, err)
+	}
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("first", ServerConfig{Transport: "stdio", Command: "first-command"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := engine.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	firstCommit := runGit(t, remote, "rev-parse", "main")
+	firstData, err := dest.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Syncing again without changes doesn't create a commit
+	if _, err := engine.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if count := runGit(t, repo, "rev-list", "--count", "HEAD"); count != "1" {
+		t.Errorf("Expected 1 commit, got %s", count)
+	}
+
+	if err := engine.AddServer("second", ServerConfig{Transport: "stdio", Command: "second-command"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := engine.SyncTo(context.Background(), dest, SyncOptions{CreateBackup: true})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if result.BackupPath != firstCommit {
+		t.Errorf("Expected backup to be the previous commit %s, got %q", firstCommit, result.BackupPath)
+	}
+
+	message := runGit(t, remote, "log", "-1", "--format=%B", "main")
+	if !strings.HasPrefix(message, "Sync dotfiles: 1 add") || !strings.Contains(message, "- add second") {
+		t.Errorf("Unexpected commit message: %q", message)
+	}
+
+	// Restoring checks out the backup and commits it
+	if err := dest.Restore(result.BackupPath); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := os.ReadFile(filepath.Join(repo, "mcp", "servers.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(restored) != string(firstData) {
+		t.Errorf("Expected restored file to match the first sync, got %s", restored)
+	}
+	if count := runGit(t, remote, "rev-list", "--count", "main"); count != "3" {
+		t.Errorf("Expected 3 commits on the remote, got %s", count)
+	}
+}
+
+func TestGitDestinationLockOutsideWorkTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q", "-b", "main")
+	dest := NewGitDestination("dotfiles", repo, filepath.Join("mcp", "servers.json"), nil)
+
+	unlock, err := dest.Lock(time.Second)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	defer unlock()
+
+	if status := runGit(t, repo, "status", "--porcelain", "--untracked-files=all"); status != "" {
+		t.Errorf("Expected a clean working tree while locked, got %q", status)
+	}
+	if _, err := dest.Lock(10 * time.Millisecond); err == nil {
+		t.Error("Expected a second lock to time out")
+	}
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -39,4 +40,11 @@ func SanitizeServerName(name string) string {
 // isNotFoundError checks if an error is a "not found" error
 func isNotFoundError(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "not found")
-}
\ No newline at end of file
+}
+
+// isRecoverable reports whether a destination error is worth retrying.
+// Destination errors opt in by implementing recoverable().
+func isRecoverable(err error) bool {
+	var re interface{ recoverable() bool }
+	return errors.As(err, &re) && re.recoverable()
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPTimeout bounds a single request made by an HTTPDestination
+const DefaultHTTPTimeout = 30 * time.Second
+
+// HTTPDestination pushes the transformed config to a remote HTTP endpoint.
+// GET returns the current state and PUT (or POST) replaces it. When the
+// server sends an ETag, writes carry it in If-Match so a concurrent change
+// fails with 412 Precondition Failed instead of being overwritten.
+type HTTPDestination struct {
+	ID          string
+	URL         string
+	Method      string            // Write method, PUT by default
+	Headers     map[string]string // Sent with every request
+	BearerToken string            // Sent as "Authorization: Bearer <token>"
+	Username    string            // Basic auth, used when BearerToken is empty
+	Password    string
+	Transformer ConfigTransformer
+	Client      *http.Client
+
+	mu   sync.Mutex
+	etag string // ETag of the last state read or written
+}
+
+// NewHTTPDestination creates a destination for an HTTP endpoint
+func NewHTTPDestination(id, url string) *HTTPDestination {
+	return &HTTPDestination{
+		ID:     id,
+		URL:    url,
+		Method: http.MethodPut,
+		Client: &http.Client{Timeout: DefaultHTTPTimeout},
+	}
+}
+
+// HTTPError is returned for failed requests. Server errors, rate limiting,
+// ETag conflicts and transport failures are recoverable.
+type HTTPError struct {
+	Method     string
+	URL        string
+	StatusCode int    // 0 when the request never got a response
+	Body       string // Start of the response body
+	Err        error  // Transport error
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s %s: %v", e.Method, e.URL, e.Err)
+	}
+	if e.Body != "" {
+		return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+	}
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Conflict reports whether the write was rejected because the remote state
+// changed since it was last read
+func (e *HTTPError) Conflict() bool {
+	return e.StatusCode == http.StatusPreconditionFailed
+}
+
+func (e *HTTPError) recoverable() bool {
+	return e.Err != nil ||
+		e.StatusCode >= 500 ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.Conflict()
+}
+
+// GetID returns the destination identifier
+func (h *HTTPDestination) GetID() string {
+	return h.ID
+}
+
+// GetDescription returns a human-readable description
+func (h *HTTPDestination) GetDescription() string {
+	return fmt.Sprintf("HTTP destination at %s", h.URL)
+}
+
+// Transform converts the config using the Transformer, or to mcpServers
+func (h *HTTPDestination) Transform(config *Config) (interface{}, error) {
+	if h.Transformer != nil {
+		return h.Transformer.Transform(config)
+	}
+
+	servers := make(map[string]ServerConfig)
+	for name, server := range config.Servers {
+		if server.Internal.Enabled {
+			servers[name] = server.ServerConfig
+		}
+	}
+	return map[string]interface{}{
+		"mcpServers": servers,
+	}, nil
+}
+
+// Read fetches the current state with GET and remembers its ETag
+func (h *HTTPDestination) Read() ([]byte, error) {
+	resp, body, err := h.do(http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	h.setETag(resp.Header.Get("ETag"))
+	return body, nil
+}
+
+// Write sends the config, guarded by the last seen ETag
+func (h *HTTPDestination) Write(data []byte) error {
+	resp, _, err := h.do(h.method(), data)
+	if err != nil {
+		return err
+	}
+	h.setETag(resp.Header.Get("ETag"))
+	return nil
+}
+
+// Exists reports whether the endpoint currently has state. It is false
+// when the check fails too; see CheckExists.
+func (h *HTTPDestination) Exists() bool {
+	exists, err := h.CheckExists()
+	return exists && err == nil
+}
+
+// CheckExists reports whether the endpoint currently has state. Only 404
+// Not Found and 410 Gone mean it has none; other failures are returned as
+// an *HTTPError, recoverable for server errors.
+func (h *HTTPDestination) CheckExists() (bool, error) {
+	resp, _, err := h.do(http.MethodGet, nil)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusGone) {
+		h.setETag("")
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	h.setETag(resp.Header.Get("ETag"))
+	return true, nil
+}
+
+// SupportsBackup returns false; remote services keep their own history
+func (h *HTTPDestination) SupportsBackup() bool {
+	return false
+}
+
+// Backup is not supported for HTTP destinations
+func (h *HTTPDestination) Backup() (string, error) {
+	return "", fmt.Errorf("backup not supported for HTTP destination %s", h.ID)
+}
+
+// ETag returns the ETag of the last state read or written
+func (h *HTTPDestination) ETag() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.etag
+}
+
+func (h *HTTPDestination) setETag(etag string) {
+	h.mu.Lock()
+	h.etag = etag
+	h.mu.Unlock()
+}
+
+func (h *HTTPDestination) method() string {
+	if h.Method == "" {
+		return http.MethodPut
+	}
+	return h.Method
+}
+
+// do sends a request and returns the response and body for 2xx responses
+func (h *HTTPDestination) do(method string, data []byte) (*http.Response, []byte, error) {
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, h.URL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+		if etag := h.ETag(); etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+	}
+	for key, value := range h.Headers {
+		req.Header.Set(key, value)
+	}
+	if h.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+h.BearerToken)
+	} else if h.Username != "" {
+		req.SetBasicAuth(h.Username, h.Password)
+	}
+
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, &HTTPError{Method: method, URL: h.URL, Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, &HTTPError{Method: method, URL: h.URL, Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := strings.TrimSpace(string(respBody))
+		if len(snippet) > 200 {
+			snippet = snippet[:200]
+		}
+		return nil, nil, &HTTPError{Method: method, URL: h.URL, StatusCode: resp.StatusCode, Body: snippet}
+	}
+
+	return resp, respBody, nil
+}
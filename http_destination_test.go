@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// httpTestServer is an endpoint that versions its state with ETags
+type httpTestServer struct {
+	mu       sync.Mutex
+	data     []byte
+	version  int
+	failures int // Number of requests to answer with 503
+}
+
+func (s *httpTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.failures > 0 {
+		s.failures--
+		http.Error(w, "try again later", http.StatusServiceUnavailable)
+		return
+	}
+
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+	switch r.Method {
+	case http.MethodGet:
+		if s.data == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write(s.data)
+	case http.MethodPut:
+		if match := r.Header.Get("If-Match"); match != "" && match != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.data, _ = io.ReadAll(r.Body)
+		s.version++
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, s.version))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// modify changes the state behind the destination's back
+func (s *httpTestServer) modify() {
+	s.mu.Lock()
+	s.version++
+	s.mu.Unlock()
+}
+
+func TestHTTPDestination(t *testing.T) {
+	server := &httpTestServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dest := NewHTTPDestination("remote", ts.URL)
+	dest.BearerToken = "secret"
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("test-server", ServerConfig{
+		Transport: "stdio",
+		Command:   "test-command",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if dest.Exists() {
+		t.Error("Expected no remote state yet")
+	}
+
+	result, err := engine.SyncTo(context.Background(), dest, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if !result.Success || result.ServersWritten != 1 {
+		t.Errorf("Expected one server written, got %+v", result)
+	}
+	if dest.ETag() != `"v1"` {
+		t.Errorf("Expected ETag from write, got %q", dest.ETag())
+	}
+
+	// Previews read the remote state back
+	preview, err := engine.PreviewSync(dest)
+	if err != nil {
+		t.Fatalf("PreviewSync failed: %v", err)
+	}
+	if len(preview.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", preview.Changes)
+	}
+
+	// Writing over a remote change is a recoverable conflict
+	server.modify()
+	err = dest.Write([]byte(`{"mcpServers":{}}`))
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !httpErr.Conflict() || !isRecoverable(err) {
+		t.Errorf("Expected recoverable conflict, got %v", err)
+	}
+
+	// Syncing reads the new state first, so it succeeds
+	if _, err := engine.SyncTo(context.Background(), dest, SyncOptions{}); err != nil {
+		t.Errorf("Expected sync after conflict to succeed: %v", err)
+	}
+}
+
+func TestHTTPDestinationErrors(t *testing.T) {
+	server := &httpTestServer{failures: 100}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 5xx responses are recoverable
+	dest := NewHTTPDestination("remote", ts.URL)
+	dest.BearerToken = "secret"
+	result, err := engine.SyncTo(context.Background(), dest, SyncOptions{})
+	if err == nil {
+		t.Fatal("Expected sync to fail")
+	}
+	if len(result.Errors) != 1 || !result.Errors[0].Recoverable {
+		t.Errorf("Expected one recoverable error, got %+v", result.Errors)
+	}
+
+	// Auth failures are not
+	unauthorized := NewHTTPDestination("remote", ts.URL)
+	result, err = engine.SyncTo(context.Background(), unauthorized, SyncOptions{})
+	if err == nil {
+		t.Fatal("Expected sync to fail")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Recoverable {
+		t.Errorf("Expected one unrecoverable error, got %+v", result.Errors)
+	}
+}
+
+func TestHTTPDestinationExists(t *testing.T) {
+	server := &httpTestServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dest := NewHTTPDestination("remote", ts.URL)
+	dest.BearerToken = "secret"
+
+	// 404 means there is no state yet
+	if exists, err := dest.CheckExists(); exists || err != nil {
+		t.Errorf("Expected no state for 404, got %v, %v", exists, err)
+	}
+	if err := dest.Write([]byte(`{"mcpServers":{}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := dest.CheckExists(); !exists || err != nil {
+		t.Errorf("Expected state after writing, got %v, %v", exists, err)
+	}
+
+	// Other failures are errors, recoverable for server errors
+	server.failures = 1
+	exists, err := dest.CheckExists()
+	if exists || err == nil || !isRecoverable(err) {
+		t.Errorf("Expected a recoverable error for 503, got %v, %v", exists, err)
+	}
+	unauthorized := NewHTTPDestination("remote", ts.URL)
+	if exists, err := unauthorized.CheckExists(); exists || err == nil || isRecoverable(err) {
+		t.Errorf("Expected an unrecoverable error for 401, got %v, %v", exists, err)
+	}
+
+	// A failed check stops the sync instead of overwriting the remote state
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("test-server", ServerConfig{Transport: "stdio", Command: "test-command"}); err != nil {
+		t.Fatal(err)
+	}
+	server.failures = 1
+	result, err := engine.SyncTo(context.Background(), dest, SyncOptions{})
+	if err == nil || len(result.Errors) != 1 || !result.Errors[0].Recoverable {
+		t.Errorf("Expected the sync to fail recoverably, got %+v, %v", result, err)
+	}
+	if server.version != 1 {
+		t.Errorf("Expected the remote state to be untouched, got version %d", server.version)
+	}
+	server.failures = 1
+	if _, err := engine.PreviewSync(dest); err == nil {
+		t.Error("Expected the preview to fail")
+	}
+
+	// 410 means the state is gone
+	gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer gone.Close()
+	if exists, err := NewHTTPDestination("gone", gone.URL).CheckExists(); exists || err != nil {
+		t.Errorf("Expected no state for 410, got %v, %v", exists, err)
+	}
+}
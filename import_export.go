@@ -3,15 +3,30 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 )
 
 // Import/Export functionality for engineImpl
 
-// Export exports the configuration in the specified format
+// Export exports the configuration in the specified format. With encrypted
+// storage the export is sealed with the storage's key, so tokens don't leave
+// it in plaintext; Import opens it again. Use ExportDecrypted for plaintext.
 func (e *engineImpl) Export(format ExportFormat) ([]byte, error) {
+	data, err := e.ExportDecrypted(format)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted, ok := e.getStorage().(*EncryptedStorage); ok {
+		if data, err = encrypted.sealExport(data); err != nil {
+			return nil, fmt.Errorf("failed to export: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// ExportDecrypted exports the configuration in plaintext, even with
+// encrypted storage
+func (e *engineImpl) ExportDecrypted(format ExportFormat) ([]byte, error) {
 	e.mu.RLock()
 	config := e.config
 	e.mu.RUnlock()
@@ -54,25 +69,35 @@ func (e *engineImpl) Export(format ExportFormat) ([]byte, error) {
 	}
 }
 
-// ExportToFile exports the configuration to a file
+// ExportToFile exports the configuration to a file, sealed like Export
+// with encrypted storage. Use ExportToFileDecrypted for a plaintext export.
 func (e *engineImpl) ExportToFile(path string, format ExportFormat) error {
+	return e.exportToFile(path, format, false)
+}
+
+// ExportToFileDecrypted exports the configuration to a file in plaintext,
+// even with encrypted storage
+func (e *engineImpl) ExportToFileDecrypted(path string, format ExportFormat) error {
+	return e.exportToFile(path, format, true)
+}
+
+func (e *engineImpl) exportToFile(path string, format ExportFormat, decrypted bool) error {
 	// Export to bytes first
-	data, err := e.Export(format)
+	export := e.Export
+	if decrypted {
+		export = e.ExportDecrypted
+	}
+	data, err := export(format)
 	if err != nil {
 		return fmt.Errorf("failed to export: %w", err)
 	}
+	_, sealed := e.getStorage().(*EncryptedStorage)
 
 	// Expand path
 	expandedPath := expandPath(path)
 
-	// Create directory if needed
-	dir := filepath.Dir(expandedPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Write to file
-	if err := os.WriteFile(expandedPath, data, 0644); err != nil {
+	// Write to file; plaintext exports holding tokens get SecretFileMode
+	if err := WriteFileAtomic(expandedPath, data, DefaultFileMode); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -81,7 +106,11 @@ func (e *engineImpl) ExportToFile(path string, format ExportFormat) error {
 		Type:      "config-exported",
 		Timestamp: time.Now(),
 		Source:    "export",
-		Details:   map[string]interface{}{"path": expandedPath, "format": string(format)},
+		Details: map[string]interface{}{
+			"path":      expandedPath,
+			"format":    string(format),
+			"encrypted": sealed && !decrypted,
+		},
 	})
 
 	return nil
@@ -89,8 +118,30 @@ func (e *engineImpl) ExportToFile(path string, format ExportFormat) error {
 
 // Import imports configuration from data
 func (e *engineImpl) Import(data []byte, format ImportFormat, options ImportOptions) error {
+	// Open exports sealed by ExportToFile
+	if _, ok := parseSealed(data); ok {
+		encrypted, ok := e.getStorage().(*EncryptedStorage)
+		if !ok {
+			return fmt.Errorf("failed to import: data is encrypted and the engine has no encryption key")
+		}
+		opened, err := encrypted.openExport(data)
+		if err != nil {
+			return fmt.Errorf("failed to import: %w", err)
+		}
+		data = opened
+	}
+
 	// Parse the data using our MCP parser
-	config, err := ParseMCPConfigWithOptions(data, options.SubstituteEnvVars)
+	var config *Config
+	var err error
+	switch format {
+	case ImportFormatYAML:
+		config, err = ParseMCPConfigYAML(data, options.SubstituteEnvVars)
+	case ImportFormatTOML:
+		config, err = ParseMCPConfigTOML(data, options.SubstituteEnvVars)
+	default:
+		config, err = ParseMCPConfigWithOptions(data, options.SubstituteEnvVars)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to parse config: %w", err)
 	}
@@ -99,7 +150,8 @@ func (e *engineImpl) Import(data []byte, format ImportFormat, options ImportOpti
 	defer e.mu.Unlock()
 
 	// Handle merge options
-	if options.MergeMode == "replace" {
+	options.normalize()
+	if options.MergeStrategy == "replace" {
 		// Replace all servers
 		e.config.Servers = make(map[string]ServerWithMetadata)
 	}
@@ -108,7 +160,7 @@ func (e *engineImpl) Import(data []byte, format ImportFormat, options ImportOpti
 	importedCount := 0
 	for name, server := range config.Servers {
 		// Skip if server already exists and not overwriting
-		if _, exists := e.config.Servers[name]; exists && !options.OverwriteExisting {
+		if _, exists := e.config.Servers[name]; exists && !options.Overwrite {
 			continue
 		}
 
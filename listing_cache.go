@@ -0,0 +1,92 @@
+package engine
+
+// With WithCache, server and project listings are kept at
+// Keys.ServerCache() and Keys.ProjectCache(path), stamped with the revision
+// of the config they were built from. They are then read from memory until
+// the config changes, and other engines sharing the storage reuse them.
+// Without a cache, reading them would cost a round trip that building them
+// from the loaded config doesn't, so they aren't used.
+
+// serverListing is the listing stored at Keys.ServerCache()
+type serverListing struct {
+	Revision string               `json:"revision"`
+	Servers  []serverListingEntry `json:"servers"`
+}
+
+// serverListingEntry is a listed server with the fields ListServers
+// filters on that ServerInfo doesn't carry
+type serverListingEntry struct {
+	Info   ServerInfo `json:"info"`
+	Source string     `json:"source,omitempty"`
+}
+
+// projectListing is the listing stored at Keys.ProjectCache(path)
+type projectListing struct {
+	Revision string      `json:"revision"`
+	Project  ProjectInfo `json:"project"`
+}
+
+// listingRevision returns the config revision listings are stamped with,
+// or "" when listings aren't cached: the storage has no cache, the config
+// comes from a file, or the loaded config has unsaved changes. Caller must
+// hold e.mu.
+func (e *engineImpl) listingRevision() string {
+	if e.configPath != "" || e.unsavedConfig.Load() || !isCached(e.getStorage()) {
+		return ""
+	}
+	return e.configRevision
+}
+
+// isCached reports whether storage is a CachingStorage, possibly below
+// encryption
+func isCached(storage Storage) bool {
+	if encrypted, ok := storage.(*EncryptedStorage); ok {
+		storage = encrypted.Backend()
+	}
+	_, ok := storage.(*CachingStorage)
+	return ok
+}
+
+// loadServerListing returns the stored server listing if it was built from
+// the loaded config. Caller must hold e.mu.
+func (e *engineImpl) loadServerListing() ([]serverListingEntry, bool) {
+	revision := e.listingRevision()
+	if revision == "" {
+		return nil, false
+	}
+	var listing serverListing
+	if err := LoadJSON(e.getStorage(), Keys.ServerCache(), &listing); err != nil || listing.Revision != revision {
+		return nil, false
+	}
+	return listing.Servers, true
+}
+
+// saveServerListing stores the server listing for the loaded config.
+// Listings can be rebuilt, so failures are ignored. Caller must hold e.mu.
+func (e *engineImpl) saveServerListing(servers []serverListingEntry) {
+	if revision := e.listingRevision(); revision != "" {
+		SaveJSON(e.getStorage(), Keys.ServerCache(), serverListing{Revision: revision, Servers: servers})
+	}
+}
+
+// loadProjectListing returns the stored listing of the project at path if
+// it was built from the loaded config. Caller must hold e.mu.
+func (e *engineImpl) loadProjectListing(path string) (*ProjectInfo, bool) {
+	revision := e.listingRevision()
+	if revision == "" {
+		return nil, false
+	}
+	var listing projectListing
+	if err := LoadJSON(e.getStorage(), Keys.ProjectCache(path), &listing); err != nil || listing.Revision != revision {
+		return nil, false
+	}
+	return &listing.Project, true
+}
+
+// saveProjectListing stores the listing of the project at path for the
+// loaded config. Failures are ignored. Caller must hold e.mu.
+func (e *engineImpl) saveProjectListing(path string, project *ProjectInfo) {
+	if revision := e.listingRevision(); revision != "" {
+		SaveJSON(e.getStorage(), Keys.ProjectCache(path), projectListing{Revision: revision, Project: *project})
+	}
+}
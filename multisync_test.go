@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -295,4 +296,75 @@ func (m *mockDestination) Backup() (string, error) {
 
 func (m *mockDestination) Validate() error {
 	return nil
-}
\ No newline at end of file
+}
+// validatingDestination is a mockDestination with its own name rules
+type validatingDestination struct {
+	mockDestination
+	validator ServerValidator
+	sanitizer NameSanitizer
+}
+
+func (v *validatingDestination) Validator() ServerValidator {
+	return v.validator
+}
+
+func (v *validatingDestination) Sanitizer() NameSanitizer {
+	return v.sanitizer
+}
+
+func TestDestinationValidation(t *testing.T) {
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	servers := map[string]ServerConfig{
+		"valid":              {Transport: "stdio", Command: "valid"},
+		"needs.rename":       {Transport: "stdio", Command: "renamed"},
+		"much-too-long-name": {Transport: "stdio", Command: "skipped"},
+	}
+	for name, server := range servers {
+		if err := e.AddServer(name, server); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	validator, err := NewPatternValidator(`^[a-z.-]+$`, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := &validatingDestination{
+		mockDestination: mockDestination{id: "strict"},
+		validator:       validator,
+		sanitizer:       NewReplacementSanitizer(map[string]string{".": "-"}, "", 0),
+	}
+
+	preview, err := e.PreviewSync(dest)
+	if err != nil {
+		t.Fatalf("PreviewSync failed: %v", err)
+	}
+	if len(preview.Changes) != 2 || len(preview.Errors) != 1 {
+		t.Errorf("Expected 2 changes and 1 error in preview, got %+v / %+v", preview.Changes, preview.Errors)
+	}
+
+	result, err := e.SyncTo(context.Background(), dest, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error, "much-too-long-name") {
+		t.Errorf("Expected the long name to be reported, got %+v", result.Errors)
+	}
+
+	var written struct {
+		Servers map[string]ServerConfig `json:"servers"`
+	}
+	if err := json.Unmarshal(dest.writtenData, &written); err != nil {
+		t.Fatal(err)
+	}
+	if len(written.Servers) != 2 {
+		t.Errorf("Expected 2 servers written, got %v", written.Servers)
+	}
+	if written.Servers["needs-rename"].Command != "renamed" {
+		t.Errorf("Expected sanitized name needs-rename, got %v", written.Servers)
+	}
+}
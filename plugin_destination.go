@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPluginTimeout bounds a single plugin invocation
+const DefaultPluginTimeout = 30 * time.Second
+
+// PluginProtocolVersion is sent with every plugin request
+const PluginProtocolVersion = 1
+
+// Plugin operations. The operation is passed as the last argument and in the
+// request body, like git credential helpers.
+const (
+	PluginOpDescribe  = "describe"
+	PluginOpTransform = "transform"
+	PluginOpRead      = "read"
+	PluginOpWrite     = "write"
+	PluginOpExists    = "exists"
+	PluginOpBackup    = "backup"
+)
+
+// PluginRequest is written as JSON to the plugin's stdin
+type PluginRequest struct {
+	Version     int     `json:"version"`
+	Operation   string  `json:"operation"`
+	Destination string  `json:"destination"`
+	Config      *Config `json:"config,omitempty"` // transform
+	Data        string  `json:"data,omitempty"`   // write
+}
+
+// PluginResponse is read as JSON from the plugin's stdout. Only the fields
+// for the requested operation need to be set.
+type PluginResponse struct {
+	Description    string          `json:"description,omitempty"`    // describe
+	SupportsBackup bool            `json:"supportsBackup,omitempty"` // describe
+	Result         json.RawMessage `json:"result,omitempty"`         // transform
+	Data           string          `json:"data,omitempty"`           // read
+	Exists         bool            `json:"exists,omitempty"`         // exists
+	BackupPath     string          `json:"backupPath,omitempty"`     // backup
+	Error          *PluginError    `json:"error,omitempty"`
+}
+
+// PluginError is an error reported by or about a plugin. Recoverable errors,
+// such as timeouts, are reported as recoverable sync errors.
+type PluginError struct {
+	Operation   string `json:"-"`
+	Message     string `json:"message"`
+	Recoverable bool   `json:"recoverable,omitempty"`
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("plugin %s failed: %s", e.Operation, e.Message)
+}
+
+// PluginDestination delegates the Destination methods to an external
+// executable speaking a JSON request/response protocol over stdin/stdout.
+// The executable is run once per operation as `command args... <operation>`.
+// A non-zero exit status without an "error" in the response is a failure.
+type PluginDestination struct {
+	ID      string
+	Command string
+	Args    []string
+	Env     []string // Extra environment variables, "KEY=value"
+	Timeout time.Duration
+
+	describeOnce sync.Once
+	description  string
+	backup       bool
+}
+
+// NewPluginDestination creates a destination backed by an external executable
+func NewPluginDestination(id, command string, args ...string) *PluginDestination {
+	return &PluginDestination{
+		ID:      id,
+		Command: command,
+		Args:    args,
+		Timeout: DefaultPluginTimeout,
+	}
+}
+
+// GetID returns the destination identifier
+func (p *PluginDestination) GetID() string {
+	return p.ID
+}
+
+// GetDescription returns the plugin's description, asking it once
+func (p *PluginDestination) GetDescription() string {
+	p.describe()
+	return p.description
+}
+
+// Transform asks the plugin to convert the config to its format
+func (p *PluginDestination) Transform(config *Config) (interface{}, error) {
+	resp, err := p.call(PluginRequest{Operation: PluginOpTransform, Config: config})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Result) == 0 {
+		return nil, &PluginError{Operation: PluginOpTransform, Message: "no result returned"}
+	}
+	return resp.Result, nil
+}
+
+// Read returns the current configuration held by the plugin
+func (p *PluginDestination) Read() ([]byte, error) {
+	resp, err := p.call(PluginRequest{Operation: PluginOpRead})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(resp.Data), nil
+}
+
+// Write passes the transformed configuration to the plugin
+func (p *PluginDestination) Write(data []byte) error {
+	_, err := p.call(PluginRequest{Operation: PluginOpWrite, Data: string(data)})
+	return err
+}
+
+// Exists asks the plugin whether its configuration exists
+func (p *PluginDestination) Exists() bool {
+	resp, err := p.call(PluginRequest{Operation: PluginOpExists})
+	return err == nil && resp.Exists
+}
+
+// SupportsBackup reports whether the plugin advertised backup support
+func (p *PluginDestination) SupportsBackup() bool {
+	p.describe()
+	return p.backup
+}
+
+// Backup asks the plugin to back up its configuration
+func (p *PluginDestination) Backup() (string, error) {
+	resp, err := p.call(PluginRequest{Operation: PluginOpBackup})
+	if err != nil {
+		return "", err
+	}
+	return resp.BackupPath, nil
+}
+
+// describe asks the plugin for its description and capabilities once.
+// Plugins that don't implement describe get a default description and no backups.
+func (p *PluginDestination) describe() {
+	p.describeOnce.Do(func() {
+		p.description = fmt.Sprintf("Plugin destination %s", p.Command)
+		resp, err := p.call(PluginRequest{Operation: PluginOpDescribe})
+		if err != nil {
+			return
+		}
+		if resp.Description != "" {
+			p.description = resp.Description
+		}
+		p.backup = resp.SupportsBackup
+	})
+}
+
+// call runs the plugin for one request and decodes its response
+func (p *PluginDestination) call(req PluginRequest) (*PluginResponse, error) {
+	req.Version = PluginProtocolVersion
+	req.Destination = p.ID
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, &PluginError{Operation: req.Operation, Message: fmt.Sprintf("failed to encode request: %v", err)}
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := append(append([]string{}, p.Args...), req.Operation)
+	cmd := exec.CommandContext(ctx, expandPath(p.Command), args...)
+	cmd.Env = append(os.Environ(), p.Env...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, &PluginError{
+			Operation:   req.Operation,
+			Message:     fmt.Sprintf("timed out after %s", timeout),
+			Recoverable: true,
+		}
+	}
+
+	var resp PluginResponse
+	decodeErr := json.Unmarshal(stdout.Bytes(), &resp)
+	if decodeErr == nil && resp.Error != nil {
+		resp.Error.Operation = req.Operation
+		return nil, resp.Error
+	}
+
+	if runErr != nil {
+		message := runErr.Error()
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			message = fmt.Sprintf("%s: %s", message, detail)
+		}
+		return nil, &PluginError{Operation: req.Operation, Message: message}
+	}
+	if decodeErr != nil {
+		return nil, &PluginError{Operation: req.Operation, Message: fmt.Sprintf("invalid response: %v", decodeErr)}
+	}
+
+	return &resp, nil
+}
+
+func (e *PluginError) recoverable() bool {
+	return e.Recoverable
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestPluginHelperProcess is run as the plugin executable by the tests below
+func TestPluginHelperProcess(t *testing.T) {
+	if os.Getenv("PLUGIN_HELPER_STATE") == "" {
+		return
+	}
+	defer os.Exit(0)
+
+	state := os.Getenv("PLUGIN_HELPER_STATE")
+	operation := os.Args[len(os.Args)-1]
+
+	var req PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil || req.Operation != operation {
+		fmt.Fprintln(os.Stderr, "bad request")
+		os.Exit(2)
+	}
+
+	var resp PluginResponse
+	switch operation {
+	case PluginOpDescribe:
+		resp.Description = "Helper plugin"
+		resp.SupportsBackup = true
+	case PluginOpTransform:
+		names := make([]string, 0, len(req.Config.Servers))
+		for name := range req.Config.Servers {
+			names = append(names, name)
+		}
+		resp.Result, _ = json.Marshal(map[string]interface{}{"servers": names})
+	case PluginOpRead:
+		data, _ := os.ReadFile(state)
+		resp.Data = string(data)
+	case PluginOpWrite:
+		if os.Getenv("PLUGIN_HELPER_MODE") == "reject" {
+			resp.Error = &PluginError{Message: "store is read-only", Recoverable: true}
+			break
+		}
+		if err := os.WriteFile(state, []byte(req.Data), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case PluginOpExists:
+		_, err := os.Stat(state)
+		resp.Exists = err == nil
+	case PluginOpBackup:
+		if os.Getenv("PLUGIN_HELPER_MODE") == "hang" {
+			time.Sleep(10 * time.Second)
+		}
+		resp.BackupPath = state + ".bak"
+	default:
+		fmt.Fprintln(os.Stderr, "unsupported operation")
+		os.Exit(1)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(resp)
+}
+
+func newHelperPlugin(state, mode string) *PluginDestination {
+	plugin := NewPluginDestination("helper", os.Args[0], "-test.run=^TestPluginHelperProcess$", "--")
+	plugin.Env = []string{"PLUGIN_HELPER_STATE=" + state, "PLUGIN_HELPER_MODE=" + mode}
+	return plugin
+}
+
+func TestPluginDestination(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "state.json")
+	plugin := newHelperPlugin(state, "")
+
+	if plugin.GetDescription() != "Helper plugin" || !plugin.SupportsBackup() {
+		t.Errorf("Expected description and backup support from describe, got %q %v",
+			plugin.GetDescription(), plugin.SupportsBackup())
+	}
+	if plugin.Exists() {
+		t.Error("Expected plugin state not to exist yet")
+	}
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("test-server", ServerConfig{
+		Transport: "stdio",
+		Command:   "test-command",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := engine.SyncTo(context.Background(), plugin, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected sync to succeed, got %+v", result.Errors)
+	}
+
+	data, err := plugin.Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(data) != `{"servers":["test-server"]}` {
+		t.Errorf("Unexpected plugin state: %s", data)
+	}
+	if !plugin.Exists() {
+		t.Error("Expected plugin state to exist")
+	}
+}
+
+func TestPluginDestinationErrors(t *testing.T) {
+	state := filepath.Join(t.TempDir(), "state.json")
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Errors reported by the plugin keep their recoverable flag
+	result, err := engine.SyncTo(context.Background(), newHelperPlugin(state, "reject"), SyncOptions{})
+	if err == nil {
+		t.Fatal("Expected write to fail")
+	}
+	if len(result.Errors) != 1 || !result.Errors[0].Recoverable {
+		t.Errorf("Expected one recoverable error, got %+v", result.Errors)
+	}
+
+	// Timeouts are recoverable
+	plugin := newHelperPlugin(state, "hang")
+	plugin.Timeout = 200 * time.Millisecond
+	if _, err := plugin.Backup(); !isRecoverable(err) {
+		t.Errorf("Expected recoverable timeout error, got %v", err)
+	}
+
+	// A missing executable is not
+	missing := NewPluginDestination("missing", filepath.Join(t.TempDir(), "no-such-plugin"))
+	if err := missing.Write([]byte("{}")); err == nil || isRecoverable(err) {
+		t.Errorf("Expected unrecoverable error, got %v", err)
+	}
+	if missing.GetDescription() == "" || missing.SupportsBackup() {
+		t.Error("Expected defaults for a plugin without describe")
+	}
+}
@@ -0,0 +1,504 @@
+package presets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	engine "github.com/b-open-io/agent-master-engine"
+	"gopkg.in/yaml.v3"
+)
+
+// DefinitionsFileName is the file in the storage directory holding
+// user-defined destinations
+const DefinitionsFileName = "destinations.json"
+
+// DestinationDefinition describes a destination declaratively, so new editors
+// can be supported without changing Go code
+type DestinationDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Paths maps a GOOS value ("darwin", "linux", "windows") to the config
+	// file path; "default" is used for any other OS
+	Paths map[string]string `json:"paths"`
+
+	// Format is the file format: "json" (default), "yaml" or "toml"
+	Format string `json:"format,omitempty"`
+
+	// ServersPath is the dot-separated location of the server map inside the
+	// document, e.g. "mcpServers" or "context_servers" or "mcp.servers"
+	ServersPath string `json:"serversPath"`
+
+	// Fields renames server fields, e.g. {"transport": "type", "env": "envs"}.
+	// The transport is only written and read when it is mapped here.
+	Fields map[string]string `json:"fields,omitempty"`
+
+	NamePattern string          `json:"namePattern,omitempty"`
+	Sanitizer   *SanitizerRules `json:"sanitizer,omitempty"`
+
+	// Plugin delegates the destination to an external executable instead;
+	// the file fields above are then ignored
+	Plugin *PluginDefinition `json:"plugin,omitempty"`
+}
+
+// PluginDefinition describes an external plugin destination
+type PluginDefinition struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Env     []string `json:"env,omitempty"`
+	Timeout string   `json:"timeout,omitempty"` // Duration, e.g. "10s"
+}
+
+// SanitizerRules describes how server names are rewritten for a destination.
+// Rules are applied in field order. Replace has no order of its own, so its
+// replacements are made in sorted order of the strings they replace.
+type SanitizerRules struct {
+	Replace   map[string]string `json:"replace,omitempty"`
+	Lowercase bool              `json:"lowercase,omitempty"`
+	Remove    string            `json:"remove,omitempty"` // Regex of characters to drop
+	MaxLength int               `json:"maxLength,omitempty"`
+}
+
+// definitionServerFields are the server fields a definition can rename
+var definitionServerFields = map[string]bool{
+	"transport": true,
+	"command":   true,
+	"args":      true,
+	"env":       true,
+	"url":       true,
+	"headers":   true,
+}
+
+var definitionNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// LoadDefinitions reads and validates a destination definitions file
+func LoadDefinitions(path string) ([]DestinationDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDefinitions(data)
+}
+
+// ParseDefinitions parses and validates destination definitions. The document
+// has the form {"destinations": [...]}.
+func ParseDefinitions(data []byte) ([]DestinationDefinition, error) {
+	var file struct {
+		Destinations []DestinationDefinition `json:"destinations"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse destination definitions: %w", err)
+	}
+
+	seen := make(map[string]bool, len(file.Destinations))
+	for i, def := range file.Destinations {
+		if err := def.Validate(); err != nil {
+			return nil, fmt.Errorf("destination %d (%s): %w", i, def.Name, err)
+		}
+		if seen[def.Name] {
+			return nil, fmt.Errorf("destination %d: duplicate name %q", i, def.Name)
+		}
+		seen[def.Name] = true
+	}
+
+	return file.Destinations, nil
+}
+
+// Validate checks that the definition can be turned into a destination
+func (d DestinationDefinition) Validate() error {
+	if !definitionNamePattern.MatchString(d.Name) {
+		return fmt.Errorf("name must match %s", definitionNamePattern)
+	}
+	if d.Plugin != nil {
+		return d.Plugin.validate()
+	}
+	if d.Path() == "" {
+		return fmt.Errorf("no path for %s and no default path", runtime.GOOS)
+	}
+
+	switch d.format() {
+	case "json", "yaml", "toml":
+	default:
+		return fmt.Errorf("unsupported format %q", d.Format)
+	}
+
+	if d.ServersPath == "" {
+		return fmt.Errorf("serversPath is required")
+	}
+	for _, segment := range strings.Split(d.ServersPath, ".") {
+		if segment == "" {
+			return fmt.Errorf("invalid serversPath %q", d.ServersPath)
+		}
+	}
+
+	renamed := make(map[string]bool, len(d.Fields))
+	for from, to := range d.Fields {
+		if !definitionServerFields[from] {
+			return fmt.Errorf("unknown server field %q", from)
+		}
+		if to == "" || renamed[to] {
+			return fmt.Errorf("invalid or duplicate rename for field %q", from)
+		}
+		renamed[to] = true
+	}
+
+	if d.NamePattern != "" {
+		if _, err := regexp.Compile(d.NamePattern); err != nil {
+			return fmt.Errorf("invalid namePattern: %w", err)
+		}
+	}
+	if d.Sanitizer != nil {
+		if d.Sanitizer.Remove != "" {
+			if _, err := regexp.Compile(d.Sanitizer.Remove); err != nil {
+				return fmt.Errorf("invalid sanitizer remove pattern: %w", err)
+			}
+		}
+		if d.Sanitizer.MaxLength < 0 {
+			return fmt.Errorf("sanitizer maxLength must not be negative")
+		}
+	}
+
+	return nil
+}
+
+func (p *PluginDefinition) validate() error {
+	if p.Command == "" {
+		return fmt.Errorf("plugin command is required")
+	}
+	if p.Timeout != "" {
+		if timeout, err := time.ParseDuration(p.Timeout); err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid plugin timeout %q", p.Timeout)
+		}
+	}
+	return nil
+}
+
+// Path returns the config file path for the current OS
+func (d DestinationDefinition) Path() string {
+	if path := d.Paths[runtime.GOOS]; path != "" {
+		return path
+	}
+	return d.Paths["default"]
+}
+
+func (d DestinationDefinition) format() string {
+	if d.Format == "" {
+		return "json"
+	}
+	return strings.ToLower(d.Format)
+}
+
+// Preset builds the preset equivalent of the definition
+func (d DestinationDefinition) Preset() (Preset, error) {
+	if err := d.Validate(); err != nil {
+		return Preset{}, err
+	}
+	if d.Plugin != nil {
+		return Preset{}, fmt.Errorf("%s is a plugin destination", d.Name)
+	}
+
+	description := d.Description
+	if description == "" {
+		description = d.Name
+	}
+
+	preset := Preset{
+		Name:         d.Name,
+		Description:  description,
+		DefaultPath:  d.Path(),
+		ConfigFormat: "flat",
+		FileFormat:   d.format(),
+		NamePattern:  d.NamePattern,
+		MergeFile:    d.mergeFile,
+		ParseFile:    d.parseFile,
+	}
+	if d.Sanitizer != nil {
+		preset.NameSanitizer = d.Sanitizer.sanitizer()
+		preset.RequiresSanitization = true
+	}
+	return preset, nil
+}
+
+// NewDefinitionDestination creates a destination from a definition
+func NewDefinitionDestination(def DestinationDefinition) (engine.Destination, error) {
+	if def.Plugin != nil {
+		if err := def.Validate(); err != nil {
+			return nil, err
+		}
+		dest := engine.NewPluginDestination(def.Name, def.Plugin.Command, def.Plugin.Args...)
+		dest.Env = def.Plugin.Env
+		if def.Plugin.Timeout != "" {
+			dest.Timeout, _ = time.ParseDuration(def.Plugin.Timeout)
+		}
+		return dest, nil
+	}
+
+	preset, err := def.Preset()
+	if err != nil {
+		return nil, err
+	}
+	return &PresetDestination{
+		preset: preset,
+		path:   preset.DefaultPath,
+	}, nil
+}
+
+// mergeFile writes servers at the definition's servers path, keeping the rest
+// of the document and unknown keys of existing server entries. YAML and TOML
+// comments are not preserved.
+func (d DestinationDefinition) mergeFile(existing []byte, servers map[string]engine.ServerConfig) ([]byte, error) {
+	doc, err := decodeDocument(d.format(), existing)
+	if err != nil {
+		return nil, err
+	}
+
+	path := strings.Split(d.ServersPath, ".")
+	previous, _ := lookupPath(doc, path).(map[string]interface{})
+
+	entries := make(map[string]interface{}, len(servers))
+	for name, server := range servers {
+		entry, ok := previous[name].(map[string]interface{})
+		if !ok {
+			entry = make(map[string]interface{})
+		}
+		for field := range definitionServerFields {
+			if d.hasField(field) {
+				delete(entry, d.fieldName(field))
+			}
+		}
+		for field, value := range serverFields(server) {
+			if d.hasField(field) {
+				entry[d.fieldName(field)] = value
+			}
+		}
+		entries[name] = entry
+	}
+
+	if err := setPath(doc, path, entries); err != nil {
+		return nil, err
+	}
+	return encodeDocument(d.format(), doc)
+}
+
+// parseFile reads the servers at the definition's servers path
+func (d DestinationDefinition) parseFile(data []byte) (*engine.Config, error) {
+	doc, err := decodeDocument(d.format(), data)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _ := lookupPath(doc, strings.Split(d.ServersPath, ".")).(map[string]interface{})
+	mcpConfig := &engine.MCPConfig{MCPServers: make(map[string]engine.ServerConfig, len(entries))}
+	for name, value := range entries {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		for field := range definitionServerFields {
+			if !d.hasField(field) {
+				continue
+			}
+			if v, ok := entry[d.fieldName(field)]; ok {
+				fields[field] = v
+			}
+		}
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server %q: %w", name, err)
+		}
+		var server engine.ServerConfig
+		if err := json.Unmarshal(raw, &server); err != nil {
+			return nil, fmt.Errorf("invalid server %q: %w", name, err)
+		}
+		if server.Transport == "" {
+			if server.Command != "" {
+				server.Transport = "stdio"
+			} else if server.URL != "" {
+				server.Transport = "sse"
+			}
+		}
+		mcpConfig.MCPServers[name] = server
+	}
+
+	return mcpConfig.ToConfigWithOptions(false)
+}
+
+// hasField reports whether the destination's server entries have a field.
+// Many formats infer the transport, so it is only kept when mapped.
+func (d DestinationDefinition) hasField(field string) bool {
+	if field != "transport" {
+		return true
+	}
+	_, ok := d.Fields[field]
+	return ok
+}
+
+// fieldName returns the destination's name for a server field
+func (d DestinationDefinition) fieldName(field string) string {
+	if renamed, ok := d.Fields[field]; ok {
+		return renamed
+	}
+	return field
+}
+
+// sanitizer returns a function applying the rules to a server name. The
+// rules must have been validated; the remove pattern is compiled once here.
+func (r *SanitizerRules) sanitizer() func(string) string {
+	replacements := make([]string, 0, len(r.Replace))
+	for old := range r.Replace {
+		replacements = append(replacements, old)
+	}
+	sort.Strings(replacements)
+	replace := make(map[string]string, len(r.Replace))
+	for old, with := range r.Replace {
+		replace[old] = with
+	}
+
+	var remove *regexp.Regexp
+	if r.Remove != "" {
+		remove = regexp.MustCompile(r.Remove)
+	}
+	lowercase, maxLength := r.Lowercase, r.MaxLength
+
+	return func(name string) string {
+		for _, old := range replacements {
+			name = strings.ReplaceAll(name, old, replace[old])
+		}
+
+		if lowercase {
+			name = strings.ToLower(name)
+		}
+		if remove != nil {
+			name = remove.ReplaceAllString(name, "")
+		}
+		if maxLength > 0 && len(name) > maxLength {
+			name = name[:maxLength]
+		}
+
+		if name == "" {
+			name = "unnamed-server"
+		}
+		return name
+	}
+}
+
+// serverFields returns the non-empty fields of a server keyed by their JSON names
+func serverFields(server engine.ServerConfig) map[string]interface{} {
+	fields := map[string]interface{}{"transport": server.Transport}
+	if server.Command != "" {
+		fields["command"] = server.Command
+	}
+	if len(server.Args) > 0 {
+		fields["args"] = server.Args
+	}
+	if len(server.Env) > 0 {
+		fields["env"] = server.Env
+	}
+	if server.URL != "" {
+		fields["url"] = server.URL
+	}
+	if len(server.Headers) > 0 {
+		fields["headers"] = server.Headers
+	}
+	return fields
+}
+
+// decodeDocument decodes a JSON, YAML or TOML document into a generic map
+func decodeDocument(format string, data []byte) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	if len(bytes.TrimSpace(data)) == 0 {
+		return doc, nil
+	}
+
+	var err error
+	switch format {
+	case "yaml":
+		err = yaml.Unmarshal(data, &doc)
+	case "toml":
+		_, err = toml.Decode(string(data), &doc)
+	default:
+		err = json.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", strings.ToUpper(format), err)
+	}
+	if doc == nil {
+		doc = make(map[string]interface{})
+	}
+	return doc, nil
+}
+
+// encodeDocument encodes a generic map as JSON, YAML or TOML
+func encodeDocument(format string, doc map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "yaml":
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	case "toml":
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode TOML: %w", err)
+		}
+	default:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// lookupPath returns the value at path in doc, or nil if it doesn't exist
+func lookupPath(doc map[string]interface{}, path []string) interface{} {
+	var current interface{} = doc
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// setPath sets the value at path in doc, creating intermediate maps
+func setPath(doc map[string]interface{}, path []string, value interface{}) error {
+	current := doc
+	for i, key := range path[:len(path)-1] {
+		next, exists := current[key]
+		if !exists || next == nil {
+			child := make(map[string]interface{})
+			current[key] = child
+			current = child
+			continue
+		}
+		child, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(path[:i+1], "."))
+		}
+		current = child
+	}
+	current[path[len(path)-1]] = value
+	return nil
+}
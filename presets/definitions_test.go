@@ -0,0 +1,211 @@
+package presets
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	engine "github.com/b-open-io/agent-master-engine"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDefinitions(t *testing.T) {
+	defs, err := ParseDefinitions([]byte(`{
+  "destinations": [
+    {
+      "name": "editor",
+      "paths": {"default": "~/.editor/mcp.yaml"},
+      "format": "yaml",
+      "serversPath": "mcp.servers",
+      "fields": {"transport": "type"},
+      "sanitizer": {"lowercase": true, "remove": "[^a-z0-9-]"}
+    },
+    {
+      "name": "remote",
+      "plugin": {"command": "remote-mcp", "timeout": "5s"}
+    }
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("ParseDefinitions failed: %v", err)
+	}
+	if len(defs) != 2 || defs[0].Path() != "~/.editor/mcp.yaml" || defs[1].Plugin == nil {
+		t.Errorf("Unexpected definitions: %+v", defs)
+	}
+
+	invalid := map[string]string{
+		"unknown field":    `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "s", "extra": 1}]}`,
+		"bad name":         `{"destinations": [{"name": "a b", "paths": {"default": "a.json"}, "serversPath": "s"}]}`,
+		"no path":          `{"destinations": [{"name": "a", "serversPath": "s"}]}`,
+		"bad format":       `{"destinations": [{"name": "a", "paths": {"default": "a.ini"}, "format": "ini", "serversPath": "s"}]}`,
+		"no servers path":  `{"destinations": [{"name": "a", "paths": {"default": "a.json"}}]}`,
+		"empty segment":    `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "mcp..servers"}]}`,
+		"unknown rename":   `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "s", "fields": {"cwd": "dir"}}]}`,
+		"duplicate rename": `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "s", "fields": {"url": "x", "command": "x"}}]}`,
+		"bad pattern":      `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "s", "namePattern": "("}]}`,
+		"bad remove":       `{"destinations": [{"name": "a", "paths": {"default": "a.json"}, "serversPath": "s", "sanitizer": {"remove": "["}}]}`,
+		"bad timeout":      `{"destinations": [{"name": "a", "plugin": {"command": "a", "timeout": "soon"}}]}`,
+		"duplicate name":   `{"destinations": [{"name": "a", "plugin": {"command": "a"}}, {"name": "a", "plugin": {"command": "b"}}]}`,
+	}
+	for name, data := range invalid {
+		if _, err := ParseDefinitions([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDefinitionDestination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.yaml")
+	existing := `theme: dark
+mcp:
+  servers:
+    api:
+      type: stdio
+      command: old
+      disabled: false
+`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	def := DestinationDefinition{
+		Name:        "editor",
+		Paths:       map[string]string{"default": path},
+		Format:      "yaml",
+		ServersPath: "mcp.servers",
+		Fields:      map[string]string{"transport": "type"},
+		Sanitizer:   &SanitizerRules{Replace: map[string]string{"_": "-"}, Lowercase: true, Remove: "[^a-z0-9-]", MaxLength: 8},
+	}
+	dest, err := NewDefinitionDestination(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Names are sanitized by the rules, in order
+	sanitizer := dest.(*PresetDestination).Sanitizer()
+	for name, want := range map[string]string{"My_Server!": "my-serve", "API": "api", "!!!": "unnamed-server"} {
+		if got := sanitizer.Sanitize(name); got != want {
+			t.Errorf("Sanitize(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	// Replacements are made in sorted order of the strings they replace
+	chained := (&SanitizerRules{Replace: map[string]string{"b": "c", "a": "b"}}).sanitizer()
+	if got := chained("ab"); got != "cc" {
+		t.Errorf("Expected a->b then b->c, got %q", got)
+	}
+
+	// Writing merges into the servers path, keeping the rest of the
+	// document and unknown keys of existing entries
+	config := &engine.Config{Servers: map[string]engine.ServerWithMetadata{
+		"api": {ServerConfig: engine.ServerConfig{Transport: "stdio", Command: "api"}, Internal: engine.InternalMetadata{Enabled: true}},
+		"web": {ServerConfig: engine.ServerConfig{Transport: "sse", URL: "https://example.com"}, Internal: engine.InternalMetadata{Enabled: true}},
+	}}
+	transformed, err := dest.Transform(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(transformed)
+	if err := dest.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	written, _ := os.ReadFile(path)
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(written, &doc); err != nil {
+		t.Fatalf("Expected valid YAML, got %s: %v", written, err)
+	}
+	if doc["theme"] != "dark" {
+		t.Errorf("Expected other settings to be kept, got %s", written)
+	}
+	api := doc["mcp"].(map[string]interface{})["servers"].(map[string]interface{})["api"].(map[string]interface{})
+	if api["type"] != "stdio" || api["command"] != "api" || api["disabled"] != false {
+		t.Errorf("Expected api to be updated with renamed fields and extra keys kept, got %v", api)
+	}
+	if _, ok := api["transport"]; ok {
+		t.Error("Expected transport to be written as type")
+	}
+
+	// Reading parses the servers back
+	parsed, err := dest.(*PresetDestination).ParseConfig(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Servers) != 2 || parsed.Servers["web"].URL != "https://example.com" || parsed.Servers["api"].Transport != "stdio" {
+		t.Errorf("Unexpected parsed servers: %+v", parsed.Servers)
+	}
+}
+
+func TestDefinitionWithoutTransport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	dest, err := NewDefinitionDestination(DestinationDefinition{
+		Name:        "editor",
+		Paths:       map[string]string{"default": path},
+		ServersPath: "servers",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a transport mapping, entries have no transport field
+	config := &engine.Config{Servers: map[string]engine.ServerWithMetadata{
+		"api": {ServerConfig: engine.ServerConfig{Transport: "stdio", Command: "api"}, Internal: engine.InternalMetadata{Enabled: true}},
+		"web": {ServerConfig: engine.ServerConfig{Transport: "sse", URL: "https://example.com"}, Internal: engine.InternalMetadata{Enabled: true}},
+	}}
+	transformed, err := dest.Transform(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(transformed)
+	if err := dest.Write(data); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	written, _ := os.ReadFile(path)
+	var doc struct {
+		Servers map[string]map[string]interface{} `json:"servers"`
+	}
+	if err := json.Unmarshal(written, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for name, entry := range doc.Servers {
+		if _, ok := entry["transport"]; ok {
+			t.Errorf("Expected no transport field for %s, got %v", name, entry)
+		}
+	}
+
+	// The transport is inferred when reading
+	parsed, err := dest.(*PresetDestination).ParseConfig(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Servers["api"].Transport != "stdio" || parsed.Servers["web"].Transport != "sse" {
+		t.Errorf("Expected inferred transports, got %+v", parsed.Servers)
+	}
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefinitionsFileName)
+	if _, err := LoadDefinitions(path); !os.IsNotExist(err) {
+		t.Errorf("Expected a missing file to be reported as such, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"destinations": [{"name": "a", "plugin": {"command": "a"}}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defs, err := LoadDefinitions(path)
+	if err != nil || len(defs) != 1 {
+		t.Fatalf("LoadDefinitions: %+v, %v", defs, err)
+	}
+
+	dest, err := NewDefinitionDestination(defs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dest.(*engine.PluginDestination); !ok {
+		t.Errorf("Expected a plugin destination, got %T", dest)
+	}
+	if _, err := defs[0].Preset(); err == nil || !strings.Contains(err.Error(), "plugin") {
+		t.Errorf("Expected a plugin definition to have no preset, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package presets
+
+import (
+	"encoding/json"
+	"fmt"
+
+	engine "github.com/b-open-io/agent-master-engine"
+)
+
+// vscodeServer is a server entry in a VS Code .vscode/mcp.json
+type vscodeServer struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// vscodeWorkspaceConfig is the layout of a VS Code .vscode/mcp.json
+type vscodeWorkspaceConfig struct {
+	Inputs  interface{}             `json:"inputs,omitempty"`
+	Servers map[string]vscodeServer `json:"servers"`
+}
+
+// transformForVSCodeWorkspace writes servers under "servers" with the
+// transport as "type", carrying over inputs from the config metadata
+func transformForVSCodeWorkspace(config *engine.Config) (interface{}, error) {
+	result := vscodeWorkspaceConfig{
+		Servers: make(map[string]vscodeServer),
+	}
+
+	for name, server := range config.Servers {
+		if !server.Internal.Enabled {
+			continue
+		}
+		serverType := server.Transport
+		if serverType == "" {
+			serverType = "stdio"
+		}
+		result.Servers[name] = vscodeServer{
+			Type:    serverType,
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.Headers,
+		}
+	}
+
+	if inputs, ok := config.Metadata["inputs"]; ok {
+		result.Inputs = inputs
+	}
+
+	return result, nil
+}
+
+// parseVSCodeWorkspace reads a .vscode/mcp.json back into a config
+func parseVSCodeWorkspace(data []byte) (*engine.Config, error) {
+	var workspace struct {
+		Inputs  []engine.MCPInput       `json:"inputs"`
+		Servers map[string]vscodeServer `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse VS Code mcp.json: %w", err)
+	}
+
+	mcpConfig := &engine.MCPConfig{MCPServers: make(map[string]engine.ServerConfig, len(workspace.Servers))}
+	for name, server := range workspace.Servers {
+		mcpConfig.MCPServers[name] = engine.ServerConfig{
+			Transport: server.Type,
+			Command:   server.Command,
+			Args:      server.Args,
+			Env:       server.Env,
+			URL:       server.URL,
+			Headers:   server.Headers,
+		}
+	}
+
+	config, err := mcpConfig.ToConfigWithOptions(false)
+	if err != nil {
+		return nil, err
+	}
+	if len(workspace.Inputs) > 0 {
+		config.Metadata = map[string]interface{}{"inputs": workspace.Inputs}
+	}
+	return config, nil
+}
+
+// geminiServerKeys are the per-server keys managed when merging Gemini settings
+var geminiServerKeys = []string{"command", "args", "env", "url", "httpUrl", "headers"}
+
+// mergeGeminiSettings writes servers into the mcpServers of an existing Gemini
+// CLI settings.json. Other settings and unmanaged per-server keys such as
+// "trust" or "timeout" are kept.
+func mergeGeminiSettings(existing []byte, servers map[string]engine.ServerConfig) ([]byte, error) {
+	settings := make(map[string]interface{})
+	if len(existing) > 0 {
+		if err := json.Unmarshal(existing, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse Gemini settings: %w", err)
+		}
+	}
+
+	previous, _ := settings["mcpServers"].(map[string]interface{})
+	mcpServers := make(map[string]interface{}, len(servers))
+	for name, server := range servers {
+		entry, ok := previous[name].(map[string]interface{})
+		if !ok {
+			entry = make(map[string]interface{})
+		}
+		for _, key := range geminiServerKeys {
+			delete(entry, key)
+		}
+
+		switch {
+		case server.Command != "":
+			entry["command"] = server.Command
+			if len(server.Args) > 0 {
+				entry["args"] = server.Args
+			}
+			if len(server.Env) > 0 {
+				entry["env"] = server.Env
+			}
+		case server.Transport == "http" || server.Transport == "streamable-http":
+			entry["httpUrl"] = server.URL
+		default:
+			entry["url"] = server.URL
+		}
+		if len(server.Headers) > 0 {
+			entry["headers"] = server.Headers
+		}
+
+		mcpServers[name] = entry
+	}
+	settings["mcpServers"] = mcpServers
+
+	return json.MarshalIndent(settings, "", "  ")
+}
+
+// parseGeminiSettings reads the mcpServers of a Gemini CLI settings.json
+func parseGeminiSettings(data []byte) (*engine.Config, error) {
+	var settings struct {
+		MCPServers map[string]struct {
+			Command string            `json:"command"`
+			Args    []string          `json:"args"`
+			Env     map[string]string `json:"env"`
+			URL     string            `json:"url"`
+			HTTPURL string            `json:"httpUrl"`
+			Headers map[string]string `json:"headers"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini settings: %w", err)
+	}
+
+	mcpConfig := &engine.MCPConfig{MCPServers: make(map[string]engine.ServerConfig, len(settings.MCPServers))}
+	for name, server := range settings.MCPServers {
+		config := engine.ServerConfig{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.Headers,
+		}
+		switch {
+		case server.Command != "":
+			config.Transport = "stdio"
+		case server.HTTPURL != "":
+			config.Transport = "http"
+			config.URL = server.HTTPURL
+		default:
+			config.Transport = "sse"
+		}
+		mcpConfig.MCPServers[name] = config
+	}
+	return mcpConfig.ToConfigWithOptions(false)
+}
@@ -3,6 +3,7 @@
 package presets
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -25,11 +26,27 @@ type Preset struct {
 	NameSanitizer        func(string) string
 	RequiresSanitization bool
 	SupportsProjects     bool
+	ProjectFile          string // Path relative to a project root for per-project destinations
+	DetectPaths          []string // Paths whose presence means the client is installed
 	CustomTransform      func(*engine.Config) (interface{}, error)
+
+	// MergeFile writes the transformed servers into the existing file contents
+	// for formats that aren't plain JSON. ParseFile reads them back.
+	MergeFile func(existing []byte, servers map[string]engine.ServerConfig) ([]byte, error)
+	ParseFile func(data []byte) (*engine.Config, error)
 }
 
-// Common presets - users can define their own
-var CommonPresets = map[string]Preset{
+// CommonPresets is the preset registry, so presets added with
+// RegisterPreset appear in it.
+//
+// Deprecated: use GetPreset, ListPresets and RegisterPreset. Reading
+// CommonPresets directly isn't safe while presets are being registered,
+// and writing to it skips RegisterPreset's validation.
+var CommonPresets = registry
+
+// registry holds the built-in presets and those added with RegisterPreset.
+// Access it only through the registry functions, which hold presetsMu.
+var registry = map[string]Preset{
 	"claude": {
 		Name:                 "claude",
 		Description:          "Claude Desktop configuration",
@@ -46,6 +63,7 @@ var CommonPresets = map[string]Preset{
 		Name:         "vscode-mcp",
 		Description:  "VS Code MCP extension",
 		DefaultPath:  "~/.vscode/extensions/mcp/settings.json",
+		DetectPaths:  []string{"~/.vscode"},
 		ConfigFormat: "flat",
 		FileFormat:   "json",
 	},
@@ -53,9 +71,59 @@ var CommonPresets = map[string]Preset{
 		Name:         "cursor",
 		Description:  "Cursor IDE",
 		DefaultPath:  "~/Library/Application Support/Cursor/User/globalStorage/settings.json",
+		DetectPaths:  []string{"~/Library/Application Support/Cursor", "~/.cursor", "~/.config/Cursor"},
 		ConfigFormat: "flat",
 		FileFormat:   "json",
 	},
+	"codex": {
+		Name:                 "codex",
+		Description:          "OpenAI Codex CLI",
+		DefaultPath:          "~/.codex/config.toml",
+		ConfigFormat:         "flat",
+		FileFormat:           "toml",
+		NamePattern:          "^[a-zA-Z0-9_-]+$",
+		RequiresSanitization: true,
+		NameSanitizer:        sanitizeForClaude,
+		MergeFile:            engine.MergeMCPServersTOML,
+		ParseFile:            parseTOML,
+	},
+	"vscode-workspace": {
+		Name:             "vscode-workspace",
+		Description:      "VS Code workspace (.vscode/mcp.json)",
+		ConfigFormat:     "vscode-workspace",
+		FileFormat:       "json",
+		SupportsProjects: true,
+		ProjectFile:      filepath.Join(".vscode", "mcp.json"),
+		CustomTransform:  transformForVSCodeWorkspace,
+		ParseFile:        parseVSCodeWorkspace,
+	},
+	"gemini": {
+		Name:         "gemini",
+		Description:  "Gemini CLI",
+		DefaultPath:  "~/.gemini/settings.json",
+		ConfigFormat: "flat",
+		FileFormat:   "json",
+		MergeFile:    mergeGeminiSettings,
+		ParseFile:    parseGeminiSettings,
+	},
+	"goose": {
+		Name:         "goose",
+		Description:  "Goose",
+		DefaultPath:  "~/.config/goose/config.yaml",
+		ConfigFormat: "flat",
+		FileFormat:   "yaml",
+		MergeFile:    engine.MergeGooseExtensions,
+		ParseFile:    parseGoose,
+	},
+	"continue": {
+		Name:         "continue",
+		Description:  "Continue",
+		DefaultPath:  "~/.continue/config.yaml",
+		ConfigFormat: "flat",
+		FileFormat:   "yaml",
+		MergeFile:    engine.MergeContinueMCPServers,
+		ParseFile:    parseContinue,
+	},
 	"generic-json": {
 		Name:         "generic-json",
 		Description:  "Generic JSON format",
@@ -67,7 +135,7 @@ var CommonPresets = map[string]Preset{
 
 // NewDestination creates a destination from a preset
 func NewDestination(presetName string, customPath ...string) (engine.Destination, error) {
-	preset, ok := CommonPresets[presetName]
+	preset, ok := GetPreset(presetName)
 	if !ok {
 		return nil, fmt.Errorf("unknown preset: %s", presetName)
 	}
@@ -101,6 +169,37 @@ func (pd *PresetDestination) GetPath() string {
 	return pd.path
 }
 
+// ForProject returns the destination writing inside the given project for
+// presets with a ProjectFile, and the destination itself otherwise
+func (pd *PresetDestination) ForProject(projectPath string) engine.Destination {
+	if pd.preset.ProjectFile == "" {
+		return pd
+	}
+	return &PresetDestination{
+		preset: pd.preset,
+		path:   filepath.Join(projectPath, pd.preset.ProjectFile),
+	}
+}
+
+// WithPath returns the destination writing the preset's format to path
+func (pd *PresetDestination) WithPath(path string) engine.Destination {
+	return &PresetDestination{
+		preset: pd.preset,
+		path:   path,
+	}
+}
+
+// ProjectOnly reports whether the destination still needs a project to know
+// where to write
+func (pd *PresetDestination) ProjectOnly() bool {
+	return pd.preset.ProjectFile != "" && pd.path == ""
+}
+
+// errProjectOnly is returned when a per-project destination is used without a project
+func (pd *PresetDestination) errProjectOnly() error {
+	return fmt.Errorf("%s writes %s inside projects; sync it through a project", pd.preset.Name, pd.preset.ProjectFile)
+}
+
 func (pd *PresetDestination) Transform(config *engine.Config) (interface{}, error) {
 	// Use custom transform if provided
 	if pd.preset.CustomTransform != nil {
@@ -159,27 +258,78 @@ func (pd *PresetDestination) transformProjectNested(config *engine.Config) map[s
 }
 
 func (pd *PresetDestination) Read() ([]byte, error) {
+	if pd.ProjectOnly() {
+		return nil, pd.errProjectOnly()
+	}
 	path := expandPath(pd.path)
 	return os.ReadFile(path)
 }
 
+// ParseConfig parses the destination file according to the preset's file format
+func (pd *PresetDestination) ParseConfig(data []byte) (*engine.Config, error) {
+	if pd.preset.ParseFile != nil {
+		return pd.preset.ParseFile(data)
+	}
+	return engine.ParseMCPConfigWithOptions(data, false)
+}
+
 func (pd *PresetDestination) Write(data []byte) error {
+	if pd.ProjectOnly() {
+		return pd.errProjectOnly()
+	}
 	path := expandPath(pd.path)
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	// Write atomically
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return err
+	// Non-JSON files are merged into the existing document
+	if pd.preset.MergeFile != nil {
+		merged, err := pd.mergeFile(path, data)
+		if err != nil {
+			return err
+		}
+		data = merged
 	}
 
-	return os.Rename(tmpPath, path)
+	// Write atomically, keeping symlinks and permissions
+	return engine.WriteFileAtomic(path, data, engine.DefaultFileMode)
+}
+
+// mergeFile writes the transformed servers into the existing file using the
+// preset's MergeFile, keeping everything else
+func (pd *PresetDestination) mergeFile(path string, data []byte) ([]byte, error) {
+	var transformed struct {
+		MCPServers map[string]engine.ServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &transformed); err != nil {
+		return nil, fmt.Errorf("invalid transformed config: %w", err)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return pd.preset.MergeFile(existing, transformed.MCPServers)
+}
+
+// Lock takes the config file's advisory lock
+func (pd *PresetDestination) Lock(timeout time.Duration) (func() error, error) {
+	if pd.ProjectOnly() {
+		return nil, pd.errProjectOnly()
+	}
+	lock, err := engine.AcquireFileLock(expandPath(pd.path), timeout)
+	if err != nil {
+		return nil, err
+	}
+	return lock.Unlock, nil
 }
 
 func (pd *PresetDestination) Exists() bool {
+	if pd.ProjectOnly() {
+		return false
+	}
 	path := expandPath(pd.path)
 	_, err := os.Stat(path)
 	return err == nil
@@ -190,6 +340,9 @@ func (pd *PresetDestination) SupportsBackup() bool {
 }
 
 func (pd *PresetDestination) Backup() (string, error) {
+	if pd.ProjectOnly() {
+		return "", pd.errProjectOnly()
+	}
 	// Simple timestamp-based backup
 	path := expandPath(pd.path)
 	backupPath := fmt.Sprintf("%s.backup.%d", path, time.Now().Unix())
@@ -199,7 +352,7 @@ func (pd *PresetDestination) Backup() (string, error) {
 		return "", err
 	}
 
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+	if err := engine.WriteFileAtomic(backupPath, data, engine.DefaultFileMode); err != nil {
 		return "", err
 	}
 
@@ -208,24 +361,46 @@ func (pd *PresetDestination) Backup() (string, error) {
 
 // CreateValidator creates a validator from preset
 func CreateValidator(presetName string) engine.ServerValidator {
-	preset, ok := CommonPresets[presetName]
-	if !ok || preset.NamePattern == "" {
+	preset, ok := GetPreset(presetName)
+	if !ok {
 		return nil
 	}
+	return newValidator(preset)
+}
 
+// CreateSanitizer creates a sanitizer from preset
+func CreateSanitizer(presetName string) engine.NameSanitizer {
+	preset, ok := GetPreset(presetName)
+	if !ok {
+		return nil
+	}
+	return newSanitizer(preset)
+}
+
+// Validator returns the preset's validator, applied by the engine when syncing
+func (pd *PresetDestination) Validator() engine.ServerValidator {
+	return newValidator(pd.preset)
+}
+
+// Sanitizer returns the preset's name sanitizer, applied by the engine when syncing
+func (pd *PresetDestination) Sanitizer() engine.NameSanitizer {
+	return newSanitizer(pd.preset)
+}
+
+func newValidator(preset Preset) engine.ServerValidator {
+	if preset.NamePattern == "" {
+		return nil
+	}
 	return &PatternValidator{
 		pattern: regexp.MustCompile(preset.NamePattern),
 		preset:  preset,
 	}
 }
 
-// CreateSanitizer creates a sanitizer from preset
-func CreateSanitizer(presetName string) engine.NameSanitizer {
-	preset, ok := CommonPresets[presetName]
-	if !ok || preset.NameSanitizer == nil {
+func newSanitizer(preset Preset) engine.NameSanitizer {
+	if preset.NameSanitizer == nil {
 		return nil
 	}
-
 	return &PresetSanitizer{
 		sanitize: preset.NameSanitizer,
 		pattern:  preset.NamePattern,
@@ -335,6 +510,19 @@ func transformForClaude(config *engine.Config) (interface{}, error) {
 	}, nil
 }
 
+// File parsers for non-JSON presets; variables are left unsubstituted
+func parseTOML(data []byte) (*engine.Config, error) {
+	return engine.ParseMCPConfigTOML(data, false)
+}
+
+func parseGoose(data []byte) (*engine.Config, error) {
+	return engine.ParseGooseConfig(data, false)
+}
+
+func parseContinue(data []byte) (*engine.Config, error) {
+	return engine.ParseContinueConfig(data, false)
+}
+
 // getClaudeDesktopConfigPath returns the platform-specific Claude Desktop config path
 func getClaudeDesktopConfigPath() string {
 	switch runtime.GOOS {
@@ -0,0 +1,103 @@
+package presets
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	engine "github.com/b-open-io/agent-master-engine"
+)
+
+func TestGitDestinationKeepsPresetFormat(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	existing := "model = \"o3\"\n"
+	if err := os.WriteFile(filepath.Join(repo, "config.toml"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	codex, err := NewDestination("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := engine.NewGitDestination("dotfiles", repo, "config.toml", codex)
+	dest.AuthorName = "Test"
+	dest.AuthorEmail = "test@example.com"
+
+	eng, err := engine.NewEngine(engine.WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	if err := eng.AddServer("api", engine.ServerConfig{Transport: "stdio", Command: "api"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.SyncTo(context.Background(), dest, engine.SyncOptions{}); err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+
+	// The file is merged as TOML by the preset
+	written, err := os.ReadFile(filepath.Join(repo, "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(written), existing) || !strings.Contains(string(written), "[mcp_servers.api]") {
+		t.Errorf("Expected TOML merged into the existing file, got %s", written)
+	}
+
+	// Existing servers are parsed back, so syncing again changes nothing
+	preview, err := eng.PreviewSync(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", preview.Changes)
+	}
+}
+
+func TestSyncToParsesDestinationFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	existing := "[mcp_servers.api]\ncommand = \"api\"\n\n[mcp_servers.old]\ncommand = \"old\"\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dest, err := NewDestination("codex", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eng, err := engine.NewEngine(engine.WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	for name, command := range map[string]string{"api": "api", "web": "web"} {
+		if err := eng.AddServer(name, engine.ServerConfig{Transport: "stdio", Command: command}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := eng.SyncTo(context.Background(), dest, engine.SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncTo failed: %v", err)
+	}
+	changes := make(map[string]string)
+	for _, change := range result.Changes {
+		changes[change.Server] = change.Type
+	}
+	if len(changes) != 2 || changes["web"] != engine.ChangeTypeAdd || changes["old"] != engine.ChangeTypeDelete {
+		t.Errorf("Expected web added and old removed, got %+v", result.Changes)
+	}
+	if result.ServersAdded != 1 || result.ServersRemoved != 1 || result.ServersWritten != 2 {
+		t.Errorf("Unexpected counts: added %d, removed %d, written %d", result.ServersAdded, result.ServersRemoved, result.ServersWritten)
+	}
+}
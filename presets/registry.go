@@ -0,0 +1,84 @@
+package presets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// presetsMu guards registry
+var presetsMu sync.RWMutex
+
+// RegisterPreset adds a preset, replacing any preset with the same name
+func RegisterPreset(preset Preset) error {
+	if preset.Name == "" {
+		return fmt.Errorf("preset name is required")
+	}
+	if preset.DefaultPath == "" && preset.ProjectFile == "" {
+		return fmt.Errorf("preset %s needs a DefaultPath or ProjectFile", preset.Name)
+	}
+	if preset.NamePattern != "" {
+		if _, err := regexp.Compile(preset.NamePattern); err != nil {
+			return fmt.Errorf("preset %s has an invalid NamePattern: %w", preset.Name, err)
+		}
+	}
+
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	registry[preset.Name] = preset
+	return nil
+}
+
+// GetPreset returns a registered preset
+func GetPreset(name string) (Preset, bool) {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	preset, ok := registry[name]
+	return preset, ok
+}
+
+// ListPresets returns the names of all registered presets, sorted
+func ListPresets() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Installed reports whether the preset's client appears to be installed: one
+// of DetectPaths exists, or the directory of DefaultPath does. Per-project
+// presets are always available.
+func (p Preset) Installed() bool {
+	if p.DefaultPath == "" && p.ProjectFile != "" {
+		return true
+	}
+
+	paths := p.DetectPaths
+	if len(paths) == 0 {
+		paths = []string{filepath.Dir(p.DefaultPath)}
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(expandPath(path)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectInstalled reports for each registered preset whether its client is installed
+func DetectInstalled() map[string]bool {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	installed := make(map[string]bool, len(registry))
+	for name, preset := range registry {
+		installed[name] = preset.Installed()
+	}
+	return installed
+}
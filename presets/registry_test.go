@@ -0,0 +1,99 @@
+package presets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// registerTestPreset registers a preset for the length of a test
+func registerTestPreset(t *testing.T, preset Preset) {
+	t.Helper()
+	if err := RegisterPreset(preset); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		presetsMu.Lock()
+		defer presetsMu.Unlock()
+		delete(registry, preset.Name)
+	})
+}
+
+func TestRegisterPreset(t *testing.T) {
+	invalid := []Preset{
+		{DefaultPath: "~/.editor/mcp.json"},
+		{Name: "no-path"},
+		{Name: "bad-pattern", DefaultPath: "~/.editor/mcp.json", NamePattern: "("},
+	}
+	for _, preset := range invalid {
+		if err := RegisterPreset(preset); err == nil {
+			t.Errorf("Expected %+v to be refused", preset)
+		}
+	}
+
+	registerTestPreset(t, Preset{Name: "zz-editor", Description: "Editor", DefaultPath: "~/.editor/mcp.json", ConfigFormat: "flat"})
+	preset, ok := GetPreset("zz-editor")
+	if !ok || preset.Description != "Editor" {
+		t.Fatalf("Expected the registered preset, got %+v, %v", preset, ok)
+	}
+	if _, ok := GetPreset("missing"); ok {
+		t.Error("Expected an unknown preset not to be found")
+	}
+
+	// Registering again replaces the preset
+	registerTestPreset(t, Preset{Name: "zz-editor", Description: "Editor 2", DefaultPath: "~/.editor/mcp.json"})
+	if preset, _ := GetPreset("zz-editor"); preset.Description != "Editor 2" {
+		t.Errorf("Expected the preset to be replaced, got %q", preset.Description)
+	}
+
+	names := ListPresets()
+	if len(names) < 2 || names[len(names)-1] != "zz-editor" {
+		t.Errorf("Expected sorted names ending with zz-editor, got %v", names)
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("Expected sorted names, got %v", names)
+		}
+	}
+	if _, err := NewDestination("zz-editor"); err != nil {
+		t.Errorf("Expected a destination for the registered preset: %v", err)
+	}
+
+	// The deprecated CommonPresets still lists built-in and registered presets
+	if _, ok := CommonPresets["claude"]; !ok {
+		t.Error("Expected CommonPresets to hold the built-in presets")
+	}
+	if preset := CommonPresets["zz-editor"]; preset.Description != "Editor 2" {
+		t.Errorf("Expected CommonPresets to hold the registered preset, got %+v", preset)
+	}
+}
+
+func TestPresetInstalled(t *testing.T) {
+	dir := t.TempDir()
+	installed := Preset{Name: "zz-installed", DefaultPath: filepath.Join(dir, "installed", "mcp.json")}
+	if err := os.MkdirAll(filepath.Join(dir, "installed"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	detected := Preset{
+		Name:        "zz-detected",
+		DefaultPath: filepath.Join(dir, "missing", "mcp.json"),
+		DetectPaths: []string{filepath.Join(dir, "nowhere"), dir},
+	}
+	missing := Preset{Name: "zz-missing", DefaultPath: filepath.Join(dir, "missing", "mcp.json")}
+	project := Preset{Name: "zz-project", ProjectFile: ".editor/mcp.json"}
+
+	for preset, want := range map[*Preset]bool{&installed: true, &detected: true, &missing: false, &project: true} {
+		if got := preset.Installed(); got != want {
+			t.Errorf("%s: Installed() = %v, want %v", preset.Name, got, want)
+		}
+		registerTestPreset(t, *preset)
+	}
+
+	all := DetectInstalled()
+	if !all["zz-installed"] || !all["zz-detected"] || all["zz-missing"] || !all["zz-project"] {
+		t.Errorf("Unexpected detection results: %v", all)
+	}
+	if len(all) != len(ListPresets()) {
+		t.Errorf("Expected a result for every preset, got %d of %d", len(all), len(ListPresets()))
+	}
+}
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,12 @@ import (
 	"time"
 )
 
+// projectScanSource is the Internal.Source of servers read from a project's MCP config file
+const projectScanSource = "project-scan"
+
+// projectMCPConfigFiles are the project-level MCP config files, in priority order
+var projectMCPConfigFiles = []string{"mcp.json", ".mcp.json", "mcp-config.json", ".mcp/config.json"}
+
 // ScanForProjects scans the given paths for MCP projects using the provided detector
 func (e *engineImpl) ScanForProjects(paths []string, detector ProjectDetector) ([]*ProjectConfig, error) {
 	e.mu.RLock()
@@ -141,7 +148,17 @@ func (e *engineImpl) RegisterProject(path string, config ProjectConfig) error {
 	e.config.Settings.Projects[expandedPath] = config
 
 	// Save configuration
-	return e.saveConfigNoLock()
+	if err := e.saveConfigNoLock(); err != nil {
+		return err
+	}
+
+	// Pick up the project in auto-sync; refreshProjectWatches checks that
+	// auto-sync is running under its own lock
+	if e.autoSync != nil {
+		go e.autoSync.refreshProjectWatches()
+	}
+
+	return nil
 }
 
 // GetProjectConfig retrieves a project configuration by path
@@ -178,24 +195,190 @@ func (e *engineImpl) ListProjects() ([]*ProjectInfo, error) {
 		return projects, nil
 	}
 
-	// Convert projects to ProjectInfo
+	// Convert projects to ProjectInfo, using listings cached for this config
 	for path, config := range e.config.Settings.Projects {
+		if info, ok := e.loadProjectListing(path); ok {
+			projects = append(projects, info)
+			continue
+		}
+
 		serverNames := make([]string, 0, len(config.Servers))
 		for name := range config.Servers {
 			serverNames = append(serverNames, name)
 		}
 
-		projects = append(projects, &ProjectInfo{
+		info := &ProjectInfo{
 			Name:        config.Name,
 			Path:        path,
 			ServerCount: len(config.Servers),
 			Servers:     serverNames,
-		})
+		}
+		e.saveProjectListing(path, info)
+		projects = append(projects, info)
 	}
 
 	return projects, nil
 }
 
+// SyncProject syncs a registered project's servers to the project's destinations.
+// Each destination receives the global servers plus the project's own servers.
+func (e *engineImpl) SyncProject(ctx context.Context, path string, options SyncOptions) (*MultiSyncResult, error) {
+	expandedPath := expandPath(path)
+
+	e.mu.RLock()
+	project, exists := e.config.Settings.Projects[expandedPath]
+	if !exists {
+		e.mu.RUnlock()
+		return nil, fmt.Errorf("project not found: %s", expandedPath)
+	}
+	config := e.projectSyncConfig(project)
+
+	var dests []Destination
+	var missing []string
+	for _, name := range project.Destinations {
+		if dest, ok := e.destinations[name]; ok {
+			if pd, ok := dest.(ProjectDestination); ok {
+				dest = pd.ForProject(expandedPath)
+			}
+			dests = append(dests, dest)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	e.mu.RUnlock()
+
+	start := time.Now()
+	result := &MultiSyncResult{
+		Results: make([]SyncResult, 0, len(project.Destinations)),
+	}
+
+	for _, name := range missing {
+		result.Results = append(result.Results, SyncResult{
+			Destination: name,
+			Success:     false,
+			Errors: []SyncError{{
+				Error:       fmt.Sprintf("destination %q not found", name),
+				Recoverable: false,
+			}},
+			Timestamp: time.Now(),
+		})
+		result.FailureCount++
+	}
+
+	for _, dest := range dests {
+		syncResult, err := e.syncConfigTo(ctx, dest, config, options)
+		if err != nil || !syncResult.Success {
+			result.FailureCount++
+		} else {
+			result.SuccessCount++
+		}
+		result.Results = append(result.Results, *syncResult)
+	}
+
+	result.TotalDuration = time.Since(start)
+
+	e.eventBus.emit(EventProjectSynced, ConfigChange{
+		Type:      "project-synced",
+		Name:      expandedPath,
+		Timestamp: time.Now(),
+		Source:    "sync",
+		Details: map[string]interface{}{
+			"successCount": result.SuccessCount,
+			"failureCount": result.FailureCount,
+		},
+	})
+
+	return result, nil
+}
+
+// projectSyncConfig builds the config synced to a project's destinations (caller must hold lock)
+func (e *engineImpl) projectSyncConfig(project ProjectConfig) *Config {
+	config := &Config{
+		Version:  e.config.Version,
+		Servers:  make(map[string]ServerWithMetadata, len(e.config.Servers)+len(project.Servers)),
+		Settings: e.config.Settings,
+		Metadata: make(map[string]interface{}),
+	}
+
+	for name, server := range e.config.Servers {
+		config.Servers[name] = server
+	}
+	// Project servers win over global servers with the same name
+	for name, server := range project.Servers {
+		config.Servers[name] = server
+	}
+
+	for k, v := range e.config.Metadata {
+		config.Metadata[k] = v
+	}
+	if inputs, ok := project.Metadata["inputs"]; ok {
+		config.Metadata["inputs"] = inputs
+	}
+
+	return config
+}
+
+// reloadProject re-parses a project's MCP config file and updates the stored ProjectConfig
+func (e *engineImpl) reloadProject(path string) (*ProjectConfig, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	project, exists := e.config.Settings.Projects[path]
+	if !exists {
+		return nil, fmt.Errorf("project not found: %s", path)
+	}
+
+	// Parse into a copy so the stored project is untouched on errors
+	parsed := project
+	parsed.Servers = make(map[string]ServerWithMetadata)
+	parsed.Metadata = make(map[string]interface{}, len(project.Metadata))
+	for k, v := range project.Metadata {
+		parsed.Metadata[k] = v
+	}
+	delete(parsed.Metadata, "inputs")
+
+	// A missing file simply leaves the project without file servers
+	for _, configFile := range projectMCPConfigFiles {
+		configPath := filepath.Join(path, configFile)
+		if _, err := os.Stat(configPath); err != nil {
+			continue
+		}
+		if err := parseProjectMCPConfig(configPath, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+		}
+		break
+	}
+
+	// Merge into the existing servers: servers that didn't come from the
+	// file are kept, and file servers keep whether they are enabled
+	for name, server := range project.Servers {
+		fileServer, inFile := parsed.Servers[name]
+		switch {
+		case inFile:
+			fileServer.Internal.Enabled = server.Internal.Enabled
+			parsed.Servers[name] = fileServer
+		case server.Internal.Source != projectScanSource:
+			parsed.Servers[name] = server
+		}
+	}
+	project = parsed
+
+	e.config.Settings.Projects[path] = project
+	if err := e.saveConfigNoLock(); err != nil {
+		return nil, err
+	}
+
+	e.eventBus.emit(EventProjectUpdated, ConfigChange{
+		Type:      "project-updated",
+		Name:      path,
+		Timestamp: time.Now(),
+		Source:    "auto-sync",
+		Details:   map[string]interface{}{"servers": len(project.Servers)},
+	})
+
+	return &project, nil
+}
+
 // DefaultProjectDetector provides a basic implementation of ProjectDetector
 type DefaultProjectDetector struct {
 	// ConfigFiles are the files that indicate a project root
@@ -215,6 +398,7 @@ func NewDefaultProjectDetector() *DefaultProjectDetector {
 			"build.gradle",
 			".project",
 			"mcp.json",
+			".mcp.json",
 			"mcp-config.json",
 			".mcp",
 		},
@@ -246,9 +430,7 @@ func (d *DefaultProjectDetector) DetectProject(path string) (*ProjectConfig, err
 	}
 
 	// Try to detect MCP configuration files
-	mcpConfigs := []string{"mcp.json", "mcp-config.json", ".mcp/config.json"}
-
-	for _, configFile := range mcpConfigs {
+	for _, configFile := range projectMCPConfigFiles {
 		configPath := filepath.Join(path, configFile)
 		if _, err := os.Stat(configPath); err == nil {
 			// Found MCP config, try to parse it
@@ -267,6 +449,11 @@ func (d *DefaultProjectDetector) DetectProject(path string) (*ProjectConfig, err
 
 // parseMCPConfig parses an MCP configuration file and adds servers to the project
 func (d *DefaultProjectDetector) parseMCPConfig(configPath string, project *ProjectConfig) error {
+	return parseProjectMCPConfig(configPath, project)
+}
+
+// parseProjectMCPConfig parses a project MCP configuration file into the project
+func parseProjectMCPConfig(configPath string, project *ProjectConfig) error {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return err
@@ -284,7 +471,7 @@ func (d *DefaultProjectDetector) parseMCPConfig(configPath string, project *Proj
 			ServerConfig: server.ServerConfig,
 			Internal: InternalMetadata{
 				Enabled:         true,
-				Source:          "project-scan",
+				Source:          projectScanSource,
 				ProjectPath:     project.Path,
 				ProjectSpecific: true,
 				LastModified:    time.Now(),
@@ -301,3 +488,9 @@ func (d *DefaultProjectDetector) parseMCPConfig(configPath string, project *Proj
 
 	return nil
 }
+
+// isProjectOnly reports whether a destination can only be synced through a project
+func isProjectOnly(dest Destination) bool {
+	pd, ok := dest.(ProjectDestination)
+	return ok && pd.ProjectOnly()
+}
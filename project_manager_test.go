@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -161,3 +162,142 @@ go 1.21
 		t.Errorf("Expected project path '%s', got '%s'", tempDir, project.Path)
 	}
 }
+
+// projectFileDestination writes a file inside each project it is synced to
+type projectFileDestination struct {
+	*testDestination
+	file string
+}
+
+func (pd *projectFileDestination) ForProject(projectPath string) Destination {
+	return &projectFileDestination{
+		testDestination: &testDestination{
+			id:     pd.id,
+			path:   filepath.Join(projectPath, pd.file),
+			synced: make(chan bool, 1),
+		},
+		file: pd.file,
+	}
+}
+
+func (pd *projectFileDestination) ProjectOnly() bool {
+	return pd.path == ""
+}
+
+func TestSyncProjectDestination(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "project-dest-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	engine, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	if err := engine.AddServer("test-server", ServerConfig{
+		Transport: "stdio",
+		Command:   "test-command",
+	}); err != nil {
+		t.Fatalf("Failed to add server: %v", err)
+	}
+
+	workspace := &projectFileDestination{
+		testDestination: &testDestination{id: "workspace", synced: make(chan bool, 1)},
+		file:            "workspace.json",
+	}
+	global := &testDestination{
+		id:     "global",
+		path:   filepath.Join(tempDir, "global.json"),
+		synced: make(chan bool, 1),
+	}
+	if err := engine.RegisterDestination("workspace", workspace); err != nil {
+		t.Fatalf("Failed to register destination: %v", err)
+	}
+	if err := engine.RegisterDestination("global", global); err != nil {
+		t.Fatalf("Failed to register destination: %v", err)
+	}
+
+	// Project-only destinations are left out when no destinations are named
+	dests, err := engine.(*engineImpl).jobDestinations(ScheduledJob{})
+	if err != nil {
+		t.Fatalf("jobDestinations failed: %v", err)
+	}
+	if len(dests) != 1 || dests[0].GetID() != "global" {
+		t.Errorf("Expected only the global destination, got %d destinations", len(dests))
+	}
+
+	if err := engine.RegisterProject(tempDir, ProjectConfig{
+		Name:         "test-project",
+		Destinations: []string{"workspace"},
+	}); err != nil {
+		t.Fatalf("Failed to register project: %v", err)
+	}
+
+	result, err := engine.SyncProject(context.Background(), tempDir, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncProject failed: %v", err)
+	}
+	if result.FailureCount != 0 {
+		t.Fatalf("Expected no failures, got %+v", result.Results)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, "workspace.json")); err != nil {
+		t.Errorf("Expected project file to be written: %v", err)
+	}
+}
+
+func TestReloadProjectMergesServers(t *testing.T) {
+	projectDir := t.TempDir()
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	impl := e.(*engineImpl)
+
+	if err := e.RegisterProject(projectDir, ProjectConfig{
+		Name: "project",
+		Servers: map[string]ServerWithMetadata{
+			"manual": {ServerConfig: ServerConfig{Transport: "stdio", Command: "manual"}, Internal: InternalMetadata{Enabled: true}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeConfig := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(projectDir, ".mcp.json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig(`{"mcpServers": {"api": {"command": "api"}, "web": {"command": "web"}}}`)
+	if _, err := impl.reloadProject(projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Disable a file server, then change the file
+	impl.mu.Lock()
+	project := impl.config.Settings.Projects[projectDir]
+	web := project.Servers["web"]
+	web.Internal.Enabled = false
+	project.Servers["web"] = web
+	impl.mu.Unlock()
+
+	writeConfig(`{"mcpServers": {"web": {"command": "web-v2"}}}`)
+	reloaded, err := impl.reloadProject(projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := reloaded.Servers["manual"]; !ok {
+		t.Error("Expected the registered server to be kept")
+	}
+	if _, ok := reloaded.Servers["api"]; ok {
+		t.Error("Expected the server removed from the file to be removed")
+	}
+	if server := reloaded.Servers["web"]; server.Command != "web-v2" || server.Internal.Enabled {
+		t.Errorf("Expected web to be updated and stay disabled, got %+v", server)
+	}
+}
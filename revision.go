@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ContentRevision returns the revision of a stored value: a hash of its
+// content, so every backend can compute it the same way. Missing keys have
+// the empty revision.
+func ContentRevision(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ConflictError is returned by conditional writes when the key changed since
+// it was read, including config saves that would overwrite another writer's
+// changes. Reload the config and retry to resolve it.
+type ConflictError struct {
+	Key      string
+	Expected string // Revision the writer read
+	Actual   string // Revision found in storage
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflict writing %s: expected revision %s, found %s",
+		e.Key, revisionString(e.Expected), revisionString(e.Actual))
+}
+
+// checkRevision returns a ConflictError unless current has the expected revision
+func checkRevision(key string, current []byte, exists bool, expected string) error {
+	actual := ""
+	if exists {
+		actual = ContentRevision(current)
+	}
+	if actual != expected {
+		return &ConflictError{Key: key, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+func revisionString(revision string) string {
+	if revision == "" {
+		return "none"
+	}
+	return revision
+}
@@ -0,0 +1,151 @@
+package engine
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRevisionedStorage(t *testing.T) {
+	fileStorage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	boltStorage, err := NewBoltStorage(filepath.Join(t.TempDir(), "agent-master.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer boltStorage.Close()
+
+	for name, storage := range map[string]RevisionedStorage{
+		"memory": NewMemoryStorage(),
+		"file":   fileStorage,
+		"bolt":   boltStorage,
+	} {
+		t.Run(name, func(t *testing.T) {
+			// The empty revision creates a key only if it doesn't exist
+			revision, err := storage.WriteIfRevision("config", []byte(`{"a":1}`), "")
+			if err != nil {
+				t.Fatalf("WriteIfRevision failed: %v", err)
+			}
+			if _, err := storage.WriteIfRevision("config", []byte(`{"a":2}`), ""); !errors.As(err, new(*ConflictError)) {
+				t.Errorf("Expected a ConflictError, got %v", err)
+			}
+
+			data, read, err := storage.ReadRevision("config")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if read != revision || string(data) != `{"a":1}` {
+				t.Errorf("Expected revision %s with the first write, got %s: %s", revision, read, data)
+			}
+
+			// A write by someone else invalidates the revision
+			if err := storage.(Storage).Write("config", []byte(`{"b":1}`)); err != nil {
+				t.Fatal(err)
+			}
+			_, err = storage.WriteIfRevision("config", []byte(`{"a":2}`), revision)
+			var conflict *ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("Expected a ConflictError, got %v", err)
+			}
+			if conflict.Expected != revision || conflict.Actual != ContentRevision([]byte(`{"b":1}`)) {
+				t.Errorf("Unexpected conflict revisions: %+v", conflict)
+			}
+
+			if _, err := storage.WriteIfRevision("config", []byte(`{"a":2}`), conflict.Actual); err != nil {
+				t.Errorf("Expected write at the current revision to succeed: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigSaveConflict(t *testing.T) {
+	// Two processes on one directory; B doesn't notice A's save in time
+	storageDir := t.TempDir()
+	engineA, err := NewEngine(WithFileStorage(storageDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageB, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageB.SetWatchDebounce(time.Hour)
+	defer storageB.Close()
+	engineB, err := NewEngine(WithStorage(storageB))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engineA.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// B would overwrite A's server, so its save fails
+	err = engineB.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"})
+	if !errors.As(err, new(*ConflictError)) {
+		t.Fatalf("Expected a ConflictError, got %v", err)
+	}
+
+	// Reloading drops the unsaved change and picks up A's
+	if err := engineB.LoadConfig(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engineB.GetServer("b"); err == nil {
+		t.Error("Expected the unsaved server to be dropped")
+	}
+	if err := engineB.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"}); err != nil {
+		t.Fatalf("AddServer after reload failed: %v", err)
+	}
+
+	config, err := engineB.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Servers) != 2 {
+		t.Errorf("Expected both servers, got %d", len(config.Servers))
+	}
+}
+
+func TestConfigReloadFromStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	engineA, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engineB, err := NewEngine(WithStorage(storage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan struct{}, 1)
+	engineB.OnConfigChange(func(change ConfigChange) {
+		if change.Type == "config-reloaded" {
+			reloaded <- struct{}{}
+		}
+	})
+
+	if err := engineA.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("Expected engine B to reload the config")
+	}
+	if _, err := engineB.GetServer("a"); err != nil {
+		t.Errorf("Expected engine B to see the new server: %v", err)
+	}
+
+	// B's own saves don't reload it
+	if err := engineB.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"}); err != nil {
+		t.Fatalf("AddServer failed: %v", err)
+	}
+	select {
+	case <-reloaded:
+		t.Error("Expected no reload for engine B's own save")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
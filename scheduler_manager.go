@@ -0,0 +1,489 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// schedulerManager runs time-based jobs configured under Settings.Schedule
+type schedulerManager struct {
+	engine    *engineImpl
+	isRunning bool
+	stopChan  chan struct{}
+	wake      chan struct{}
+	jobs      map[string]*scheduledJob
+	mu        sync.Mutex
+	wg        sync.WaitGroup
+}
+
+// scheduledJob is a parsed job with its runtime state
+type scheduledJob struct {
+	job      ScheduledJob
+	schedule cron.Schedule
+	state    jobState
+	running  bool
+}
+
+// jobState is the per-job state persisted in storage
+type jobState struct {
+	Schedule     string        `json:"schedule"`
+	NextRun      time.Time     `json:"nextRun,omitempty"`
+	LastRun      time.Time     `json:"lastRun,omitempty"`
+	LastDuration time.Duration `json:"lastDuration,omitempty"`
+	LastResult   string        `json:"lastResult,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+	RunCount     int           `json:"runCount"`
+}
+
+// newSchedulerManager creates a new scheduler manager
+func newSchedulerManager(engine *engineImpl) *schedulerManager {
+	return &schedulerManager{
+		engine: engine,
+		wake:   make(chan struct{}, 1),
+		jobs:   make(map[string]*scheduledJob),
+	}
+}
+
+// JobNotFoundError is returned when no scheduled job has the requested name
+type JobNotFoundError struct {
+	Name string
+}
+
+func (e *JobNotFoundError) Error() string {
+	return fmt.Sprintf("scheduled job %q not found", e.Name)
+}
+
+// parseSchedule parses a cron expression. Standard five-field expressions and
+// descriptors such as "@daily", "@weekly" and "@every 1h" are supported.
+func parseSchedule(expr string) (cron.Schedule, error) {
+	return cron.ParseStandard(expr)
+}
+
+// StartScheduler starts running scheduled jobs
+func (e *engineImpl) StartScheduler() error {
+	return e.scheduler.Start()
+}
+
+// StopScheduler stops running scheduled jobs
+func (e *engineImpl) StopScheduler() error {
+	return e.scheduler.Stop()
+}
+
+// ListScheduledJobs returns all configured jobs with their next and last runs
+func (e *engineImpl) ListScheduledJobs() ([]*ScheduledJobStatus, error) {
+	return e.scheduler.List()
+}
+
+// RunScheduledJob runs a configured job immediately
+func (e *engineImpl) RunScheduledJob(ctx context.Context, name string) (*ScheduledJobStatus, error) {
+	return e.scheduler.Run(ctx, name)
+}
+
+// Start starts the scheduler loop
+func (sm *schedulerManager) Start() error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if sm.isRunning {
+		return fmt.Errorf("scheduler is already running")
+	}
+
+	if err := sm.reloadJobsLocked(); err != nil {
+		return err
+	}
+
+	sm.isRunning = true
+	sm.stopChan = make(chan struct{})
+
+	sm.wg.Add(1)
+	go func() {
+		defer sm.wg.Done()
+		sm.loop()
+	}()
+
+	return nil
+}
+
+// Stop stops the scheduler loop and waits for running jobs to finish
+func (sm *schedulerManager) Stop() error {
+	sm.mu.Lock()
+	if !sm.isRunning {
+		sm.mu.Unlock()
+		return fmt.Errorf("scheduler is not running")
+	}
+
+	close(sm.stopChan)
+	sm.isRunning = false
+	sm.mu.Unlock()
+
+	sm.wg.Wait()
+	return nil
+}
+
+// notify asks the scheduler loop to re-read its jobs from the engine config
+func (sm *schedulerManager) notify() {
+	select {
+	case sm.wake <- struct{}{}:
+	default:
+	}
+}
+
+// List returns the status of all configured jobs sorted by name
+func (sm *schedulerManager) List() ([]*ScheduledJobStatus, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	// Pick up config changes even when the loop isn't running
+	if !sm.isRunning {
+		if err := sm.reloadJobsLocked(); err != nil {
+			return nil, err
+		}
+	}
+
+	jobs := make([]*ScheduledJobStatus, 0, len(sm.jobs))
+	for _, sj := range sm.jobs {
+		jobs = append(jobs, sj.status())
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Name < jobs[j].Name
+	})
+
+	return jobs, nil
+}
+
+// Run runs a job immediately, regardless of its schedule
+func (sm *schedulerManager) Run(ctx context.Context, name string) (*ScheduledJobStatus, error) {
+	sm.mu.Lock()
+	if !sm.isRunning {
+		if err := sm.reloadJobsLocked(); err != nil {
+			sm.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	sj, ok := sm.jobs[name]
+	if !ok {
+		sm.mu.Unlock()
+		return nil, &JobNotFoundError{Name: name}
+	}
+	if sj.running {
+		sm.mu.Unlock()
+		return nil, fmt.Errorf("scheduled job %q is already running", name)
+	}
+	sj.running = true
+	job := sj.job
+	sm.mu.Unlock()
+
+	return sm.execute(ctx, job), nil
+}
+
+// loop waits for the earliest due job and runs everything that is due
+func (sm *schedulerManager) loop() {
+	for {
+		sm.mu.Lock()
+		next := sm.earliestRunLocked()
+		sm.mu.Unlock()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			fire = timer.C
+		}
+
+		select {
+		case <-sm.stopChan:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case <-sm.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+			sm.mu.Lock()
+			if err := sm.reloadJobsLocked(); err != nil {
+				sm.engine.eventBus.emit(EventError, err)
+			}
+			sm.mu.Unlock()
+
+		case <-fire:
+			sm.runDue(time.Now())
+		}
+	}
+}
+
+// runDue starts every enabled job whose next run has passed
+func (sm *schedulerManager) runDue(now time.Time) {
+	sm.mu.Lock()
+	var due []ScheduledJob
+	for _, sj := range sm.jobs {
+		if sj.job.Disabled || sj.running || sj.state.NextRun.After(now) {
+			continue
+		}
+		sj.running = true
+		due = append(due, sj.job)
+	}
+	sm.mu.Unlock()
+
+	for _, job := range due {
+		sm.wg.Add(1)
+		go func(job ScheduledJob) {
+			defer sm.wg.Done()
+			sm.execute(context.Background(), job)
+		}(job)
+	}
+}
+
+// execute runs a job, records the outcome, persists the new state and
+// returns the job's status after the run
+func (sm *schedulerManager) execute(ctx context.Context, job ScheduledJob) *ScheduledJobStatus {
+	start := time.Now()
+	result, err := sm.engine.runJobAction(ctx, job)
+
+	sm.mu.Lock()
+	// A reload during the run may have replaced or removed the job
+	sj, ok := sm.jobs[job.Name]
+	if !ok {
+		sj = &scheduledJob{job: job}
+	}
+	sj.running = false
+	sj.state.LastRun = start
+	sj.state.LastDuration = time.Since(start)
+	sj.state.LastResult = result
+	sj.state.LastError = ""
+	if err != nil {
+		sj.state.LastError = err.Error()
+	}
+	sj.state.RunCount++
+	if sj.schedule != nil {
+		sj.state.NextRun = sj.schedule.Next(time.Now())
+	}
+	status := sj.status()
+	saveErr := sm.saveStateLocked()
+	sm.mu.Unlock()
+
+	// The loop skips running jobs when picking its next wakeup, so tell it
+	// this one is idle again
+	sm.notify()
+
+	if saveErr != nil {
+		sm.engine.eventBus.emit(EventWarning, fmt.Sprintf("failed to persist scheduler state: %v", saveErr))
+	}
+
+	change := ConfigChange{
+		Type:      "job-completed",
+		Name:      job.Name,
+		Timestamp: time.Now(),
+		Source:    "scheduler",
+		Details: map[string]interface{}{
+			"action": job.Action,
+			"result": result,
+		},
+	}
+	if err != nil {
+		change.Type = "job-failed"
+		change.Details["error"] = err.Error()
+		sm.engine.eventBus.emit(EventJobFailed, change)
+		sm.engine.eventBus.emit(EventError, fmt.Errorf("scheduled job %s failed: %w", job.Name, err))
+		return status
+	}
+	sm.engine.eventBus.emit(EventJobCompleted, change)
+	return status
+}
+
+// reloadJobsLocked syncs the job table with the engine config and persisted
+// state (caller must hold sm.mu)
+func (sm *schedulerManager) reloadJobsLocked() error {
+	sm.engine.mu.RLock()
+	configured := append([]ScheduledJob(nil), sm.engine.config.Settings.Schedule.Jobs...)
+	sm.engine.mu.RUnlock()
+
+	persisted := make(map[string]jobState)
+	if err := LoadJSON(sm.engine.getStorage(), Keys.SchedulerState(), &persisted); err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to load scheduler state: %w", err)
+	}
+
+	now := time.Now()
+	jobs := make(map[string]*scheduledJob, len(configured))
+	for _, job := range configured {
+		if job.Name == "" {
+			return fmt.Errorf("scheduled job is missing a name")
+		}
+		if _, dup := jobs[job.Name]; dup {
+			return fmt.Errorf("duplicate scheduled job %q", job.Name)
+		}
+		if !isValidJobAction(job.Action) {
+			return fmt.Errorf("scheduled job %q has unknown action %q", job.Name, job.Action)
+		}
+
+		schedule, err := parseSchedule(job.Schedule)
+		if err != nil {
+			return fmt.Errorf("scheduled job %q has invalid schedule %q: %w", job.Name, job.Schedule, err)
+		}
+
+		sj := &scheduledJob{job: job, schedule: schedule}
+		if existing, ok := sm.jobs[job.Name]; ok {
+			sj.state = existing.state
+			sj.running = existing.running
+		} else if state, ok := persisted[job.Name]; ok {
+			sj.state = state
+		}
+
+		// Recompute the next run for new jobs or changed schedules. A persisted
+		// next run in the past means the run was missed and is due now.
+		if sj.state.Schedule != job.Schedule || sj.state.NextRun.IsZero() {
+			sj.state.Schedule = job.Schedule
+			sj.state.NextRun = schedule.Next(now)
+		}
+
+		jobs[job.Name] = sj
+	}
+
+	sm.jobs = jobs
+	return sm.saveStateLocked()
+}
+
+// earliestRunLocked returns the earliest next run of enabled idle jobs (caller must hold sm.mu)
+func (sm *schedulerManager) earliestRunLocked() time.Time {
+	var earliest time.Time
+	for _, sj := range sm.jobs {
+		if sj.job.Disabled || sj.running {
+			continue
+		}
+		if earliest.IsZero() || sj.state.NextRun.Before(earliest) {
+			earliest = sj.state.NextRun
+		}
+	}
+	return earliest
+}
+
+// saveStateLocked persists next-run state for all jobs (caller must hold sm.mu)
+func (sm *schedulerManager) saveStateLocked() error {
+	state := make(map[string]jobState, len(sm.jobs))
+	for name, sj := range sm.jobs {
+		state[name] = sj.state
+	}
+	return SaveJSON(sm.engine.getStorage(), Keys.SchedulerState(), state)
+}
+
+// status converts the job to its public status
+func (sj *scheduledJob) status() *ScheduledJobStatus {
+	status := &ScheduledJobStatus{
+		ScheduledJob: sj.job,
+		LastRun:      sj.state.LastRun,
+		LastDuration: sj.state.LastDuration,
+		LastResult:   sj.state.LastResult,
+		LastError:    sj.state.LastError,
+		RunCount:     sj.state.RunCount,
+	}
+	if !sj.job.Disabled {
+		status.NextRun = sj.state.NextRun
+	}
+	return status
+}
+
+// isValidJobAction checks if an action is supported by the scheduler
+func isValidJobAction(action string) bool {
+	switch action {
+	case JobActionSync, JobActionDriftCheck, JobActionBackup:
+		return true
+	default:
+		return false
+	}
+}
+
+// runJobAction performs a scheduled job's action and returns a short summary
+func (e *engineImpl) runJobAction(ctx context.Context, job ScheduledJob) (string, error) {
+	switch job.Action {
+	case JobActionBackup:
+		info, err := e.CreateBackup(fmt.Sprintf("scheduled backup (%s)", job.Name))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("created backup %s", info.ID), nil
+
+	case JobActionSync:
+		dests, err := e.jobDestinations(job)
+		if err != nil {
+			return "", err
+		}
+		result, err := e.SyncToMultiple(ctx, dests, SyncOptions{})
+		if err != nil {
+			return "", err
+		}
+		summary := fmt.Sprintf("synced %d of %d destinations", result.SuccessCount, len(dests))
+		if result.FailureCount > 0 {
+			return summary, fmt.Errorf("%d destinations failed to sync", result.FailureCount)
+		}
+		return summary, nil
+
+	case JobActionDriftCheck:
+		dests, err := e.jobDestinations(job)
+		if err != nil {
+			return "", err
+		}
+		drifted := 0
+		for _, dest := range dests {
+			preview, err := e.PreviewSync(dest)
+			if err != nil {
+				return "", fmt.Errorf("failed to preview %s: %w", dest.GetID(), err)
+			}
+			if len(preview.Changes) == 0 {
+				continue
+			}
+			drifted++
+			e.eventBus.emit(EventDriftDetected, ConfigChange{
+				Type:      "drift-detected",
+				Name:      dest.GetID(),
+				Timestamp: time.Now(),
+				Source:    "scheduler",
+				Details:   map[string]interface{}{"changes": len(preview.Changes), "job": job.Name},
+			})
+		}
+		return fmt.Sprintf("%d of %d destinations drifted", drifted, len(dests)), nil
+
+	default:
+		return "", fmt.Errorf("unknown job action: %s", job.Action)
+	}
+}
+
+// jobDestinations resolves a job's destinations, defaulting to all registered
+func (e *engineImpl) jobDestinations(job ScheduledJob) ([]Destination, error) {
+	if len(job.Destinations) == 0 {
+		registered := e.ListDestinations()
+		names := make([]string, 0, len(registered))
+		for name, dest := range registered {
+			if !isProjectOnly(dest) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		dests := make([]Destination, 0, len(names))
+		for _, name := range names {
+			dests = append(dests, registered[name])
+		}
+		if len(dests) == 0 {
+			return nil, fmt.Errorf("no destinations registered")
+		}
+		return dests, nil
+	}
+
+	dests := make([]Destination, 0, len(job.Destinations))
+	for _, name := range job.Destinations {
+		dest, err := e.GetDestination(name)
+		if err != nil {
+			return nil, err
+		}
+		dests = append(dests, dest)
+	}
+	return dests, nil
+}
@@ -0,0 +1,196 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunScheduledJob(t *testing.T) {
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.AddServer("server1", ServerConfig{Transport: "stdio", Command: "test1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := &mockDestination{id: "dest1"}
+	if err := e.RegisterDestination("dest1", dest); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := e.GetConfig()
+	config.Settings.Schedule = ScheduleSettings{
+		Jobs: []ScheduledJob{
+			{Name: "nightly-sync", Schedule: "0 2 * * *", Action: JobActionSync, Destinations: []string{"dest1"}},
+			{Name: "drift", Schedule: "@hourly", Action: JobActionDriftCheck},
+		},
+	}
+	if err := e.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var completed []ConfigChange
+	unsub := e.OnConfigChange(func(event ConfigChange) {
+		if event.Type == "job-completed" || event.Type == "drift-detected" {
+			mu.Lock()
+			completed = append(completed, event)
+			mu.Unlock()
+		}
+	})
+	defer unsub()
+
+	status, err := e.RunScheduledJob(context.Background(), "nightly-sync")
+	if err != nil {
+		t.Fatalf("RunScheduledJob failed: %v", err)
+	}
+
+	if dest.writeCount != 1 {
+		t.Errorf("Expected destination to be written once, got %d", dest.writeCount)
+	}
+	if status.RunCount != 1 {
+		t.Errorf("Expected RunCount 1, got %d", status.RunCount)
+	}
+	if status.LastError != "" {
+		t.Errorf("Unexpected job error: %s", status.LastError)
+	}
+	if !status.NextRun.After(time.Now()) {
+		t.Errorf("Expected next run in the future, got %v", status.NextRun)
+	}
+
+	// A fresh destination has drifted from the config
+	dest.exists = false
+	status, err = e.RunScheduledJob(context.Background(), "drift")
+	if err != nil {
+		t.Fatalf("RunScheduledJob failed: %v", err)
+	}
+	if status.LastResult != "1 of 1 destinations drifted" {
+		t.Errorf("Unexpected drift result: %q", status.LastResult)
+	}
+
+	// Give async event handlers a moment to run
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(completed) == 0 {
+		t.Error("Expected job events to be emitted")
+	}
+
+	var notFound *JobNotFoundError
+	if _, err := e.RunScheduledJob(context.Background(), "missing"); !errors.As(err, &notFound) {
+		t.Errorf("Expected JobNotFoundError for unknown job, got %v", err)
+	}
+}
+
+func TestSchedulerRunsDueJobs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "scheduler-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	e, err := NewEngine(WithFileStorage(tmpDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := e.GetConfig()
+	config.Settings.Schedule = ScheduleSettings{
+		Enabled: true,
+		Jobs: []ScheduledJob{
+			{Name: "frequent-backup", Schedule: "@every 1s", Action: JobActionBackup},
+		},
+	}
+	if err := e.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.StartScheduler(); err != nil {
+		t.Fatalf("StartScheduler failed: %v", err)
+	}
+	if err := e.StartScheduler(); err == nil {
+		t.Error("Expected error starting scheduler twice")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		jobs, err := e.ListScheduledJobs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) == 1 && jobs[0].RunCount >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Scheduled job did not run, jobs: %+v", jobs)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := e.StopScheduler(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := e.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) < 1 {
+		t.Errorf("Expected a scheduled backup, got %d", len(backups))
+	}
+
+	// A new engine on the same storage picks up the persisted state
+	e2, err := NewEngine(WithFileStorage(tmpDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e2.SetConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := e2.ListScheduledJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].RunCount < 1 || jobs[0].LastRun.IsZero() {
+		t.Errorf("Expected persisted job state, got %+v", jobs)
+	}
+}
+
+func TestSchedulerInvalidJobs(t *testing.T) {
+	tests := []struct {
+		name string
+		job  ScheduledJob
+	}{
+		{"bad schedule", ScheduledJob{Name: "bad", Schedule: "not a cron", Action: JobActionSync}},
+		{"bad action", ScheduledJob{Name: "bad", Schedule: "@daily", Action: "explode"}},
+		{"missing name", ScheduledJob{Schedule: "@daily", Action: JobActionBackup}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEngine(WithMemoryStorage())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			config, _ := e.GetConfig()
+			config.Settings.Schedule.Jobs = []ScheduledJob{tt.job}
+			if err := e.SetConfig(config); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := e.ListScheduledJobs(); err == nil {
+				t.Error("Expected error for invalid job")
+			}
+			if err := e.StartScheduler(); err == nil {
+				t.Error("Expected StartScheduler to fail for invalid job")
+			}
+		})
+	}
+}
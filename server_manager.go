@@ -192,35 +192,48 @@ func (e *engineImpl) ListServers(filter ServerFilter) ([]*ServerInfo, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
+	// Use the listing cached for this config, building it if needed
+	listing, ok := e.loadServerListing()
+	if !ok {
+		listing = make([]serverListingEntry, 0, len(e.config.Servers))
+		for name, server := range e.config.Servers {
+			listing = append(listing, serverListingEntry{
+				Info: ServerInfo{
+					Name:            name,
+					Config:          server.ServerConfig,
+					Transport:       server.Transport,
+					Enabled:         server.Internal.Enabled,
+					SyncTargetCount: len(server.Internal.SyncTargets),
+					LastModified:    server.Internal.LastModified,
+					HasErrors:       server.Internal.ErrorCount > 0,
+				},
+				Source: server.Internal.Source,
+			})
+		}
+		e.saveServerListing(listing)
+	}
+
 	var servers []*ServerInfo
 
-	for name, server := range e.config.Servers {
+	for i := range listing {
+		entry := &listing[i]
+
 		// Apply filters
-		if filter.Enabled != nil && server.Internal.Enabled != *filter.Enabled {
+		if filter.Enabled != nil && entry.Info.Enabled != *filter.Enabled {
 			continue
 		}
 
-		if filter.Transport != "" && server.Transport != filter.Transport {
+		if filter.Transport != "" && entry.Info.Transport != filter.Transport {
 			continue
 		}
 
-		if filter.Source != "" && server.Internal.Source != filter.Source {
+		if filter.Source != "" && entry.Source != filter.Source {
 			continue
 		}
 
 		// TODO: Implement other filters
 
-		info := &ServerInfo{
-			Name:            name,
-			Config:          server.ServerConfig,
-			Transport:       server.Transport,
-			Enabled:         server.Internal.Enabled,
-			SyncTargetCount: len(server.Internal.SyncTargets),
-			LastModified:    server.Internal.LastModified,
-			HasErrors:       server.Internal.ErrorCount > 0,
-		}
-
-		servers = append(servers, info)
+		servers = append(servers, &entry.Info)
 	}
 
 	return servers, nil
@@ -8,14 +8,21 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
-// FileStorage implements Storage interface using filesystem
+// FileStorage implements Storage interface using filesystem. Writes take an
+// advisory file lock per key, so several processes can share a directory,
+// and Watch sees changes from any of them through fsnotify.
 type FileStorage struct {
-	basePath string
-	mu       sync.RWMutex
-	watchers map[string][]func([]byte)
-	stopChan chan struct{}
+	basePath    string
+	lockTimeout time.Duration
+	debounce    time.Duration
+	mu          sync.RWMutex
+	watchers    map[string][]func([]byte)
+	seen        map[string]string // Last revision handlers saw per watched key
+	timers      map[string]*time.Timer
+	stopChan    chan struct{}
 }
 
 // NewFileStorage creates a new file-based storage
@@ -35,12 +42,24 @@ func NewFileStorage(basePath string) (*FileStorage, error) {
 	}
 
 	return &FileStorage{
-		basePath: basePath,
-		watchers: make(map[string][]func([]byte)),
-		stopChan: make(chan struct{}),
+		basePath:    basePath,
+		lockTimeout: DefaultLockTimeout,
+		debounce:    DefaultWatchDebounce,
+		watchers:    make(map[string][]func([]byte)),
+		seen:        make(map[string]string),
+		timers:      make(map[string]*time.Timer),
+		stopChan:    make(chan struct{}),
 	}, nil
 }
 
+// SetLockTimeout sets how long writes wait for another process holding a
+// key's lock
+func (fs *FileStorage) SetLockTimeout(timeout time.Duration) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.lockTimeout = timeout
+}
+
 // Read reads data from storage
 func (fs *FileStorage) Read(key string) ([]byte, error) {
 	fs.mu.RLock()
@@ -64,31 +83,85 @@ func (fs *FileStorage) Write(key string, data []byte) error {
 	defer fs.mu.Unlock()
 
 	path := fs.keyToPath(key)
+	lock, err := AcquireFileLock(path, fs.lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return fs.writeLocked(key, path, data)
+}
+
+// Update replaces the data of a key with the result of fn, holding the key's
+// lock from the read to the write so no other process can write in between.
+// fn receives nil if the key doesn't exist.
+func (fs *FileStorage) Update(key string, fn func([]byte) ([]byte, error)) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	path := fs.keyToPath(key)
+	lock, err := AcquireFileLock(path, fs.lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, err := fn(current)
+	if err != nil {
+		return err
+	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+	return fs.writeLocked(key, path, data)
+}
+
+// ReadRevision reads a key with its revision
+func (fs *FileStorage) ReadRevision(key string) ([]byte, string, error) {
+	data, err := fs.Read(key)
+	if err != nil {
+		return nil, "", err
 	}
+	return data, ContentRevision(data), nil
+}
+
+// WriteIfRevision writes a key if it is still at revision, holding the key's
+// lock so other processes can't write in between
+func (fs *FileStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
 
-	// Write atomically
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
+	path := fs.keyToPath(key)
+	lock, err := AcquireFileLock(path, fs.lockTimeout)
+	if err != nil {
+		return "", err
 	}
+	defer lock.Unlock()
 
-	if err := os.Rename(tmpPath, path); err != nil {
-		os.Remove(tmpPath) // Clean up
-		return fmt.Errorf("failed to rename file: %w", err)
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := checkRevision(key, current, err == nil, revision); err != nil {
+		return "", err
 	}
 
-	// Notify watchers
-	if handlers, ok := fs.watchers[key]; ok {
-		for _, handler := range handlers {
-			go handler(data)
-		}
+	if err := fs.writeLocked(key, path, data); err != nil {
+		return "", err
+	}
+	return ContentRevision(data), nil
+}
+
+// writeLocked writes a key; the caller holds fs.mu and the key's file lock
+func (fs *FileStorage) writeLocked(key, path string, data []byte) error {
+	// Write atomically, keeping symlinks and permissions
+	if err := WriteFileAtomic(path, data, DefaultFileMode); err != nil {
+		return err
 	}
 
+	fs.notifyLocked(key, data)
 	return nil
 }
 
@@ -98,6 +171,12 @@ func (fs *FileStorage) Delete(key string) error {
 	defer fs.mu.Unlock()
 
 	path := fs.keyToPath(key)
+	lock, err := AcquireFileLock(path, fs.lockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	if err := os.Remove(path); err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("key not found: %s", key)
@@ -105,6 +184,7 @@ func (fs *FileStorage) Delete(key string) error {
 		return err
 	}
 
+	fs.notifyLocked(key, nil)
 	return nil
 }
 
@@ -120,7 +200,7 @@ func (fs *FileStorage) List(prefix string) ([]string, error) {
 			return err
 		}
 
-		if info.IsDir() {
+		if info.IsDir() || isSidecarFile(info.Name()) {
 			return nil
 		}
 
@@ -141,35 +221,6 @@ func (fs *FileStorage) List(prefix string) ([]string, error) {
 	return keys, err
 }
 
-// Watch watches for changes to a key
-func (fs *FileStorage) Watch(key string, handler func([]byte)) (func(), error) {
-	fs.mu.Lock()
-	defer fs.mu.Unlock()
-
-	fs.watchers[key] = append(fs.watchers[key], handler)
-
-	// Return unsubscribe function
-	return func() {
-		fs.mu.Lock()
-		defer fs.mu.Unlock()
-
-		if handlers, ok := fs.watchers[key]; ok {
-			// Remove this handler
-			for i, h := range handlers {
-				if &h == &handler {
-					fs.watchers[key] = append(handlers[:i], handlers[i+1:]...)
-					break
-				}
-			}
-
-			// Clean up if no more handlers
-			if len(fs.watchers[key]) == 0 {
-				delete(fs.watchers, key)
-			}
-		}
-	}, nil
-}
-
 // GetBasePath returns the base path of the file storage
 func (fs *FileStorage) GetBasePath() string {
 	return fs.basePath
@@ -236,6 +287,35 @@ func (ms *MemoryStorage) Write(key string, data []byte) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
+	ms.store(key, data)
+	return nil
+}
+
+// ReadRevision reads a key with its revision
+func (ms *MemoryStorage) ReadRevision(key string) ([]byte, string, error) {
+	data, err := ms.Read(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, ContentRevision(data), nil
+}
+
+// WriteIfRevision writes a key if it is still at revision
+func (ms *MemoryStorage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	current, exists := ms.data[key]
+	if err := checkRevision(key, current, exists, revision); err != nil {
+		return "", err
+	}
+
+	ms.store(key, data)
+	return ContentRevision(data), nil
+}
+
+// store writes a key and notifies watchers; the caller holds ms.mu
+func (ms *MemoryStorage) store(key string, data []byte) {
 	// Store copy to prevent external modification
 	stored := make([]byte, len(data))
 	copy(stored, data)
@@ -244,11 +324,11 @@ func (ms *MemoryStorage) Write(key string, data []byte) error {
 	// Notify watchers
 	if handlers, ok := ms.watchers[key]; ok {
 		for _, handler := range handlers {
-			go handler(stored)
+			if handler != nil {
+				go handler(stored)
+			}
 		}
 	}
-
-	return nil
 }
 
 // Delete removes data from memory
@@ -261,6 +341,13 @@ func (ms *MemoryStorage) Delete(key string) error {
 	}
 
 	delete(ms.data, key)
+
+	// Notify watchers
+	for _, handler := range ms.watchers[key] {
+		if handler != nil {
+			go handler(nil)
+		}
+	}
 	return nil
 }
 
@@ -285,26 +372,28 @@ func (ms *MemoryStorage) Watch(key string, handler func([]byte)) (func(), error)
 	defer ms.mu.Unlock()
 
 	ms.watchers[key] = append(ms.watchers[key], handler)
+	index := len(ms.watchers[key]) - 1
 
-	// Return unsubscribe function
+	var once sync.Once
 	return func() {
-		ms.mu.Lock()
-		defer ms.mu.Unlock()
-
-		if handlers, ok := ms.watchers[key]; ok {
-			// Remove this handler
-			for i, h := range handlers {
-				if &h == &handler {
-					ms.watchers[key] = append(handlers[:i], handlers[i+1:]...)
-					break
-				}
+		once.Do(func() {
+			ms.mu.Lock()
+			defer ms.mu.Unlock()
+
+			// Keep indices stable for other unsubscribe functions
+			handlers := ms.watchers[key]
+			if index < len(handlers) {
+				handlers[index] = nil
 			}
 
 			// Clean up if no more handlers
-			if len(ms.watchers[key]) == 0 {
-				delete(ms.watchers, key)
+			for _, h := range handlers {
+				if h != nil {
+					return
+				}
 			}
-		}
+			delete(ms.watchers, key)
+		})
 	}, nil
 }
 
@@ -336,6 +425,9 @@ func (StorageKeys) BackupList() string {
 	return "backups:list"
 }
 
+// ServerCache and ProjectCache hold the engine's server and project
+// listings when its storage is cached, stamped with the config revision
+// they were built from
 func (StorageKeys) ServerCache() string {
 	return "cache:servers:list"
 }
@@ -346,10 +438,24 @@ func (StorageKeys) ProjectCache(path string) string {
 	return fmt.Sprintf("cache:projects:%s", sanitized)
 }
 
+// IsCache reports whether key holds cached data that can be rebuilt, such
+// as ServerCache and ProjectCache, so backends may expire it
+func (StorageKeys) IsCache(key string) bool {
+	return strings.HasPrefix(key, "cache:")
+}
+
 func (StorageKeys) AutoSyncState() string {
 	return "state:autosync:status"
 }
 
+func (StorageKeys) SchedulerState() string {
+	return "state:scheduler:jobs"
+}
+
+func (StorageKeys) MigrationState() string {
+	return "state:migration:progress"
+}
+
 func (StorageKeys) LastSync(target string) string {
 	return fmt.Sprintf("state:sync:%s:last", target)
 }
@@ -386,8 +492,22 @@ func CopyKey(storage Storage, srcKey, dstKey string) error {
 	return storage.Write(dstKey, data)
 }
 
-// ExportStorage exports all data to a writer
+// ExportStorage exports all data to a writer. Values of an EncryptedStorage
+// are exported sealed; use ExportStorageDecrypted for plaintext.
 func ExportStorage(storage Storage, w io.Writer) error {
+	if encrypted, ok := storage.(*EncryptedStorage); ok {
+		storage = encrypted.Backend()
+	}
+	return exportStorage(storage, w)
+}
+
+// ExportStorageDecrypted exports all data to a writer, decrypting the values
+// of an EncryptedStorage
+func ExportStorageDecrypted(storage Storage, w io.Writer) error {
+	return exportStorage(storage, w)
+}
+
+func exportStorage(storage Storage, w io.Writer) error {
 	keys, err := storage.List("")
 	if err != nil {
 		return err
@@ -395,12 +515,23 @@ func ExportStorage(storage Storage, w io.Writer) error {
 
 	export := make(map[string]json.RawMessage)
 
-	for _, key := range keys {
-		data, err := storage.Read(key)
+	// Read in batches when the backend supports it
+	if batch, ok := storage.(BatchReader); ok {
+		values, err := batch.ReadMany(keys)
 		if err != nil {
-			continue // Skip errors
+			return err
+		}
+		for key, data := range values {
+			export[key] = json.RawMessage(data)
+		}
+	} else {
+		for _, key := range keys {
+			data, err := storage.Read(key)
+			if err != nil {
+				continue // Skip errors
+			}
+			export[key] = json.RawMessage(data)
 		}
-		export[key] = json.RawMessage(data)
 	}
 
 	encoder := json.NewEncoder(w)
@@ -408,7 +539,8 @@ func ExportStorage(storage Storage, w io.Writer) error {
 	return encoder.Encode(export)
 }
 
-// ImportStorage imports data from a reader
+// ImportStorage imports data from a reader. Sealed values imported into an
+// EncryptedStorage are kept as they are, and must be readable with its key.
 func ImportStorage(storage Storage, r io.Reader) error {
 	var data map[string]json.RawMessage
 
@@ -417,8 +549,18 @@ func ImportStorage(storage Storage, r io.Reader) error {
 		return err
 	}
 
+	encrypted, _ := storage.(*EncryptedStorage)
 	for key, value := range data {
-		if err := storage.Write(key, []byte(value)); err != nil {
+		target := storage
+		if encrypted != nil {
+			if _, ok := parseSealed(value); ok {
+				if _, err := encrypted.decrypt(key, value); err != nil {
+					return fmt.Errorf("failed to import key %s: %w", key, err)
+				}
+				target = encrypted.Backend()
+			}
+		}
+		if err := target.Write(key, []byte(value)); err != nil {
 			return fmt.Errorf("failed to import key %s: %w", key, err)
 		}
 	}
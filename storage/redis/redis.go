@@ -3,120 +3,500 @@ package redis
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
+	engine "github.com/b-open-io/agent-master-engine"
 	"github.com/redis/go-redis/v9"
 )
 
-// Storage implements the engine.Storage interface using Redis
+// Change subscription timing
+const (
+	subscribeTimeout = 5 * time.Second // Wait for Redis to confirm the subscription
+	reconnectDelay   = time.Second     // Wait before retrying a lost connection
+)
+
+// Batch sizes
+const (
+	DefaultScanCount = 100 // Keys per SCAN call when listing
+	readBatchSize    = 500 // Keys per pipeline in ReadMany
+)
+
+// Options configure a Storage
+type Options struct {
+	Prefix    string        // Prepended to every key
+	Namespace string        // Separates users sharing one Redis, such as a user name
+	Timeout   time.Duration // Limit for each call made without a context, 0 for none
+	CacheTTL  time.Duration // Expiry of cache keys (see engine.Keys.IsCache), 0 for none
+	ScanCount int64         // Keys per SCAN call, defaults to DefaultScanCount
+}
+
+// Storage implements the engine.Storage interface using Redis. Every write
+// is announced on a pub/sub channel, so Watch handlers see writes from all
+// instances sharing the prefix and namespace, not just this one.
 type Storage struct {
 	client   *redis.Client
 	ctx      context.Context
-	prefix   string
+	cancel   context.CancelFunc
+	options  Options
+	id       string // Identifies this instance's change messages
 	watchers map[string][]func([]byte)
+	seen     map[string]string // Last revision handlers saw per watched key
+	pubsub   *redis.PubSub
+	subMu    sync.Mutex // Guards pubsub; held while waiting for Redis, so never with mu
 	mu       sync.RWMutex
 }
 
+// changeMessage is published after every write or delete
+type changeMessage struct {
+	Key    string `json:"key"`
+	Source string `json:"source"`
+}
+
 // New creates a new Redis-based storage
 func New(client *redis.Client, prefix string) *Storage {
+	return NewWithOptions(client, Options{Prefix: prefix})
+}
+
+// NewWithOptions creates a Redis-based storage with namespacing, timeouts
+// and cache expiry
+func NewWithOptions(client *redis.Client, options Options) *Storage {
+	if options.ScanCount <= 0 {
+		options.ScanCount = DefaultScanCount
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Storage{
 		client:   client,
-		ctx:      context.Background(),
-		prefix:   prefix,
+		ctx:      ctx,
+		cancel:   cancel,
+		options:  options,
+		id:       newInstanceID(),
 		watchers: make(map[string][]func([]byte)),
+		seen:     make(map[string]string),
+	}
+}
+
+// newInstanceID returns a random ID for change messages
+func newInstanceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// base is the part of every Redis key before the storage key. Namespaces
+// are joined with "/" so they never match another namespace's keys.
+func (s *Storage) base() string {
+	if s.options.Namespace != "" {
+		return s.options.Prefix + "/" + s.options.Namespace
 	}
+	return s.options.Prefix
+}
+
+// fullKey returns the Redis key for a storage key
+func (s *Storage) fullKey(key string) string {
+	return s.base() + ":" + key
+}
+
+// ttl returns the expiry for a key, 0 for none
+func (s *Storage) ttl(key string) time.Duration {
+	if engine.Keys.IsCache(key) {
+		return s.options.CacheTTL
+	}
+	return 0
+}
+
+// callContext returns the context for a call made without one
+func (s *Storage) callContext() (context.Context, context.CancelFunc) {
+	if s.options.Timeout > 0 {
+		return context.WithTimeout(s.ctx, s.options.Timeout)
+	}
+	return context.WithCancel(s.ctx)
 }
 
 // Read reads data from Redis
 func (s *Storage) Read(key string) ([]byte, error) {
-	fullKey := s.prefix + ":" + key
-	data, err := s.client.Get(s.ctx, fullKey).Bytes()
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.ReadContext(ctx, key)
+}
+
+// ReadContext reads data from Redis
+func (s *Storage) ReadContext(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.Get(ctx, s.fullKey(key)).Bytes()
 	if err == redis.Nil {
 		return nil, fmt.Errorf("key not found: %s", key)
 	}
 	return data, err
 }
 
+// ReadMany reads several keys with pipelined GETs. Missing keys are left out
+// of the result.
+func (s *Storage) ReadMany(keys []string) (map[string][]byte, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.ReadManyContext(ctx, keys)
+}
+
+// ReadManyContext reads several keys with pipelined GETs
+func (s *Storage) ReadManyContext(ctx context.Context, keys []string) (map[string][]byte, error) {
+	results := make(map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += readBatchSize {
+		batch := keys[start:min(start+readBatchSize, len(keys))]
+		cmds := make([]*redis.StringCmd, len(batch))
+		_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range batch {
+				cmds[i] = pipe.Get(ctx, s.fullKey(key))
+			}
+			return nil
+		})
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+
+		for i, cmd := range cmds {
+			data, err := cmd.Bytes()
+			if err == redis.Nil {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			results[batch[i]] = data
+		}
+	}
+	return results, nil
+}
+
 // Write writes data to Redis
 func (s *Storage) Write(key string, data []byte) error {
-	fullKey := s.prefix + ":" + key
-	err := s.client.Set(s.ctx, fullKey, data, 0).Err()
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.WriteContext(ctx, key, data)
+}
+
+// WriteContext writes data to Redis. Cache keys expire after CacheTTL.
+func (s *Storage) WriteContext(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.fullKey(key), data, s.ttl(key))
+		s.publish(ctx, pipe, key)
+		return nil
+	})
 	
 	// Notify watchers
 	if err == nil {
-		s.mu.RLock()
-		if handlers, ok := s.watchers[key]; ok {
-			for _, handler := range handlers {
+		s.notify(key, data)
+	}
+	
+	return err
+}
+
+// notify calls the handlers watching key
+func (s *Storage) notify(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if handlers, ok := s.watchers[key]; ok {
+		s.seen[key] = ""
+		if data != nil {
+			s.seen[key] = engine.ContentRevision(data)
+		}
+		for _, handler := range handlers {
+			if handler != nil {
 				go handler(data)
 			}
 		}
-		s.mu.RUnlock()
 	}
-	
-	return err
+}
+
+// publish queues the change message for key on pipe
+func (s *Storage) publish(ctx context.Context, pipe redis.Pipeliner, key string) {
+	message, _ := json.Marshal(changeMessage{Key: key, Source: s.id})
+	pipe.Publish(ctx, s.channel(), message)
+}
+
+// channel is the pub/sub channel announcing changes under the prefix
+func (s *Storage) channel() string {
+	return s.base() + ":__changes"
+}
+
+// ReadRevision reads a key with its revision
+func (s *Storage) ReadRevision(key string) ([]byte, string, error) {
+	data, err := s.Read(key)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, engine.ContentRevision(data), nil
+}
+
+// WriteIfRevision writes a key if it is still at revision
+func (s *Storage) WriteIfRevision(key string, data []byte, revision string) (string, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.WriteIfRevisionContext(ctx, key, data, revision)
+}
+
+// WriteIfRevisionContext writes a key if it is still at revision. The key is
+// watched from the check until the write commits, so a write by another
+// client in between fails with a conflict too.
+func (s *Storage) WriteIfRevisionContext(ctx context.Context, key string, data []byte, revision string) (string, error) {
+	fullKey := s.fullKey(key)
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		actual, err := s.revision(ctx, tx, fullKey)
+		if err != nil {
+			return err
+		}
+		if actual != revision {
+			return &engine.ConflictError{Key: key, Expected: revision, Actual: actual}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, fullKey, data, s.ttl(key))
+			s.publish(ctx, pipe, key)
+			return nil
+		})
+		return err
+	}, fullKey)
+	if err == redis.TxFailedErr {
+		actual, _ := s.revision(ctx, s.client, fullKey)
+		return "", &engine.ConflictError{Key: key, Expected: revision, Actual: actual}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.notify(key, data)
+	return engine.ContentRevision(data), nil
+}
+
+// revision returns the revision of a full key, or "" if it doesn't exist
+func (s *Storage) revision(ctx context.Context, client redis.Cmdable, fullKey string) (string, error) {
+	current, err := client.Get(ctx, fullKey).Bytes()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return engine.ContentRevision(current), nil
 }
 
 // Delete removes data from Redis
 func (s *Storage) Delete(key string) error {
-	fullKey := s.prefix + ":" + key
-	result := s.client.Del(s.ctx, fullKey)
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.DeleteContext(ctx, key)
+}
+
+// DeleteContext removes data from Redis
+func (s *Storage) DeleteContext(ctx context.Context, key string) error {
+	var result *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		result = pipe.Del(ctx, s.fullKey(key))
+		s.publish(ctx, pipe, key)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 	if result.Val() == 0 {
 		return fmt.Errorf("key not found: %s", key)
 	}
-	return result.Err()
+	s.notify(key, nil)
+	return nil
 }
 
 // List lists keys with given prefix
 func (s *Storage) List(prefix string) ([]string, error) {
-	pattern := s.prefix + ":" + prefix + "*"
-	keys, err := s.client.Keys(s.ctx, pattern).Result()
-	if err != nil {
-		return nil, err
-	}
+	ctx, cancel := s.callContext()
+	defer cancel()
+	return s.ListContext(ctx, prefix)
+}
 
-	// Strip the storage prefix
-	results := make([]string, 0, len(keys))
-	prefixLen := len(s.prefix) + 1
-	for _, key := range keys {
-		if len(key) > prefixLen {
-			results = append(results, key[prefixLen:])
+// ListContext lists keys with given prefix, sorted. It uses SCAN, so Redis
+// isn't blocked while walking a large keyspace.
+func (s *Storage) ListContext(ctx context.Context, prefix string) ([]string, error) {
+	base := s.base() + ":"
+	pattern := escapePattern(base+prefix) + "*"
+
+	// SCAN may return a key more than once
+	seen := make(map[string]bool)
+	iter := s.client.Scan(ctx, 0, pattern, s.options.ScanCount).Iterator()
+	for iter.Next(ctx) {
+		if key := strings.TrimPrefix(iter.Val(), base); key != "" {
+			seen[key] = true
 		}
 	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
 
+	results := make([]string, 0, len(seen))
+	for key := range seen {
+		results = append(results, key)
+	}
+	sort.Strings(results)
 	return results, nil
 }
 
-// Watch watches for changes to a key
+// escapePattern escapes glob characters for SCAN's MATCH
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Watch watches for changes to a key, made by this or any other instance.
+// It fails if the subscription to other instances' changes can't be made.
 func (s *Storage) Watch(key string, handler func([]byte)) (func(), error) {
+	if err := s.subscribe(); err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", key, err)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
-
 	s.watchers[key] = append(s.watchers[key], handler)
+	index := len(s.watchers[key]) - 1
 
-	// Return unsubscribe function
+	var once sync.Once
 	return func() {
-		s.mu.Lock()
-		defer s.mu.Unlock()
-
-		if handlers, ok := s.watchers[key]; ok {
-			// Remove this handler
-			for i, h := range handlers {
-				if &h == &handler {
-					s.watchers[key] = append(handlers[:i], handlers[i+1:]...)
-					break
-				}
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			// Keep indices stable for other unsubscribe functions
+			handlers := s.watchers[key]
+			if index < len(handlers) {
+				handlers[index] = nil
 			}
 
 			// Clean up if no more handlers
-			if len(s.watchers[key]) == 0 {
-				delete(s.watchers, key)
+			for _, h := range handlers {
+				if h != nil {
+					return
+				}
 			}
-		}
+			delete(s.watchers, key)
+			delete(s.seen, key)
+		})
 	}, nil
 }
 
-// Close closes the Redis connection
+// subscribe starts listening for change messages unless already listening.
+// A failed subscription is retried by the next Watch.
+func (s *Storage) subscribe() error {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	if s.pubsub != nil {
+		return nil
+	}
+	if err := s.ctx.Err(); err != nil {
+		return fmt.Errorf("storage is closed")
+	}
+
+	// Wait for the confirmation so writes right after Watch are seen
+	pubsub := s.client.Subscribe(s.ctx, s.channel())
+	if _, err := pubsub.ReceiveTimeout(s.ctx, subscribeTimeout); err != nil {
+		pubsub.Close()
+		return err
+	}
+	s.pubsub = pubsub
+	go s.receive(pubsub)
+	return nil
+}
+
+// receive dispatches change messages from other instances until the storage
+// is closed. go-redis reconnects and resubscribes after connection errors;
+// changes missed in between are caught up once the subscription is back.
+func (s *Storage) receive(pubsub *redis.PubSub) {
+	for {
+		msg, err := pubsub.Receive(s.ctx)
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			select {
+			case <-time.After(reconnectDelay):
+			case <-s.ctx.Done():
+				return
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			// Resubscribed after a reconnect
+			if msg.Kind == "subscribe" {
+				s.catchUp()
+			}
+		case *redis.Message:
+			var change changeMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil || change.Source == s.id {
+				continue // Our own writes notify watchers directly
+			}
+			s.refresh(change.Key)
+		}
+	}
+}
+
+// refresh reads a watched key and notifies its handlers if it changed since
+// they were last notified. Handlers get nil for keys deleted since they last
+// saw them exist.
+func (s *Storage) refresh(key string) {
+	s.mu.RLock()
+	_, watched := s.watchers[key]
+	seen := s.seen[key]
+	s.mu.RUnlock()
+	if !watched {
+		return
+	}
+
+	ctx, cancel := s.callContext()
+	defer cancel()
+	data, err := s.client.Get(ctx, s.fullKey(key)).Bytes()
+	switch {
+	case err == redis.Nil:
+		// Deleted by another instance
+		if seen != "" {
+			s.notify(key, nil)
+		}
+	case err == nil && engine.ContentRevision(data) != seen:
+		s.notify(key, data)
+	}
+}
+
+// catchUp refreshes every watched key after a reconnect
+func (s *Storage) catchUp() {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.watchers))
+	for key := range s.watchers {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+
+	for _, key := range keys {
+		s.refresh(key)
+	}
+}
+
+// Close stops watching for changes and closes the Redis connection
 func (s *Storage) Close() error {
+	s.cancel()
+	s.subMu.Lock()
+	if s.pubsub != nil {
+		s.pubsub.Close()
+	}
+	s.subMu.Unlock()
 	return s.client.Close()
 }
\ No newline at end of file
@@ -0,0 +1,294 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	engine "github.com/b-open-io/agent-master-engine"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestStorage connects a Storage to an in-process Redis
+func newTestStorage(t *testing.T, server *miniredis.Miniredis) *Storage {
+	t.Helper()
+	storage := New(redis.NewClient(&redis.Options{Addr: server.Addr()}), "agent-master")
+	t.Cleanup(func() { storage.Close() })
+	return storage
+}
+
+// expectChange waits for a watch notification
+func expectChange(t *testing.T, changes <-chan []byte, want string) {
+	t.Helper()
+	select {
+	case data := <-changes:
+		if string(data) != want {
+			t.Errorf("Expected %s, got %s", want, data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Expected a notification with %s", want)
+	}
+}
+
+func TestWatchAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	writer := newTestStorage(t, server)
+	watcher := newTestStorage(t, server)
+
+	changes := make(chan []byte, 10)
+	if _, err := watcher.Watch("config", func(data []byte) { changes <- data }); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Write("config", []byte(`{"v":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, changes, `{"v":1}`)
+
+	// Other keys don't notify
+	if err := writer.Write("other", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes made while disconnected are caught up after reconnecting
+	server.Close()
+	server.Set("agent-master:config", `{"v":2}`)
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, changes, `{"v":2}`)
+
+	if err := writer.Write("config", []byte(`{"v":3}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, changes, `{"v":3}`)
+
+	select {
+	case data := <-changes:
+		t.Errorf("Unexpected notification: %s", data)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatchReportsSubscribeFailures(t *testing.T) {
+	server := miniredis.RunT(t)
+	storage := newTestStorage(t, server)
+
+	// Without Redis there is no cross-instance watch to report
+	server.Close()
+	if _, err := storage.Watch("config", func([]byte) {}); err == nil {
+		t.Fatal("Expected Watch to fail while Redis is down")
+	}
+
+	// The next Watch subscribes once Redis is back
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+	changes := make(chan []byte, 10)
+	if _, err := storage.Watch("config", func(data []byte) { changes <- data }); err != nil {
+		t.Fatalf("Watch failed after Redis came back: %v", err)
+	}
+	writer := newTestStorage(t, server)
+	if err := writer.Write("config", []byte(`{"v":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, changes, `{"v":1}`)
+}
+
+func TestEngineReloadsAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	engineA, err := engine.NewEngine(engine.WithStorage(newTestStorage(t, server)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engineB, err := engine.NewEngine(engine.WithStorage(newTestStorage(t, server)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engineA.AddServer("shared", engine.ServerConfig{Transport: "stdio", Command: "shared"}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := engineB.GetServer("shared"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected engine B to reload the server added by engine A")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// B can now save on top of A's change
+	if err := engineB.AddServer("second", engine.ServerConfig{Transport: "stdio", Command: "second"}); err != nil {
+		t.Errorf("AddServer after reload failed: %v", err)
+	}
+}
+
+func TestNamespacesListingAndExport(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	alice := NewWithOptions(client, Options{Prefix: "team", Namespace: "alice", ScanCount: 10, CacheTTL: time.Minute})
+	bob := NewWithOptions(client, Options{Prefix: "team", Namespace: "bob"})
+
+	for i := 0; i < 50; i++ {
+		if err := alice.Write(fmt.Sprintf("backups:%02d:data", i), []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := alice.Write(engine.Keys.ServerCache(), []byte(`[]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := bob.Write("backups:00:data", []byte(`{"bob":true}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Listing scans only the namespace, in batches
+	keys, err := alice.List("backups:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 50 || keys[0] != "backups:00:data" {
+		t.Errorf("Expected alice's 50 backups, got %d: %v", len(keys), keys[:1])
+	}
+	if keys, _ := bob.List(""); len(keys) != 1 {
+		t.Errorf("Expected bob's key only, got %v", keys)
+	}
+
+	// Glob characters in prefixes are literal
+	if keys, _ := alice.List("backups:*"); len(keys) != 0 {
+		t.Errorf("Expected no keys for a literal *, got %v", keys)
+	}
+
+	// Only cache keys expire
+	if ttl := server.TTL("team/alice:" + engine.Keys.ServerCache()); ttl != time.Minute {
+		t.Errorf("Expected cache TTL of a minute, got %s", ttl)
+	}
+	if ttl := server.TTL("team/alice:backups:00:data"); ttl != 0 {
+		t.Errorf("Expected no TTL for backups, got %s", ttl)
+	}
+
+	// Exports read everything through pipelines
+	var buf bytes.Buffer
+	if err := engine.ExportStorage(bob, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var exported map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	var backup map[string]bool
+	json.Unmarshal(exported["backups:00:data"], &backup)
+	if !backup["bob"] || len(exported) != 1 {
+		t.Errorf("Unexpected export: %s", buf.String())
+	}
+
+	values, err := alice.ReadMany(append(keys, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 50 {
+		t.Errorf("Expected 50 values, got %d", len(values))
+	}
+
+	// Calls honor their context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := alice.ReadContext(ctx, "backups:00:data"); err == nil {
+		t.Error("Expected a canceled context to fail the read")
+	}
+}
+
+func TestCachingReducesRoundTrips(t *testing.T) {
+	server := miniredis.RunT(t)
+	storage := newTestStorage(t, server)
+	eng, err := engine.NewEngine(engine.WithStorage(storage), engine.WithCache(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := eng.CreateBackup(fmt.Sprintf("backup %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first listing reads every backup's metadata, later ones only scan
+	if _, err := eng.ListBackups(); err != nil {
+		t.Fatal(err)
+	}
+	before := server.CommandCount()
+	backups, err := eng.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 10 {
+		t.Fatalf("Expected 10 backups, got %d", len(backups))
+	}
+	if commands := server.CommandCount() - before; commands > 2 {
+		t.Errorf("Expected a cached listing to only scan, got %d commands", commands)
+	}
+
+	// Metadata changed by another instance is read again
+	other := newTestStorage(t, server)
+	info := *backups[0]
+	info.Description = "changed"
+	if err := engine.SaveJSON(other, "backup-meta:"+info.ID, info); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		backups, _ := eng.ListBackups()
+		if len(backups) > 0 && backups[0].Description == "changed" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the cache to pick up the change from another instance")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCachedListingsMakeNoRoundTrips(t *testing.T) {
+	server := miniredis.RunT(t)
+	eng, err := engine.NewEngine(engine.WithStorage(newTestStorage(t, server)), engine.WithCache(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer eng.Close()
+	if err := eng.AddServer("a", engine.ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := eng.RegisterProject(t.TempDir(), engine.ProjectConfig{Name: "p"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first listings are stored at the cache keys and read back into
+	// the cache; the daemon's ListServers and ListProjects RPCs then make
+	// no round trips until the config changes
+	list := func() {
+		t.Helper()
+		if servers, err := eng.ListServers(engine.ServerFilter{}); err != nil || len(servers) != 1 {
+			t.Fatalf("ListServers: %d servers, %v", len(servers), err)
+		}
+		if projects, err := eng.ListProjects(); err != nil || len(projects) != 1 {
+			t.Fatalf("ListProjects: %d projects, %v", len(projects), err)
+		}
+	}
+	list()
+	list()
+	if !server.Exists("agent-master:" + engine.Keys.ServerCache()) {
+		t.Errorf("Expected the server listing in Redis, got keys %v", server.Keys())
+	}
+	before := server.CommandCount()
+	for i := 0; i < 5; i++ {
+		list()
+	}
+	if commands := server.CommandCount() - before; commands != 0 {
+		t.Errorf("Expected cached listings to make no Redis commands, got %d", commands)
+	}
+}
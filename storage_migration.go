@@ -0,0 +1,403 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrationCheckpointInterval is how many keys are copied between saved
+// checkpoints
+const migrationCheckpointInterval = 50
+
+// maxVerifyPasses bounds how often keys that changed during a migration are
+// copied again before giving up
+const maxVerifyPasses = 3
+
+// MigrationOptions controls MigrateStorage
+type MigrationOptions struct {
+	// DryRun reports the keys that would be copied without writing
+	DryRun bool
+	// Restart ignores the checkpoint of an interrupted migration
+	Restart bool
+	// Switch makes the engine use the destination once it is verified.
+	// Only used by Engine.MigrateStorage.
+	Switch bool
+	// Prune deletes keys the destination has and the source doesn't, so it
+	// ends up a copy of the source. Without it they are only reported.
+	Prune bool
+	// Progress is called after each key
+	Progress func(MigrationProgress)
+}
+
+// MigrationProgress reports a migrated key
+type MigrationProgress struct {
+	Key    string
+	Action string // "copied", "unchanged", "skipped" (before the checkpoint), "extra", "removed", "would-copy" or "would-remove"
+	Done   int
+	Total  int
+}
+
+// MigrationResult summarizes a migration
+type MigrationResult struct {
+	Total     int
+	Copied    []string // Keys written, or that would be written on a dry run
+	Unchanged int      // Keys the destination already had
+	Extra     []string // Keys only the destination has, deleted when Pruned
+	Pruned    bool     // Extra keys were deleted
+	Resumed   bool     // Continued from an interrupted migration
+	Switched  bool
+	DryRun    bool
+	Duration  time.Duration
+}
+
+// VerificationError is returned when keys still differ between source and
+// destination after a migration, because they kept changing
+type VerificationError struct {
+	Keys []string
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("%d keys differ after migration: %s", len(e.Keys), strings.Join(e.Keys, ", "))
+}
+
+// migrationCheckpoint is the progress of a migration, kept in the
+// destination so an interrupted migration can resume
+type migrationCheckpoint struct {
+	LastKey   string    `json:"lastKey"`
+	Copied    []string  `json:"copied"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// MigrateStorage copies every key from src to dst and verifies the copies
+// byte for byte. The source can stay in use: keys changed during the copy
+// are copied again when verified. Progress is checkpointed in dst, so an
+// interrupted migration resumes where it stopped. Keys the source doesn't
+// have are reported, and deleted from the destination with options.Prune.
+//
+// Values of an EncryptedStorage source are copied sealed; wrap dst with the
+// same key to read them.
+func MigrateStorage(ctx context.Context, src, dst Storage, options MigrationOptions) (*MigrationResult, error) {
+	start := time.Now()
+	if encrypted, ok := src.(*EncryptedStorage); ok {
+		src = encrypted.Backend()
+		if encrypted, ok := dst.(*EncryptedStorage); ok {
+			dst = encrypted.Backend()
+		}
+	}
+
+	keys, err := src.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source keys: %w", err)
+	}
+	keys = migratableKeys(keys)
+
+	result := &MigrationResult{Total: len(keys), DryRun: options.DryRun}
+	var checkpoint migrationCheckpoint
+	if !options.Restart {
+		if err := LoadJSON(dst, Keys.MigrationState(), &checkpoint); err != nil && !isNotFoundError(err) {
+			return nil, fmt.Errorf("failed to read migration checkpoint: %w", err)
+		}
+		result.Resumed = checkpoint.LastKey != ""
+	}
+	if checkpoint.StartedAt.IsZero() {
+		checkpoint = migrationCheckpoint{StartedAt: start}
+	}
+
+	report := func(key, action string, done int) {
+		if options.Progress != nil {
+			options.Progress(MigrationProgress{Key: key, Action: action, Done: done, Total: len(keys)})
+		}
+	}
+
+	for i, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return result, saveCheckpoint(dst, &checkpoint, options, err)
+		}
+		if key <= checkpoint.LastKey {
+			// Copied before the interruption; verified below
+			report(key, "skipped", i+1)
+			continue
+		}
+
+		copied, err := copyKey(src, dst, key, options.DryRun)
+		if err != nil {
+			return result, saveCheckpoint(dst, &checkpoint, options, err)
+		}
+		action := "unchanged"
+		if copied {
+			result.Copied = append(result.Copied, key)
+			checkpoint.Copied = append(checkpoint.Copied, key)
+			action = "copied"
+			if options.DryRun {
+				action = "would-copy"
+			}
+		} else {
+			result.Unchanged++
+		}
+		checkpoint.LastKey = key
+		report(key, action, i+1)
+
+		if (i+1)%migrationCheckpointInterval == 0 {
+			if err := saveCheckpoint(dst, &checkpoint, options, nil); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if !options.DryRun {
+		if err := verifyMigration(ctx, src, dst, keys, checkpoint.Copied, result); err != nil {
+			return result, saveCheckpoint(dst, &checkpoint, options, err)
+		}
+	}
+
+	prune := options.Prune && !options.DryRun
+	extra, err := extraKeys(src, dst, prune)
+	for _, key := range extra {
+		action := "extra"
+		switch {
+		case prune:
+			action = "removed"
+		case options.Prune:
+			action = "would-remove"
+		}
+		report(key, action, len(keys))
+	}
+	result.Extra = extra
+	result.Pruned = prune && len(extra) > 0
+	if err != nil {
+		return result, saveCheckpoint(dst, &checkpoint, options, err)
+	}
+
+	if !options.DryRun {
+		if err := dst.Delete(Keys.MigrationState()); err != nil && !isNotFoundError(err) {
+			return result, fmt.Errorf("failed to remove migration checkpoint: %w", err)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// migratableKeys sorts keys for checkpointing, leaving out migration state
+func migratableKeys(keys []string) []string {
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key != Keys.MigrationState() {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// copyKey copies a key unless dst already has the same bytes, reporting
+// whether it was (or on a dry run would be) written
+func copyKey(src, dst Storage, key string, dryRun bool) (bool, error) {
+	data, err := src.Read(key)
+	if err != nil {
+		if isNotFoundError(err) {
+			return false, nil // Deleted since listing
+		}
+		return false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	if current, err := dst.Read(key); err == nil && bytes.Equal(current, data) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	if err := dst.Write(key, data); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// verifyMigration compares every key, copying again those changed since
+// they were copied. Keys deleted from the source after being copied are
+// deleted from the destination too.
+func verifyMigration(ctx context.Context, src, dst Storage, keys, copied []string, result *MigrationResult) error {
+	wasCopied := make(map[string]bool, len(copied))
+	for _, key := range copied {
+		wasCopied[key] = true
+	}
+
+	pending := keys
+	for pass := 0; pass < maxVerifyPasses && len(pending) > 0; pass++ {
+		var differing []string
+		for _, key := range pending {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			data, err := src.Read(key)
+			if isNotFoundError(err) {
+				if wasCopied[key] {
+					if err := dst.Delete(key); err != nil && !isNotFoundError(err) {
+						return fmt.Errorf("failed to delete %s: %w", key, err)
+					}
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", key, err)
+			}
+			if current, err := dst.Read(key); err == nil && bytes.Equal(current, data) {
+				continue
+			}
+
+			differing = append(differing, key)
+			if err := dst.Write(key, data); err != nil {
+				return fmt.Errorf("failed to write %s: %w", key, err)
+			}
+			if !wasCopied[key] {
+				wasCopied[key] = true
+				result.Copied = append(result.Copied, key)
+			}
+		}
+		pending = differing
+	}
+
+	// Copies made in the last pass are checked once more
+	var mismatched []string
+	for _, key := range pending {
+		data, err := src.Read(key)
+		if err != nil {
+			continue
+		}
+		if current, err := dst.Read(key); err != nil || !bytes.Equal(current, data) {
+			mismatched = append(mismatched, key)
+		}
+	}
+	if len(mismatched) > 0 {
+		return &VerificationError{Keys: mismatched}
+	}
+	return nil
+}
+
+// extraKeys returns the keys dst has and src doesn't, such as keys deleted
+// from the source while a migration was interrupted, left from an earlier
+// one or unrelated to the source. They are deleted from dst when prune is set.
+func extraKeys(src, dst Storage, prune bool) ([]string, error) {
+	srcKeys, err := src.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source keys: %w", err)
+	}
+	dstKeys, err := dst.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list destination keys: %w", err)
+	}
+
+	inSource := make(map[string]bool, len(srcKeys))
+	for _, key := range srcKeys {
+		inSource[key] = true
+	}
+
+	var extra []string
+	for _, key := range migratableKeys(dstKeys) {
+		if inSource[key] {
+			continue
+		}
+		// Check again in case the key was written since listing
+		if _, err := src.Read(key); !isNotFoundError(err) {
+			if err != nil {
+				return extra, fmt.Errorf("failed to read %s: %w", key, err)
+			}
+			continue
+		}
+		if prune {
+			if err := dst.Delete(key); err != nil && !isNotFoundError(err) {
+				return extra, fmt.Errorf("failed to delete %s: %w", key, err)
+			}
+		}
+		extra = append(extra, key)
+	}
+	return extra, nil
+}
+
+// saveCheckpoint records progress so the migration can resume, returning
+// cause if there is one
+func saveCheckpoint(dst Storage, checkpoint *migrationCheckpoint, options MigrationOptions, cause error) error {
+	if options.DryRun {
+		return cause
+	}
+	if err := SaveJSON(dst, Keys.MigrationState(), checkpoint); err != nil {
+		if cause != nil {
+			return cause
+		}
+		return fmt.Errorf("failed to save migration checkpoint: %w", err)
+	}
+	return cause
+}
+
+// MigrateStorage copies the engine's storage to dst while the engine keeps
+// running. With options.Switch the engine then uses dst: config changes
+// are held while writes made during the copy are caught up, so none are
+// lost, and the config is saved to dst from then on, even if it was loaded
+// from a config file.
+func (e *engineImpl) MigrateStorage(ctx context.Context, dst Storage, options MigrationOptions) (*MigrationResult, error) {
+	if dst == nil {
+		return nil, fmt.Errorf("destination storage is required")
+	}
+	src := e.getStorage()
+	if encrypted, ok := src.(*EncryptedStorage); ok {
+		if _, ok := dst.(*EncryptedStorage); !ok {
+			// Keep reading sealed values after the switch
+			encrypted.mu.RLock()
+			key := encrypted.key
+			encrypted.mu.RUnlock()
+			sealed, err := NewEncryptedStorage(dst, key)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt destination storage: %w", err)
+			}
+			dst = sealed
+		}
+	}
+
+	result, err := MigrateStorage(ctx, src, dst, options)
+	if err != nil || options.DryRun || !options.Switch {
+		return result, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	catchUp, err := MigrateStorage(ctx, src, dst, MigrationOptions{Prune: options.Prune})
+	if err != nil {
+		return result, fmt.Errorf("failed to catch up before switching: %w", err)
+	}
+	result.Copied = append(result.Copied, catchUp.Copied...)
+	result.Extra = catchUp.Extra
+	result.Pruned = result.Pruned || catchUp.Pruned
+	result.Total = catchUp.Total
+
+	e.storageMu.Lock()
+	e.storage = dst
+	e.storageMu.Unlock()
+
+	// Watch the new storage and take its config revision as ours
+	if e.unwatchConfig != nil {
+		e.unwatchConfig()
+		e.unwatchConfig = nil
+	}
+	if err := e.watchStoredConfig(); err != nil {
+		e.eventBus.emit(EventWarning, fmt.Sprintf("failed to watch config: %v", err))
+	}
+	e.configRevision = ""
+	if revisioned, ok := dst.(RevisionedStorage); ok {
+		if _, revision, err := revisioned.ReadRevision(Keys.Config()); err == nil {
+			e.configRevision = revision
+		}
+	}
+	e.configPath = ""
+	if err := e.saveConfigNoLock(); err != nil {
+		return result, fmt.Errorf("failed to save config to the new storage: %w", err)
+	}
+
+	result.Switched = true
+	result.Duration += catchUp.Duration
+	return result, nil
+}
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateStorage(t *testing.T) {
+	src, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := NewBoltStorage(filepath.Join(t.TempDir(), "agent-master.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+
+	for i := 0; i < 120; i++ {
+		if err := src.Write(fmt.Sprintf("projects:p%03d:config", i), []byte(fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A dry run reports every key without writing
+	result, err := MigrateStorage(context.Background(), src, dst, MigrationOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(result.Copied) != 120 {
+		t.Errorf("Expected 120 keys to copy, got %d", len(result.Copied))
+	}
+	if keys, _ := dst.List(""); len(keys) != 0 {
+		t.Errorf("Expected a dry run not to write, got %v", keys)
+	}
+
+	// An interrupted migration resumes from its checkpoint
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = MigrateStorage(ctx, src, dst, MigrationOptions{
+		Progress: func(progress MigrationProgress) {
+			if progress.Done == 60 {
+				cancel()
+			}
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the migration to be canceled, got %v", err)
+	}
+
+	var skipped int
+	result, err = MigrateStorage(context.Background(), src, dst, MigrationOptions{
+		Progress: func(progress MigrationProgress) {
+			if progress.Action == "skipped" {
+				skipped++
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Resumed migration failed: %v", err)
+	}
+	if !result.Resumed || skipped != 60 || len(result.Copied) != 60 {
+		t.Errorf("Expected to resume after 60 keys, got resumed=%v skipped=%d copied=%d", result.Resumed, skipped, len(result.Copied))
+	}
+
+	// Everything arrived byte for byte, without the checkpoint
+	keys, _ := dst.List("")
+	if len(keys) != 120 {
+		t.Errorf("Expected 120 keys, got %d", len(keys))
+	}
+	for _, key := range keys {
+		want, _ := src.Read(key)
+		got, _ := dst.Read(key)
+		if !bytes.Equal(want, got) {
+			t.Errorf("Key %s differs: %s != %s", key, got, want)
+		}
+	}
+
+	// Migrating again finds nothing to copy
+	result, err = MigrateStorage(context.Background(), src, dst, MigrationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Copied) != 0 || result.Unchanged != 120 {
+		t.Errorf("Expected all keys unchanged, got %+v", result)
+	}
+
+	// Keys the source doesn't have are reported, and deleted only with Prune
+	if err := src.Delete("projects:p000:config"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Write("stale", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"projects:p000:config", "stale"}
+	for _, options := range []MigrationOptions{{}, {DryRun: true, Prune: true}} {
+		result, err = MigrateStorage(context.Background(), src, dst, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(result.Extra, want) || result.Pruned {
+			t.Errorf("%+v: expected %v to be reported only, got %v, pruned=%v", options, want, result.Extra, result.Pruned)
+		}
+		if _, err := dst.Read("stale"); err != nil {
+			t.Errorf("%+v: expected extra keys to be kept: %v", options, err)
+		}
+	}
+	result, err = MigrateStorage(context.Background(), src, dst, MigrationOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Extra, want) || !result.Pruned {
+		t.Errorf("Expected %v to be pruned, got %v, pruned=%v", want, result.Extra, result.Pruned)
+	}
+	if keys, _ := dst.List(""); len(keys) != 119 {
+		t.Errorf("Expected 119 keys after pruning, got %d", len(keys))
+	}
+}
+
+func TestEngineMigrateStorage(t *testing.T) {
+	src := NewMemoryStorage()
+	engine, err := NewEngine(WithStorage(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.AddServer("a", ServerConfig{Transport: "stdio", Command: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	result, err := engine.MigrateStorage(context.Background(), dst, MigrationOptions{Switch: true})
+	if err != nil {
+		t.Fatalf("MigrateStorage failed: %v", err)
+	}
+	if !result.Switched {
+		t.Error("Expected the engine to switch storage")
+	}
+
+	// Changes after the switch go to the new storage only
+	if err := engine.AddServer("b", ServerConfig{Transport: "stdio", Command: "b"}); err != nil {
+		t.Fatalf("AddServer after switch failed: %v", err)
+	}
+	reopened, err := NewEngine(WithStorage(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		if _, err := reopened.GetServer(name); err != nil {
+			t.Errorf("Expected server %s in the new storage: %v", name, err)
+		}
+	}
+	old, err := NewEngine(WithStorage(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := old.GetServer("b"); err == nil {
+		t.Error("Expected the old storage not to see changes after the switch")
+	}
+}
@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long FileStorage waits after the last
+// filesystem event for a key before notifying its watchers
+const DefaultWatchDebounce = 100 * time.Millisecond
+
+// SetWatchDebounce sets how long Watch waits for a burst of filesystem
+// events on a key to settle
+func (fs *FileStorage) SetWatchDebounce(debounce time.Duration) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.debounce = debounce
+}
+
+// sharedWatcher is the fsnotify watcher of a base path, shared by every
+// FileStorage on it so that engines opened on the same directory don't use
+// up the process's inotify instances
+type sharedWatcher struct {
+	watcher  *fsnotify.Watcher
+	dirs     map[string]bool
+	storages map[*FileStorage]bool
+}
+
+var (
+	sharedWatchersMu sync.Mutex
+	sharedWatchers   = make(map[string]*sharedWatcher) // By base path
+)
+
+// Watch watches for changes to a key, whether written through this storage,
+// another process or by hand. The handler receives the new data, or nil when
+// the key is deleted.
+func (fs *FileStorage) Watch(key string, handler func([]byte)) (func(), error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	select {
+	case <-fs.stopChan:
+		return nil, fmt.Errorf("storage is closed")
+	default:
+	}
+	if err := fs.watchDir(filepath.Dir(fs.keyToPath(key))); err != nil {
+		if len(fs.watchers) == 0 {
+			fs.detachLocked()
+		}
+		return nil, err
+	}
+	if _, ok := fs.watchers[key]; !ok {
+		// Changes from before Watch are not reported
+		if data, err := os.ReadFile(fs.keyToPath(key)); err == nil {
+			fs.seen[key] = ContentRevision(data)
+		}
+	}
+	fs.watchers[key] = append(fs.watchers[key], handler)
+	index := len(fs.watchers[key]) - 1
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			fs.mu.Lock()
+			defer fs.mu.Unlock()
+
+			// Keep indices stable for other unsubscribe functions
+			handlers := fs.watchers[key]
+			if index < len(handlers) {
+				handlers[index] = nil
+			}
+
+			// Clean up if no more handlers
+			for _, h := range handlers {
+				if h != nil {
+					return
+				}
+			}
+			delete(fs.watchers, key)
+			delete(fs.seen, key)
+			if timer, ok := fs.timers[key]; ok {
+				timer.Stop()
+				delete(fs.timers, key)
+			}
+			if len(fs.watchers) == 0 {
+				fs.detachLocked()
+			}
+		})
+	}, nil
+}
+
+// Close stops watching the filesystem
+func (fs *FileStorage) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	select {
+	case <-fs.stopChan:
+		return nil
+	default:
+		close(fs.stopChan)
+	}
+	for key, timer := range fs.timers {
+		timer.Stop()
+		delete(fs.timers, key)
+	}
+	fs.watchers = make(map[string][]func([]byte))
+	fs.seen = make(map[string]string)
+	return fs.detachLocked()
+}
+
+// watchDir makes sure the base path's shared watcher watches a directory
+// and reports to this storage, starting the watcher on first use. Keys are
+// replaced by renaming a temp file over them, so their directory is watched
+// rather than the file. The caller holds fs.mu.
+func (fs *FileStorage) watchDir(dir string) error {
+	sharedWatchersMu.Lock()
+	defer sharedWatchersMu.Unlock()
+
+	shared, ok := sharedWatchers[fs.basePath]
+	if !ok {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return err
+		}
+		shared = &sharedWatcher{
+			watcher:  watcher,
+			dirs:     make(map[string]bool),
+			storages: make(map[*FileStorage]bool),
+		}
+		sharedWatchers[fs.basePath] = shared
+		go shared.loop(fs.basePath)
+	}
+	shared.storages[fs] = true
+
+	if shared.dirs[dir] {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := shared.watcher.Add(dir); err != nil {
+		return err
+	}
+	shared.dirs[dir] = true
+	return nil
+}
+
+// detachLocked stops this storage getting filesystem events, closing the
+// shared watcher when no other storage uses it. The caller holds fs.mu.
+func (fs *FileStorage) detachLocked() error {
+	sharedWatchersMu.Lock()
+	defer sharedWatchersMu.Unlock()
+
+	shared, ok := sharedWatchers[fs.basePath]
+	if !ok || !shared.storages[fs] {
+		return nil
+	}
+	delete(shared.storages, fs)
+	if len(shared.storages) > 0 {
+		return nil
+	}
+	delete(sharedWatchers, fs.basePath)
+	return shared.watcher.Close()
+}
+
+// loop turns filesystem events into debounced key changes of the storages
+// sharing the watcher, until the watcher is closed
+func (sw *sharedWatcher) loop(basePath string) {
+	for {
+		select {
+		case event, ok := <-sw.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || isSidecarFile(filepath.Base(event.Name)) {
+				continue
+			}
+			relPath, err := filepath.Rel(basePath, event.Name)
+			if err != nil {
+				continue
+			}
+
+			sharedWatchersMu.Lock()
+			storages := make([]*FileStorage, 0, len(sw.storages))
+			for fs := range sw.storages {
+				storages = append(storages, fs)
+			}
+			sharedWatchersMu.Unlock()
+
+			for _, fs := range storages {
+				fs.schedule(fs.pathToKey(relPath))
+			}
+		case _, ok := <-sw.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// schedule checks a watched key once its events have settled
+func (fs *FileStorage) schedule(key string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.watchers[key]; !ok {
+		return
+	}
+	if timer, ok := fs.timers[key]; ok {
+		timer.Stop()
+	}
+	fs.timers[key] = time.AfterFunc(fs.debounce, func() {
+		fs.refresh(key)
+	})
+}
+
+// refresh notifies the key's watchers if its content differs from what they
+// last saw. Writes through this storage have notified them already.
+func (fs *FileStorage) refresh(key string) {
+	data, err := os.ReadFile(fs.keyToPath(key))
+	if err != nil && !os.IsNotExist(err) {
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	delete(fs.timers, key)
+
+	if err != nil {
+		data = nil
+	}
+	fs.notifyLocked(key, data)
+}
+
+// notifyLocked calls the key's handlers with its new data, nil if deleted,
+// unless they have seen it already. The caller holds fs.mu.
+func (fs *FileStorage) notifyLocked(key string, data []byte) {
+	revision := ""
+	if data != nil {
+		revision = ContentRevision(data)
+	}
+	if seen, ok := fs.seen[key]; ok && seen == revision {
+		return
+	}
+	if data == nil && !fs.hasSeen(key) {
+		return
+	}
+
+	handlers, ok := fs.watchers[key]
+	if !ok {
+		return
+	}
+	fs.seen[key] = revision
+	for _, handler := range handlers {
+		if handler != nil {
+			go handler(data)
+		}
+	}
+}
+
+// hasSeen reports whether the key's watchers have seen it exist
+func (fs *FileStorage) hasSeen(key string) bool {
+	seen, ok := fs.seen[key]
+	return ok && seen != ""
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStorageWatch(t *testing.T) {
+	storageDir := t.TempDir()
+	storage, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	storage.SetWatchDebounce(20 * time.Millisecond)
+
+	changed := make(chan []byte, 10)
+	unwatch, err := storage.Watch("targets:claude", func(data []byte) { changed <- data })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(want []byte) {
+		t.Helper()
+		select {
+		case data := <-changed:
+			if string(data) != string(want) || (data == nil) != (want == nil) {
+				t.Errorf("Expected %q, got %q", want, data)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected a notification with %q", want)
+		}
+	}
+	expectNone := func() {
+		t.Helper()
+		select {
+		case data := <-changed:
+			t.Errorf("Unexpected notification with %q", data)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	// Our own writes notify once, not again for their filesystem events
+	if err := storage.Write("targets:claude", []byte(`{"a":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	expect([]byte(`{"a":1}`))
+	expectNone()
+
+	// A burst of edits by hand is debounced into the final content
+	path := filepath.Join(storageDir, "targets", "claude.json")
+	for _, content := range []string{`{"a":2}`, `{"a":3}`, `{"a":4}`} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect([]byte(`{"a":4}`))
+	expectNone()
+
+	// So is a write by another process, which renames over the file
+	other, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Write("targets:claude", []byte(`{"a":5}`)); err != nil {
+		t.Fatal(err)
+	}
+	expect([]byte(`{"a":5}`))
+
+	// Deletes are delivered as nil
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	expect(nil)
+
+	// Other keys and sidecar files are ignored
+	if err := storage.Write("targets:other", []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectNone()
+
+	unwatch()
+	if err := other.Write("targets:claude", []byte(`{"a":6}`)); err != nil {
+		t.Fatal(err)
+	}
+	expectNone()
+}
+
+func TestFileStorageSharedWatcher(t *testing.T) {
+	storageDir := t.TempDir()
+	first, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewFileStorage(storageDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.SetWatchDebounce(20 * time.Millisecond)
+	second.SetWatchDebounce(20 * time.Millisecond)
+
+	changed := make(chan string, 10)
+	unwatchFirst, err := first.Watch("config", func([]byte) { changed <- "first" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Watch("config", func([]byte) { changed <- "second" }); err != nil {
+		t.Fatal(err)
+	}
+
+	sharedWatchersMu.Lock()
+	shared := sharedWatchers[storageDir]
+	users := len(shared.storages)
+	sharedWatchersMu.Unlock()
+	if users != 2 {
+		t.Fatalf("Expected both storages to share one watcher, got %d users", users)
+	}
+
+	// Both are notified of a change made by hand
+	if err := os.WriteFile(filepath.Join(storageDir, "config.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for len(got) < 2 {
+		select {
+		case name := <-changed:
+			got[name] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected both storages to be notified, got %v", got)
+		}
+	}
+
+	// The last unwatch forgets the key and leaves the watcher to the other
+	unwatchFirst()
+	first.mu.RLock()
+	_, watched := first.watchers["config"]
+	_, seen := first.seen["config"]
+	first.mu.RUnlock()
+	if watched || seen {
+		t.Error("Expected the unwatched key to be forgotten")
+	}
+	sharedWatchersMu.Lock()
+	users = len(shared.storages)
+	sharedWatchersMu.Unlock()
+	if users != 1 {
+		t.Errorf("Expected one storage left on the watcher, got %d", users)
+	}
+
+	// Closing the last user closes the watcher
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sharedWatchersMu.Lock()
+	_, open := sharedWatchers[storageDir]
+	sharedWatchersMu.Unlock()
+	if open {
+		t.Error("Expected the watcher to be closed with its last storage")
+	}
+	if _, err := second.Watch("config", func([]byte) {}); err == nil {
+		t.Error("Expected Watch on a closed storage to fail")
+	}
+}
+
+func TestEngineCloseReleasesWatches(t *testing.T) {
+	storageDir := t.TempDir()
+	for i := 0; i < 200; i++ {
+		engine, err := NewEngine(WithFileStorage(storageDir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	sharedWatchersMu.Lock()
+	_, open := sharedWatchers[storageDir]
+	sharedWatchersMu.Unlock()
+	if open {
+		t.Error("Expected closed engines to release the watcher")
+	}
+}
@@ -53,7 +53,8 @@ func (sm *SyncManager) SyncToTarget(ctx context.Context, targetName string, opti
 	result.ConfigPath = configPath
 
 	// Create backup if requested
-	if options.BackupFirst && !options.DryRun {
+	options.normalize()
+	if options.CreateBackup && !options.DryRun {
 		backupPath, err := sm.createBackup(configPath)
 		if err != nil {
 			// Log but don't fail
@@ -340,18 +341,8 @@ func (sm *SyncManager) writeTargetConfig(path string, config interface{}) error
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	// Write atomically
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	if err := os.Rename(tmpPath, path); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename file: %w", err)
-	}
-
-	return nil
+	// Write atomically, keeping symlinks and permissions
+	return WriteFileAtomic(path, data, DefaultFileMode)
 }
 
 // createBackup creates a backup of the target config
@@ -378,7 +369,7 @@ func (sm *SyncManager) createBackup(configPath string) (string, error) {
 		filepath.Base(configPath), timestamp))
 
 	// Write backup
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(backupPath, data, DefaultFileMode); err != nil {
 		return "", err
 	}
 
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tomlServer is a server entry in a TOML [mcp_servers.<name>] table, as used
+// by Codex CLI
+type tomlServer struct {
+	Command     string            `toml:"command,omitempty"`
+	Args        []string          `toml:"args,omitempty"`
+	Env         map[string]string `toml:"env,omitempty"`
+	URL         string            `toml:"url,omitempty"`
+	HTTPHeaders map[string]string `toml:"http_headers,omitempty"`
+}
+
+// tomlTableHeader matches [table] and [[array]] header lines
+var tomlTableHeader = regexp.MustCompile(`^\s*\[\[?\s*([^\]]*?)\s*\]\]?\s*(#.*)?$`)
+
+// ParseMCPConfigTOML parses a TOML document with servers under mcp_servers
+// (Codex CLI), mcpServers or servers
+func ParseMCPConfigTOML(data []byte, substituteEnvVars bool) (*Config, error) {
+	var doc struct {
+		MCPServersSnake map[string]tomlServer `toml:"mcp_servers"`
+		MCPServers      map[string]tomlServer `toml:"mcpServers"`
+		Servers         map[string]tomlServer `toml:"servers"`
+	}
+	if _, err := toml.Decode(string(data), &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse TOML: %w", err)
+	}
+
+	servers := doc.MCPServersSnake
+	if servers == nil {
+		servers = doc.MCPServers
+	}
+	if servers == nil {
+		servers = doc.Servers
+	}
+
+	mcpConfig := &MCPConfig{MCPServers: make(map[string]ServerConfig, len(servers))}
+	for name, server := range servers {
+		config := ServerConfig{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.HTTPHeaders,
+		}
+		if config.Command != "" {
+			config.Transport = "stdio"
+		} else if config.URL != "" {
+			config.Transport = "sse"
+		}
+		mcpConfig.MCPServers[name] = config
+	}
+
+	return mcpConfig.ToConfigWithOptions(substituteEnvVars)
+}
+
+// MergeMCPServersTOML replaces the [mcp_servers.*] tables of a TOML document
+// with the given servers. The rest of the document, including comments, is
+// kept as is when possible; documents that define mcp_servers in a way that
+// can't be edited in place are re-encoded instead.
+func MergeMCPServersTOML(existing []byte, servers map[string]ServerConfig) ([]byte, error) {
+	var original map[string]interface{}
+	if _, err := toml.Decode(string(existing), &original); err != nil {
+		return nil, fmt.Errorf("failed to parse existing TOML: %w", err)
+	}
+	delete(original, "mcp_servers")
+
+	tables, err := encodeMCPServerTables(servers)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := strings.TrimRight(stripMCPServerTables(string(existing)), "\n")
+	var out strings.Builder
+	if rest != "" {
+		out.WriteString(rest)
+		out.WriteString("\n\n")
+	}
+	out.WriteString(tables)
+
+	// Verify that only mcp_servers changed, otherwise fall back to re-encoding
+	var merged map[string]interface{}
+	if _, err := toml.Decode(out.String(), &merged); err == nil {
+		written, _ := merged["mcp_servers"].(map[string]interface{})
+		delete(merged, "mcp_servers")
+		if len(written) == len(servers) && reflect.DeepEqual(original, merged) {
+			return []byte(out.String()), nil
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := toml.NewEncoder(&buf)
+	enc.Indent = ""
+	if len(original) > 0 {
+		if err := enc.Encode(original); err != nil {
+			return nil, fmt.Errorf("failed to encode TOML: %w", err)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(tables)
+	return buf.Bytes(), nil
+}
+
+// stripMCPServerTables removes [mcp_servers] tables and their sub-tables.
+// Comments directly above the table that follows them are kept.
+func stripMCPServerTables(doc string) string {
+	var kept, comments []string
+	skipping := false
+	for _, line := range strings.Split(doc, "\n") {
+		if m := tomlTableHeader.FindStringSubmatch(line); m != nil {
+			key := strings.ReplaceAll(m[1], `"`, "")
+			wasSkipping := skipping
+			skipping = key == "mcp_servers" || strings.HasPrefix(key, "mcp_servers.")
+			if wasSkipping && !skipping {
+				kept = append(kept, comments...)
+			}
+			comments = nil
+		}
+		if !skipping {
+			kept = append(kept, line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && len(comments) > 0) {
+			comments = append(comments, line)
+		} else {
+			comments = nil
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// encodeMCPServerTables renders one [mcp_servers.<name>] table per server
+func encodeMCPServerTables(servers map[string]ServerConfig) (string, error) {
+	var out strings.Builder
+	for i, name := range sortedServerNames(servers) {
+		server := servers[name]
+
+		var body bytes.Buffer
+		enc := toml.NewEncoder(&body)
+		enc.Indent = ""
+		if err := enc.Encode(map[string]interface{}{
+			"mcp_servers": map[string]tomlServer{
+				name: {
+					Command:     server.Command,
+					Args:        server.Args,
+					Env:         server.Env,
+					URL:         server.URL,
+					HTTPHeaders: server.Headers,
+				},
+			},
+		}); err != nil {
+			return "", fmt.Errorf("failed to encode server %q: %w", name, err)
+		}
+
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		// Drop the empty [mcp_servers] parent header the encoder emits
+		out.WriteString(strings.TrimPrefix(body.String(), "[mcp_servers]\n"))
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,140 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+)
+
+func TestMergeMCPServersTOML(t *testing.T) {
+	existing := `# Codex settings
+model = "o3"
+approval_policy = "on-request"
+
+[mcp_servers.old]
+command = "old-server"
+
+[mcp_servers.old.env]
+TOKEN = "x"
+
+# Profiles stay put
+[profiles.fast]
+model = "o4-mini"
+`
+
+	servers := map[string]ServerConfig{
+		"github": {
+			Transport: "stdio",
+			Command:   "npx",
+			Args:      []string{"-y", "@modelcontextprotocol/server-github"},
+			Env:       map[string]string{"GITHUB_TOKEN": "secret"},
+		},
+		"@scoped/name": {
+			Transport: "stdio",
+			Command:   "scoped",
+		},
+	}
+
+	merged, err := MergeMCPServersTOML([]byte(existing), servers)
+	if err != nil {
+		t.Fatalf("MergeMCPServersTOML failed: %v", err)
+	}
+	out := string(merged)
+
+	for _, want := range []string{"# Codex settings", "# Profiles stay put", `model = "o4-mini"`, "[mcp_servers.github]", `[mcp_servers."@scoped/name"]`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "old-server") {
+		t.Errorf("Expected old mcp_servers tables to be replaced:\n%s", out)
+	}
+
+	var doc map[string]interface{}
+	if _, err := toml.Decode(out, &doc); err != nil {
+		t.Fatalf("Merged output is not valid TOML: %v\n%s", err, out)
+	}
+	if doc["approval_policy"] != "on-request" {
+		t.Errorf("Expected top-level keys to be preserved, got %v", doc["approval_policy"])
+	}
+
+	// Parse it back
+	config, err := ParseMCPConfigTOML(merged, false)
+	if err != nil {
+		t.Fatalf("ParseMCPConfigTOML failed: %v", err)
+	}
+	if len(config.Servers) != 2 {
+		t.Fatalf("Expected 2 servers, got %d", len(config.Servers))
+	}
+	github := config.Servers["github"]
+	if github.Transport != "stdio" || github.Command != "npx" || len(github.Args) != 2 || github.Env["GITHUB_TOKEN"] != "secret" {
+		t.Errorf("Unexpected round-tripped server: %+v", github.ServerConfig)
+	}
+}
+
+func TestMergeMCPServersTOMLFallback(t *testing.T) {
+	// Inline mcp_servers can't be edited line by line, so the document is re-encoded
+	existing := `model = "o3"
+mcp_servers = { old = { command = "old-server" } }
+`
+	merged, err := MergeMCPServersTOML([]byte(existing), map[string]ServerConfig{
+		"new": {Transport: "stdio", Command: "new-server"},
+	})
+	if err != nil {
+		t.Fatalf("MergeMCPServersTOML failed: %v", err)
+	}
+
+	config, err := ParseMCPConfigTOML(merged, false)
+	if err != nil {
+		t.Fatalf("Merged output is not valid TOML: %v\n%s", err, merged)
+	}
+	if _, ok := config.Servers["old"]; ok {
+		t.Error("Expected old server to be removed")
+	}
+	if config.Servers["new"].Command != "new-server" {
+		t.Errorf("Expected new server, got %+v", config.Servers)
+	}
+	if !strings.Contains(string(merged), `model = "o3"`) {
+		t.Errorf("Expected other keys to survive re-encoding:\n%s", merged)
+	}
+
+	if _, err := MergeMCPServersTOML([]byte("not = [valid"), nil); err == nil {
+		t.Error("Expected error for invalid existing TOML")
+	}
+}
+
+func TestImportTOML(t *testing.T) {
+	e, err := NewEngine(WithMemoryStorage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`
+[mcp_servers.docs]
+command = "docs-server"
+args = ["--port", "8080"]
+
+[mcp_servers.remote]
+url = "https://example.com/mcp"
+`)
+	if err := e.Import(data, ImportFormatTOML, ImportOptions{}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	docs, err := e.GetServer("docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs.Command != "docs-server" || len(docs.Args) != 2 {
+		t.Errorf("Unexpected imported server: %+v", docs.ServerConfig)
+	}
+
+	remote, err := e.GetServer("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remote.Transport != "sse" || remote.URL != "https://example.com/mcp" {
+		t.Errorf("Unexpected imported remote server: %+v", remote.ServerConfig)
+	}
+}
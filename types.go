@@ -10,6 +10,9 @@ type Config struct {
 	Settings Settings                      `json:"settings,omitempty"`
 	Targets  map[string]TargetConfig       `json:"targets,omitempty"` // Legacy field
 	Metadata map[string]interface{}        `json:"metadata,omitempty"`
+
+	// Migrations lists the schema migrations applied to this config
+	Migrations []AppliedConfigMigration `json:"migrations,omitempty"`
 }
 
 // Settings contains global configuration settings
@@ -21,6 +24,7 @@ type Settings struct {
 	ConflictResolution ConflictSettings         `json:"conflictResolution,omitempty"`
 	ProjectScanning    ProjectScanSettings      `json:"projectScanning,omitempty"`
 	Validation         ValidationSettings       `json:"validation,omitempty"`
+	Schedule           ScheduleSettings         `json:"schedule,omitempty"`
 	DefaultTransport   string                   `json:"defaultTransport,omitempty"`
 	Projects           map[string]ProjectConfig `json:"projects,omitempty"`
 }
@@ -71,11 +75,44 @@ type BackupSettings struct {
 	Enabled     bool   `json:"enabled"`
 	MaxBackups  int    `json:"maxBackups,omitempty"`
 	BackupPath  string `json:"backupPath,omitempty"`
-	Location    string `json:"location,omitempty"` // Alternative to BackupPath
+	Location    string `json:"location,omitempty"` // Deprecated: use BackupPath
 	BeforeSync  bool   `json:"beforeSync,omitempty"`
 	Compression bool   `json:"compression,omitempty"`
 }
 
+// ScheduleSettings controls time-based jobs
+type ScheduleSettings struct {
+	Enabled bool           `json:"enabled"`
+	Jobs    []ScheduledJob `json:"jobs,omitempty"`
+}
+
+// ScheduledJob describes a job run on a cron-style schedule
+type ScheduledJob struct {
+	Name         string   `json:"name"`
+	Schedule     string   `json:"schedule"`               // Cron expression, e.g. "0 2 * * *" or "@hourly"
+	Action       string   `json:"action"`                 // "sync", "drift-check", "backup"
+	Destinations []string `json:"destinations,omitempty"` // Empty means all registered destinations
+	Disabled     bool     `json:"disabled,omitempty"`
+}
+
+// Scheduled job action constants
+const (
+	JobActionSync       = "sync"
+	JobActionDriftCheck = "drift-check"
+	JobActionBackup     = "backup"
+)
+
+// ScheduledJobStatus reports a job's schedule and last run
+type ScheduledJobStatus struct {
+	ScheduledJob
+	NextRun      time.Time     `json:"nextRun,omitempty"`
+	LastRun      time.Time     `json:"lastRun,omitempty"`
+	LastDuration time.Duration `json:"lastDuration,omitempty"`
+	LastResult   string        `json:"lastResult,omitempty"`
+	LastError    string        `json:"lastError,omitempty"`
+	RunCount     int           `json:"runCount"`
+}
+
 // SyncSettings controls synchronization behavior
 type SyncSettings struct {
 	Strategy           string        `json:"strategy,omitempty"`           // "merge", "replace", "selective"
@@ -100,13 +137,20 @@ type SyncOptions struct {
 	DryRun            bool              `json:"dryRun,omitempty"`
 	Force             bool              `json:"force,omitempty"`
 	CreateBackup      bool              `json:"createBackup,omitempty"`
-	BackupFirst       bool              `json:"backupFirst,omitempty"` // Alias for CreateBackup
+	BackupFirst       bool              `json:"backupFirst,omitempty"` // Deprecated: use CreateBackup
 	IncludeDisabled   bool              `json:"includeDisabled,omitempty"`
 	ServerFilter      []string          `json:"serverFilter,omitempty"`
 	DestinationConfig map[string]string `json:"destinationConfig,omitempty"`
 	Verbose           bool              `json:"verbose,omitempty"`
 }
 
+// normalize folds deprecated aliases into their canonical fields
+func (o *SyncOptions) normalize() {
+	if o.BackupFirst {
+		o.CreateBackup = true
+	}
+}
+
 // SyncError represents an error during sync
 type SyncError struct {
 	Error       string `json:"error"`
@@ -121,6 +165,7 @@ type SyncResult struct {
 	ServersAdded   int           `json:"serversAdded"`
 	ServersUpdated int           `json:"serversUpdated"`
 	ServersRemoved int           `json:"serversRemoved"`
+	ServersWritten int           `json:"serversWritten"`
 	Changes        []Change      `json:"changes,omitempty"`
 	Errors         []SyncError   `json:"errors,omitempty"`
 	BackupPath     string        `json:"backupPath,omitempty"`
@@ -148,9 +193,9 @@ const (
 // ImportOptions controls import behavior
 type ImportOptions struct {
 	Overwrite         bool     `json:"overwrite"`
-	OverwriteExisting bool     `json:"overwriteExisting"` // Alias for Overwrite
+	OverwriteExisting bool     `json:"overwriteExisting"` // Deprecated: use Overwrite
 	MergeStrategy     string   `json:"mergeStrategy"`     // "replace", "merge", "skip"
-	MergeMode         string   `json:"mergeMode"`         // Alias for MergeStrategy
+	MergeMode         string   `json:"mergeMode"`         // Deprecated: use MergeStrategy
 	ServerWhitelist   []string `json:"serverWhitelist,omitempty"`
 	ServerBlacklist   []string `json:"serverBlacklist,omitempty"`
 	ImportMetadata    bool     `json:"importMetadata"`
@@ -158,6 +203,16 @@ type ImportOptions struct {
 	SubstituteEnvVars bool     `json:"substituteEnvVars"` // Whether to replace ${ENV_VAR} patterns
 }
 
+// normalize folds deprecated aliases into their canonical fields
+func (o *ImportOptions) normalize() {
+	if o.OverwriteExisting {
+		o.Overwrite = true
+	}
+	if o.MergeStrategy == "" {
+		o.MergeStrategy = o.MergeMode
+	}
+}
+
 // ImportFormat represents supported import formats
 type ImportFormat string
 
@@ -199,6 +254,7 @@ type SyncPreview struct {
 	EstimatedTime  time.Duration `json:"estimatedTime"`
 	RequiresBackup bool          `json:"requiresBackup"`
 	HasConflicts   bool          `json:"hasConflicts"`
+	Errors         []SyncError   `json:"errors,omitempty"` // Servers the destination would skip
 }
 
 // MultiSyncResult aggregates multiple sync results
@@ -241,11 +297,29 @@ type ProjectInfo struct {
 
 // AutoSyncStatus represents auto-sync state
 type AutoSyncStatus struct {
-	Enabled       bool          `json:"enabled"`
-	Running       bool          `json:"running"`
-	LastSync      time.Time     `json:"lastSync,omitempty"`
-	NextSync      time.Time     `json:"nextSync,omitempty"`
-	WatchInterval time.Duration `json:"watchInterval"`
+	Enabled        bool                             `json:"enabled"`
+	Running        bool                             `json:"running"`
+	LastSync       time.Time                        `json:"lastSync,omitempty"`
+	NextSync       time.Time                        `json:"nextSync,omitempty"` // Set while a debounced sync is pending
+	WatchInterval  time.Duration                    `json:"watchInterval"`
+	WatchedPaths   []string                         `json:"watchedPaths,omitempty"`
+	EventsReceived int64                            `json:"eventsReceived"`
+	EventsIgnored  int64                            `json:"eventsIgnored"`
+	SyncsPerformed int64                            `json:"syncsPerformed"`
+	LastError      string                           `json:"lastError,omitempty"`
+	Destinations   map[string]DestinationSyncStatus `json:"destinations,omitempty"`
+}
+
+// DestinationSyncStatus tracks auto-sync activity for a single destination
+type DestinationSyncStatus struct {
+	Name            string    `json:"name"`
+	LastAttempt     time.Time `json:"lastAttempt,omitempty"`
+	LastSuccess     time.Time `json:"lastSuccess,omitempty"`
+	LastError       string    `json:"lastError,omitempty"`
+	PendingDebounce bool      `json:"pendingDebounce"`
+	ServersWritten  int       `json:"serversWritten"`
+	SyncCount       int       `json:"syncCount"`
+	FailureCount    int       `json:"failureCount"`
 }
 
 // BackupInfo contains backup details
@@ -254,7 +328,7 @@ type BackupInfo struct {
 	Path        string    `json:"path"`
 	Timestamp   time.Time `json:"timestamp"`
 	Size        int64     `json:"size"`
-	Type        string    `json:"type"` // "manual", "auto", "pre-sync"
+	Type        string    `json:"type"` // "manual", "auto", "pre-sync", "pre-migration"
 	Description string    `json:"description,omitempty"`
 }
 
@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -66,6 +67,14 @@ func (v *DefaultValidator) ValidateConfig(config ServerConfig) error {
 	return nil
 }
 
+// ValidateServerConfig validates both the name and the configuration
+func (v *DefaultValidator) ValidateServerConfig(name string, config ServerConfig) error {
+	if err := v.ValidateName(name); err != nil {
+		return err
+	}
+	return v.ValidateConfig(config)
+}
+
 // validateStdioConfig validates stdio transport specific fields
 func (v *DefaultValidator) validateStdioConfig(config ServerConfig) error {
 	// Command is required
@@ -186,6 +195,14 @@ func (p *PatternValidator) ValidateConfig(config ServerConfig) error {
 	return NewDefaultValidator().ValidateConfig(config)
 }
 
+// ValidateServerConfig validates both the name and the configuration
+func (p *PatternValidator) ValidateServerConfig(name string, config ServerConfig) error {
+	if err := p.ValidateName(name); err != nil {
+		return err
+	}
+	return p.ValidateConfig(config)
+}
+
 // ReplacementSanitizer sanitizes names by replacing characters
 type ReplacementSanitizer struct {
 	replacements map[string]string
@@ -292,3 +309,55 @@ func HandleDuplicateName(baseName string, existingNames map[string]bool, maxLeng
 
 	return name
 }
+
+// prepareForDestination applies a ValidatingDestination's sanitizer and
+// validator to the enabled servers of config. Names the destination rejects
+// are sanitized, and servers that are still invalid are left out and reported.
+func prepareForDestination(dest Destination, config *Config) (*Config, []SyncError) {
+	vd, ok := dest.(ValidatingDestination)
+	if !ok || config == nil {
+		return config, nil
+	}
+	validator, sanitizer := vd.Validator(), vd.Sanitizer()
+	if validator == nil && sanitizer == nil {
+		return config, nil
+	}
+
+	prepared := *config
+	prepared.Servers = make(map[string]ServerWithMetadata, len(config.Servers))
+
+	names := make([]string, 0, len(config.Servers))
+	for name, server := range config.Servers {
+		if server.Internal.Enabled {
+			names = append(names, name)
+		} else {
+			prepared.Servers[name] = server
+		}
+	}
+	sort.Strings(names)
+
+	var skipped []SyncError
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		server := config.Servers[name]
+
+		target := name
+		if sanitizer != nil && sanitizer.NeedsSanitization(name) {
+			target = HandleDuplicateName(sanitizer.Sanitize(name), used, 0)
+		}
+		if validator != nil {
+			if err := validator.ValidateServerConfig(target, server.ServerConfig); err != nil {
+				skipped = append(skipped, SyncError{
+					Error:       fmt.Sprintf("server %q skipped for %s: %v", name, dest.GetID(), err),
+					Recoverable: false,
+				})
+				continue
+			}
+		}
+
+		used[target] = true
+		prepared.Servers[target] = server
+	}
+
+	return &prepared, skipped
+}
@@ -0,0 +1,387 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Goose keeps MCP servers as entries of an "extensions" map in
+// ~/.config/goose/config.yaml:
+//
+//	extensions:
+//	  github:
+//	    name: github
+//	    type: stdio
+//	    cmd: npx
+//	    args: [-y, "@modelcontextprotocol/server-github"]
+//	    envs: {GITHUB_TOKEN: "..."}
+//	    enabled: true
+//
+// Continue keeps them as a "mcpServers" list in ~/.continue/config.yaml:
+//
+//	mcpServers:
+//	  - name: github
+//	    command: npx
+//	    args: [-y, "@modelcontextprotocol/server-github"]
+//	    env: {GITHUB_TOKEN: "..."}
+
+// gooseExtension is a Goose extension entry
+type gooseExtension struct {
+	Name    string            `yaml:"name,omitempty"`
+	Type    string            `yaml:"type,omitempty"`
+	Cmd     string            `yaml:"cmd,omitempty"`
+	Args    []string          `yaml:"args,omitempty"`
+	Envs    map[string]string `yaml:"envs,omitempty"`
+	URI     string            `yaml:"uri,omitempty"`
+	Enabled *bool             `yaml:"enabled,omitempty"`
+}
+
+// continueServer is a Continue mcpServers list entry
+type continueServer struct {
+	Name    string            `yaml:"name"`
+	Type    string            `yaml:"type,omitempty"`
+	Command string            `yaml:"command,omitempty"`
+	Args    []string          `yaml:"args,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	URL     string            `yaml:"url,omitempty"`
+}
+
+// gooseManagedTypes are the extension types the engine writes, one per
+// transport, plus the engine's own HTTP transport names that earlier
+// versions wrote; builtin and other extensions are left alone
+var gooseManagedTypes = map[string]bool{"stdio": true, "sse": true, "streamable_http": true, "http": true, "streamable-http": true}
+
+// gooseExtensionType returns the Goose extension type of a transport
+func gooseExtensionType(transport string) string {
+	switch transport {
+	case "":
+		return "stdio"
+	case "http", "streamable-http":
+		return "streamable_http"
+	default:
+		return transport
+	}
+}
+
+// gooseTransport returns the transport of a Goose extension type
+func gooseTransport(typ string) string {
+	switch typ {
+	case "streamable_http", "streamable-http":
+		return "http"
+	default:
+		return typ
+	}
+}
+
+// ParseMCPConfigYAML parses a YAML document in Goose, Continue or plain
+// mcpServers/servers map layout
+func ParseMCPConfigYAML(data []byte, substituteEnvVars bool) (*Config, error) {
+	var probe struct {
+		Extensions map[string]interface{} `yaml:"extensions"`
+		MCPServers interface{}            `yaml:"mcpServers"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	if probe.Extensions != nil {
+		return ParseGooseConfig(data, substituteEnvVars)
+	}
+	if _, ok := probe.MCPServers.([]interface{}); ok {
+		return ParseContinueConfig(data, substituteEnvVars)
+	}
+
+	// Same layouts as JSON, so convert and reuse the JSON parser
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	return ParseMCPConfigWithOptions(jsonData, substituteEnvVars)
+}
+
+// ParseGooseConfig parses the extensions of a Goose config.yaml. Builtin
+// extensions are skipped and disabled extensions are marked disabled.
+func ParseGooseConfig(data []byte, substituteEnvVars bool) (*Config, error) {
+	var doc struct {
+		Extensions map[string]gooseExtension `yaml:"extensions"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Goose config: %w", err)
+	}
+
+	mcpConfig := &MCPConfig{MCPServers: make(map[string]ServerConfig)}
+	var disabled []string
+	for name, ext := range doc.Extensions {
+		if !gooseManagedTypes[ext.Type] {
+			continue
+		}
+		mcpConfig.MCPServers[name] = ServerConfig{
+			Transport: gooseTransport(ext.Type),
+			Command:   ext.Cmd,
+			Args:      ext.Args,
+			Env:       ext.Envs,
+			URL:       ext.URI,
+		}
+		if ext.Enabled != nil && !*ext.Enabled {
+			disabled = append(disabled, name)
+		}
+	}
+
+	config, err := mcpConfig.ToConfigWithOptions(substituteEnvVars)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range disabled {
+		server := config.Servers[name]
+		server.Internal.Enabled = false
+		config.Servers[name] = server
+	}
+	return config, nil
+}
+
+// ParseContinueConfig parses the mcpServers list of a Continue config.yaml
+func ParseContinueConfig(data []byte, substituteEnvVars bool) (*Config, error) {
+	var doc struct {
+		MCPServers []continueServer `yaml:"mcpServers"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse Continue config: %w", err)
+	}
+
+	mcpConfig := &MCPConfig{MCPServers: make(map[string]ServerConfig, len(doc.MCPServers))}
+	for _, server := range doc.MCPServers {
+		transport := server.Type
+		if transport == "" {
+			transport = "stdio"
+			if server.Command == "" && server.URL != "" {
+				transport = "sse"
+			}
+		}
+		mcpConfig.MCPServers[server.Name] = ServerConfig{
+			Transport: transport,
+			Command:   server.Command,
+			Args:      server.Args,
+			Env:       server.Env,
+			URL:       server.URL,
+		}
+	}
+	return mcpConfig.ToConfigWithOptions(substituteEnvVars)
+}
+
+// MergeGooseExtensions writes servers as Goose extensions into an existing
+// config.yaml. Existing extensions of the types the engine writes that are
+// not in servers are removed; builtin extensions, unknown keys of updated
+// extensions and the rest of the document are kept.
+func MergeGooseExtensions(existing []byte, servers map[string]ServerConfig) ([]byte, error) {
+	root, doc, err := loadYAMLMapping(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	extensions := yamlMappingValue(root, "extensions")
+	if extensions == nil || extensions.Kind != yaml.MappingNode {
+		extensions = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		setYAMLMappingValue(root, "extensions", extensions)
+	}
+
+	// Drop managed extensions that are no longer configured
+	for i := 0; i < len(extensions.Content); i += 2 {
+		name := extensions.Content[i].Value
+		entry := extensions.Content[i+1]
+		if _, keep := servers[name]; keep {
+			continue
+		}
+		if typ := yamlMappingValue(entry, "type"); typ != nil && gooseManagedTypes[typ.Value] {
+			extensions.Content = append(extensions.Content[:i], extensions.Content[i+2:]...)
+			i -= 2
+		}
+	}
+
+	enabled := true
+	for _, name := range sortedServerNames(servers) {
+		server := servers[name]
+		ext := gooseExtension{
+			Name:    name,
+			Type:    gooseExtensionType(server.Transport),
+			Args:    server.Args,
+			Envs:    server.Env,
+			Enabled: &enabled,
+		}
+		if ext.Type == "stdio" {
+			ext.Cmd = server.Command
+		} else {
+			ext.URI = server.URL
+		}
+
+		entry := yamlMappingValue(extensions, name)
+		if entry == nil || entry.Kind != yaml.MappingNode {
+			entry = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			setYAMLMappingValue(extensions, name, entry)
+			// Goose expects a timeout on external extensions
+			setYAMLMappingValue(entry, "timeout", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: "300"})
+		}
+		if err := mergeYAMLFields(entry, ext, "name", "type", "cmd", "args", "envs", "uri", "enabled"); err != nil {
+			return nil, fmt.Errorf("failed to encode extension %q: %w", name, err)
+		}
+	}
+
+	return encodeYAML(doc)
+}
+
+// MergeContinueMCPServers replaces the mcpServers list of an existing Continue
+// config.yaml, keeping unknown keys of servers that are still configured and
+// the rest of the document
+func MergeContinueMCPServers(existing []byte, servers map[string]ServerConfig) ([]byte, error) {
+	root, doc, err := loadYAMLMapping(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[string]*yaml.Node)
+	if list := yamlMappingValue(root, "mcpServers"); list != nil && list.Kind == yaml.SequenceNode {
+		for _, entry := range list.Content {
+			if name := yamlMappingValue(entry, "name"); name != nil {
+				previous[name.Value] = entry
+			}
+		}
+	}
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, name := range sortedServerNames(servers) {
+		server := servers[name]
+		entry := continueServer{
+			Name: name,
+			Args: server.Args,
+			Env:  server.Env,
+		}
+		if server.Command != "" {
+			entry.Command = server.Command
+		} else {
+			entry.Type = server.Transport
+			entry.URL = server.URL
+		}
+
+		node, ok := previous[name]
+		if !ok {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		if err := mergeYAMLFields(node, entry, "name", "type", "command", "args", "env", "url"); err != nil {
+			return nil, fmt.Errorf("failed to encode server %q: %w", name, err)
+		}
+		list.Content = append(list.Content, node)
+	}
+	setYAMLMappingValue(root, "mcpServers", list)
+
+	return encodeYAML(doc)
+}
+
+// loadYAMLMapping parses a YAML document whose root is a mapping, creating an
+// empty one for empty input
+func loadYAMLMapping(data []byte) (root, doc *yaml.Node, err error) {
+	doc = &yaml.Node{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse existing YAML: %w", err)
+		}
+	}
+
+	if doc.Kind == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode}
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	root = doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("existing YAML is not a mapping")
+	}
+	return root, doc, nil
+}
+
+// mergeYAMLFields sets the managed keys of a mapping node from value, removing
+// managed keys that value leaves empty and keeping all other keys
+func mergeYAMLFields(node *yaml.Node, value interface{}, managed ...string) error {
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return err
+	}
+
+	for _, key := range managed {
+		if v := yamlMappingValue(&encoded, key); v != nil {
+			setYAMLMappingValue(node, key, v)
+		} else {
+			deleteYAMLMappingValue(node, key)
+		}
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value for key in a mapping node
+func yamlMappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setYAMLMappingValue replaces or appends key in a mapping node
+func setYAMLMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// deleteYAMLMappingValue removes key from a mapping node
+func deleteYAMLMappingValue(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// encodeYAML renders a document with two-space indentation
+func encodeYAML(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sortedServerNames returns server names in a stable order
+func sortedServerNames(servers map[string]ServerConfig) []string {
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,225 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeGooseExtensions(t *testing.T) {
+	existing := `GOOSE_PROVIDER: anthropic # keep me
+GOOSE_MODEL: claude-sonnet
+extensions:
+  developer:
+    bundled: true
+    enabled: true
+    name: developer
+    timeout: 300
+    type: builtin
+  github:
+    cmd: old-github
+    enabled: false
+    name: github
+    timeout: 600
+    type: stdio
+  stale:
+    cmd: stale
+    enabled: true
+    name: stale
+    type: stdio
+  stale-http:
+    enabled: true
+    name: stale-http
+    type: http
+    uri: http://localhost:9000/mcp
+  stale-streamable:
+    enabled: true
+    name: stale-streamable
+    type: streamable-http
+    uri: http://localhost:9001/mcp
+`
+
+	servers := map[string]ServerConfig{
+		"github": {
+			Transport: "stdio",
+			Command:   "npx",
+			Args:      []string{"-y", "@modelcontextprotocol/server-github"},
+			Env:       map[string]string{"GITHUB_TOKEN": "secret"},
+		},
+		"remote": {
+			Transport: "sse",
+			URL:       "http://localhost:8080/sse",
+		},
+		"streamable": {
+			Transport: "http",
+			URL:       "http://localhost:8081/mcp",
+		},
+	}
+
+	merged, err := MergeGooseExtensions([]byte(existing), servers)
+	if err != nil {
+		t.Fatalf("MergeGooseExtensions failed: %v", err)
+	}
+	out := string(merged)
+
+	if !strings.Contains(out, "# keep me") {
+		t.Errorf("Expected comments to be preserved:\n%s", out)
+	}
+
+	var doc struct {
+		Provider   string                            `yaml:"GOOSE_PROVIDER"`
+		Extensions map[string]map[string]interface{} `yaml:"extensions"`
+	}
+	if err := yaml.Unmarshal(merged, &doc); err != nil {
+		t.Fatalf("Merged output is not valid YAML: %v\n%s", err, out)
+	}
+	if doc.Provider != "anthropic" {
+		t.Errorf("Expected unrelated keys to be preserved, got %q", doc.Provider)
+	}
+	if _, ok := doc.Extensions["developer"]; !ok {
+		t.Error("Expected builtin extension to be preserved")
+	}
+	for _, name := range []string{"stale", "stale-http", "stale-streamable"} {
+		if _, ok := doc.Extensions[name]; ok {
+			t.Errorf("Expected stale extension %s to be removed", name)
+		}
+	}
+
+	github := doc.Extensions["github"]
+	if github["cmd"] != "npx" || github["enabled"] != true || github["timeout"] != 600 {
+		t.Errorf("Unexpected github extension: %v", github)
+	}
+	if remote := doc.Extensions["remote"]; remote["type"] != "sse" || remote["uri"] != "http://localhost:8080/sse" {
+		t.Errorf("Unexpected remote extension: %v", remote)
+	}
+	if streamable := doc.Extensions["streamable"]; streamable["type"] != "streamable_http" || streamable["uri"] != "http://localhost:8081/mcp" {
+		t.Errorf("Expected Goose's streamable_http type, got %v", streamable)
+	}
+
+	// Parse it back
+	config, err := ParseMCPConfigYAML(merged, false)
+	if err != nil {
+		t.Fatalf("ParseMCPConfigYAML failed: %v", err)
+	}
+	if len(config.Servers) != 3 {
+		t.Fatalf("Expected 3 servers (builtin skipped), got %d", len(config.Servers))
+	}
+	parsed := config.Servers["github"]
+	if parsed.Command != "npx" || len(parsed.Args) != 2 || parsed.Env["GITHUB_TOKEN"] != "secret" || !parsed.Internal.Enabled {
+		t.Errorf("Unexpected round-tripped server: %+v", parsed)
+	}
+	if streamable := config.Servers["streamable"]; streamable.Transport != "http" || streamable.URL != "http://localhost:8081/mcp" {
+		t.Errorf("Expected streamable_http to parse as http, got %+v", streamable)
+	}
+
+	// Merging the parsed servers again leaves the file as it was
+	roundTrip := make(map[string]ServerConfig, len(config.Servers))
+	for name, server := range config.Servers {
+		roundTrip[name] = server.ServerConfig
+	}
+	again, err := MergeGooseExtensions(merged, roundTrip)
+	if err != nil {
+		t.Fatalf("MergeGooseExtensions failed: %v", err)
+	}
+	if string(again) != out {
+		t.Errorf("Expected a round trip to keep the file, got:\n%s\nwant:\n%s", again, out)
+	}
+}
+
+func TestMergeContinueMCPServers(t *testing.T) {
+	existing := `name: Local Assistant
+version: 1.0.0
+schema: v1
+models:
+  - name: Claude
+    provider: anthropic
+mcpServers:
+  - name: sqlite
+    command: old-sqlite
+    cwd: /tmp
+  - name: removed
+    command: gone
+`
+
+	servers := map[string]ServerConfig{
+		"sqlite": {
+			Transport: "stdio",
+			Command:   "npx",
+			Args:      []string{"-y", "mcp-sqlite", "/tmp/test.db"},
+		},
+		"remote": {
+			Transport: "sse",
+			URL:       "https://example.com/sse",
+		},
+	}
+
+	merged, err := MergeContinueMCPServers([]byte(existing), servers)
+	if err != nil {
+		t.Fatalf("MergeContinueMCPServers failed: %v", err)
+	}
+
+	var doc struct {
+		Name       string                   `yaml:"name"`
+		Models     []map[string]interface{} `yaml:"models"`
+		MCPServers []map[string]interface{} `yaml:"mcpServers"`
+	}
+	if err := yaml.Unmarshal(merged, &doc); err != nil {
+		t.Fatalf("Merged output is not valid YAML: %v\n%s", err, merged)
+	}
+	if doc.Name != "Local Assistant" || len(doc.Models) != 1 {
+		t.Errorf("Expected unrelated keys to be preserved:\n%s", merged)
+	}
+	if len(doc.MCPServers) != 2 {
+		t.Fatalf("Expected 2 servers, got %d:\n%s", len(doc.MCPServers), merged)
+	}
+	for _, server := range doc.MCPServers {
+		switch server["name"] {
+		case "sqlite":
+			if server["command"] != "npx" || server["cwd"] != "/tmp" {
+				t.Errorf("Expected updated command and preserved cwd, got %v", server)
+			}
+		case "remote":
+			if server["type"] != "sse" || server["url"] != "https://example.com/sse" {
+				t.Errorf("Unexpected remote server: %v", server)
+			}
+		default:
+			t.Errorf("Unexpected server %v", server["name"])
+		}
+	}
+
+	config, err := ParseMCPConfigYAML(merged, false)
+	if err != nil {
+		t.Fatalf("ParseMCPConfigYAML failed: %v", err)
+	}
+	if config.Servers["remote"].Transport != "sse" || len(config.Servers["sqlite"].Args) != 3 {
+		t.Errorf("Unexpected round-tripped servers: %+v", config.Servers)
+	}
+}
+
+func TestMergeYAMLIntoEmptyFile(t *testing.T) {
+	servers := map[string]ServerConfig{"test": {Transport: "stdio", Command: "test"}}
+
+	for name, merge := range map[string]func([]byte, map[string]ServerConfig) ([]byte, error){
+		"goose":    MergeGooseExtensions,
+		"continue": MergeContinueMCPServers,
+	} {
+		t.Run(name, func(t *testing.T) {
+			merged, err := merge(nil, servers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, err := ParseMCPConfigYAML(merged, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.Servers["test"].Command != "test" {
+				t.Errorf("Expected test server, got:\n%s", merged)
+			}
+		})
+	}
+
+	if _, err := MergeGooseExtensions([]byte("- not\n- a mapping\n"), servers); err == nil {
+		t.Error("Expected error for non-mapping document")
+	}
+}